@@ -0,0 +1,69 @@
+// Command accrualsim runs a simulator of the accrual system for local
+// development:
+//
+//	accrualsim -a :8081 -progression REGISTERED,PROCESSING,PROCESSED
+//
+// Orders and reward rules are registered the same way as in the real
+// service, with POST /api/orders and POST /api/goods. Faults are injected
+// with POST /sim/faults, e.g. {"status": 429, "retry_after": 60, "limit": 10,
+// "count": 3} or {"delay": "10s"}, and removed with DELETE /sim/faults.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/accrualsim"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+func main() {
+	log := logger.NewLogger()
+
+	addr := flag.String("a", ":8081", "host and port http")
+	progression := flag.String("progression", "", "comma separated statuses played for new orders")
+	flag.Parse()
+	if env, ok := os.LookupEnv("RUN_ADDRESS"); ok {
+		*addr = env
+	}
+
+	sim := accrualsim.New()
+	if *progression != "" {
+		var statuses []accrualsim.Status
+		for _, status := range strings.Split(*progression, ",") {
+			statuses = append(statuses, accrualsim.Status(strings.TrimSpace(status)))
+		}
+		if err := sim.SetProgression(statuses...); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: middlewares.LoggerMiddleware(log)(sim.Handler()),
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+	log.Infow("accrual simulator started", "address", *addr)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	<-sig
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
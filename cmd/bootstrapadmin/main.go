@@ -0,0 +1,90 @@
+// Command bootstrapadmin creates the first admin of a fresh installation, or
+// gives the admin role to an existing user:
+//
+//	ADMIN_PASSWORD=... bootstrapadmin -d postgres://... -login admin
+//
+// The password is read from ADMIN_PASSWORD or, if it is not set, from the
+// first line of the standard input. It is ignored for an existing user.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/MxTrap/gophermart/config"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
+	"github.com/MxTrap/gophermart/internal/gophermart/migrator"
+	"github.com/MxTrap/gophermart/internal/gophermart/repository/postgres"
+	userrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/user"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/hasher"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/role"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+func main() {
+	ctx := context.Background()
+	log := logger.NewLogger()
+
+	login := flag.String("login", "admin", "login of the admin")
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	password, err := readPassword()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var errs validation.Errors
+	errs.Required("login", *login)
+	errs.NewPassword("password", password)
+	if len(errs) > 0 {
+		log.Fatal(errs)
+	}
+
+	postgresStorage, err := postgres.NewPostgresStorage(ctx, cfg.DatabaseDSN)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer postgresStorage.Stop()
+
+	mgrtr, err := migrator.NewMigrator(postgresStorage.Pool)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := mgrtr.InitializeDB(); err != nil {
+		log.Fatal(err)
+	}
+
+	passwordHasher, err := hasher.NewPasswordHasher(hasher.Argon2Params{
+		Memory:  cfg.Argon2Memory,
+		Time:    cfg.Argon2Time,
+		Threads: cfg.Argon2Threads,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	roleSvc := role.NewRoleService(log, userrepo.NewUserRepository(postgresStorage.Pool), passwordHasher)
+
+	created, err := roleSvc.BootstrapAdmin(ctx, *login, password)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !created {
+		log.Info("user already exists, the password has not been changed")
+	}
+}
+
+func readPassword() (string, error) {
+	if password, ok := os.LookupEnv("ADMIN_PASSWORD"); ok {
+		return password, nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -2,26 +2,93 @@ package config
 
 import (
 	"flag"
+	"time"
 
 	"github.com/caarlos0/env"
 )
 
 type Config struct {
-	HTTPAdress     string `env:"RUN_ADDRESS"`
-	DatabaseDSN    string `env:"DATABASE_URI"`
-	AccrualAddress string `env:"ACCRUAL_SYSTEM_ADDRESS"`
+	HTTPAdress                  string        `env:"RUN_ADDRESS"`
+	DatabaseDSN                 string        `env:"DATABASE_URI"`
+	AccrualAddress              string        `env:"ACCRUAL_SYSTEM_ADDRESS"`
+	AccrualMaxAttempts          int           `env:"ACCRUAL_MAX_ATTEMPTS"`
+	AccrualRequestTimeout       time.Duration `env:"ACCRUAL_REQUEST_TIMEOUT"`
+	AccrualTimeout              time.Duration `env:"ACCRUAL_TIMEOUT"`
+	AccrualBreakerThreshold     int           `env:"ACCRUAL_BREAKER_THRESHOLD"`
+	AccrualBreakerProbeInterval time.Duration `env:"ACCRUAL_BREAKER_PROBE_INTERVAL"`
+	AccrualEngine               bool          `env:"ACCRUAL_ENGINE"`
+	AccrualCallbacks            bool          `env:"ACCRUAL_CALLBACKS"`
+	AccrualCallbackTimeout      time.Duration `env:"ACCRUAL_CALLBACK_TIMEOUT"`
+	AccessTokenTTL              time.Duration `env:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL             time.Duration `env:"REFRESH_TOKEN_TTL"`
+	JWTAlgorithm                string        `env:"JWT_ALGORITHM"`
+	JWTKeyFile                  string        `env:"JWT_KEY_FILE"`
+	JWTKeyID                    string        `env:"JWT_KEY_ID"`
+	JWTVerificationKeys         string        `env:"JWT_VERIFICATION_KEYS"`
+	PasswordResetTTL            time.Duration `env:"PASSWORD_RESET_TTL"`
+	PasswordResetNotifyURL      string        `env:"PASSWORD_RESET_NOTIFY_URL"`
+	Argon2Memory                uint32        `env:"ARGON2_MEMORY"`
+	Argon2Time                  uint32        `env:"ARGON2_TIME"`
+	Argon2Threads               uint8         `env:"ARGON2_THREADS"`
+	PartnerSignatures           bool          `env:"PARTNER_SIGNATURES"`
+	SignatureMaxSkew            time.Duration `env:"SIGNATURE_MAX_SKEW"`
+	TrustedProxies              string        `env:"TRUSTED_PROXIES"`
 }
 
 func NewConfig() (*Config, error) {
 	httpAddr := flag.String("a", "", "host and port http")
 	databaseDSN := flag.String("d", "", "database DSN")
 	accrualAddr := flag.String("r", "", "address of the accrual calculation system")
+	accrualMaxAttempts := flag.Int("accrual-max-attempts", 10, "failed accrual requests before an order is dead-lettered")
+	accrualRequestTimeout := flag.Duration("accrual-request-timeout", 5*time.Second, "timeout of a single request to the accrual system")
+	accrualTimeout := flag.Duration("accrual-timeout", 15*time.Second, "timeout of an accrual poll including retries")
+	accrualBreakerThreshold := flag.Int("accrual-breaker-threshold", 5, "consecutive failed accrual requests that open the circuit")
+	accrualBreakerProbeInterval := flag.Duration("accrual-breaker-probe-interval", 30*time.Second, "how long the accrual circuit stays open before a probe request")
+	accrualEngine := flag.Bool("accrual-engine", false, "calculate accruals in-process instead of polling the accrual system")
+	accrualCallbacks := flag.Bool("accrual-callbacks", false, "accept order status callbacks from the accrual system and poll only orders without one")
+	accrualCallbackTimeout := flag.Duration("accrual-callback-timeout", time.Minute, "how long to wait for a callback before an order is polled")
+	accessTokenTTL := flag.Duration("access-token-ttl", 15*time.Minute, "lifetime of access tokens")
+	refreshTokenTTL := flag.Duration("refresh-token-ttl", 30*24*time.Hour, "lifetime of refresh tokens")
+	jwtAlgorithm := flag.String("jwt-alg", "HS256", "access token signing algorithm: HS256, RS256 or EdDSA")
+	jwtKeyFile := flag.String("jwt-key-file", "", "file with the HS256 secret or the PEM private key to sign access tokens with; if empty, a random HS256 secret is generated on start, which other instances do not share")
+	jwtKeyID := flag.String("jwt-key-id", "", "kid of the signing key, derived from the key if empty")
+	jwtVerificationKeys := flag.String("jwt-verification-keys", "", "retired keys still accepted, as comma separated kid=path pairs")
+	passwordResetTTL := flag.Duration("password-reset-ttl", 30*time.Minute, "lifetime of password reset tokens")
+	passwordResetNotifyURL := flag.String("password-reset-notify-url", "", "url of the service delivering password reset tokens to users; password reset is disabled if empty")
+	argon2Memory := flag.Uint("argon2-memory", 19*1024, "memory used by argon2id password hashing, in KiB; defaults follow the OWASP recommendation")
+	argon2Time := flag.Uint("argon2-time", 2, "iterations of argon2id password hashing")
+	argon2Threads := flag.Uint("argon2-threads", 1, "threads used by argon2id password hashing")
+	partnerSignatures := flag.Bool("partner-signatures", false, "require partner requests to be signed with the api key's signing secret")
+	signatureMaxSkew := flag.Duration("signature-max-skew", 5*time.Minute, "how far the timestamp of a signed request may be from the server time")
+	trustedProxies := flag.String("trusted-proxies", "", "comma separated addresses or CIDR networks of proxies whose X-Forwarded-For header is trusted; if empty, the peer address is the client address")
 	flag.Parse()
 
 	cfg := &Config{
-		HTTPAdress:     *httpAddr,
-		DatabaseDSN:    *databaseDSN,
-		AccrualAddress: *accrualAddr,
+		HTTPAdress:                  *httpAddr,
+		DatabaseDSN:                 *databaseDSN,
+		AccrualAddress:              *accrualAddr,
+		AccrualMaxAttempts:          *accrualMaxAttempts,
+		AccrualRequestTimeout:       *accrualRequestTimeout,
+		AccrualTimeout:              *accrualTimeout,
+		AccrualBreakerThreshold:     *accrualBreakerThreshold,
+		AccrualBreakerProbeInterval: *accrualBreakerProbeInterval,
+		AccrualEngine:               *accrualEngine,
+		AccrualCallbacks:            *accrualCallbacks,
+		AccrualCallbackTimeout:      *accrualCallbackTimeout,
+		AccessTokenTTL:              *accessTokenTTL,
+		RefreshTokenTTL:             *refreshTokenTTL,
+		JWTAlgorithm:                *jwtAlgorithm,
+		JWTKeyFile:                  *jwtKeyFile,
+		JWTKeyID:                    *jwtKeyID,
+		JWTVerificationKeys:         *jwtVerificationKeys,
+		PasswordResetTTL:            *passwordResetTTL,
+		PasswordResetNotifyURL:      *passwordResetNotifyURL,
+		Argon2Memory:                uint32(*argon2Memory),
+		Argon2Time:                  uint32(*argon2Time),
+		Argon2Threads:               uint8(*argon2Threads),
+		PartnerSignatures:           *partnerSignatures,
+		SignatureMaxSkew:            *signatureMaxSkew,
+		TrustedProxies:              *trustedProxies,
 	}
 
 	err := env.Parse(cfg)
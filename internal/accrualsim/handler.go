@@ -0,0 +1,178 @@
+package accrualsim
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type registerOrderDto struct {
+	Order       string   `json:"order"`
+	Goods       []Good   `json:"goods"`
+	Progression []Status `json:"progression,omitempty"`
+}
+
+type faultDto struct {
+	Status int `json:"status"`
+	// Delay is a Go duration, e.g. "3s".
+	Delay string `json:"delay,omitempty"`
+	// RetryAfter is sent with 429 in seconds.
+	RetryAfter int `json:"retry_after,omitempty"`
+	Limit      int `json:"limit,omitempty"`
+	Count      int `json:"count,omitempty"`
+}
+
+type progressionDto struct {
+	Progression []Status `json:"progression"`
+}
+
+// Handler serves the API of the accrual system:
+//
+//	GET  /api/orders/{number}  order information
+//	POST /api/orders           order registration
+//	POST /api/goods            reward rule registration
+//
+// and the simulator controls:
+//
+//	PUT    /sim/progression  default progression of new orders
+//	POST   /sim/faults       fault injection
+//	DELETE /sim/faults       removal of all faults
+func (s *Simulator) Handler() http.Handler {
+	r := chi.NewRouter()
+	r.Route("/api", func(r chi.Router) {
+		r.Get("/orders/{number}", s.getOrder)
+		r.Post("/orders", s.registerOrder)
+		r.Post("/goods", s.addRule)
+	})
+	r.Route("/sim", func(r chi.Router) {
+		r.Put("/progression", s.setProgression)
+		r.Post("/faults", s.injectFault)
+		r.Delete("/faults", s.clearFaults)
+	})
+	return r
+}
+
+func (s *Simulator) getOrder(w http.ResponseWriter, r *http.Request) {
+	if fault, ok := s.nextFault(); ok {
+		if fault.Delay > 0 {
+			timer := time.NewTimer(fault.Delay)
+			select {
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+		if fault.Status != 0 {
+			writeFault(w, fault)
+			return
+		}
+	}
+
+	info, ok := s.Poll(chi.URLParam(r, "number"))
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	render.JSON(w, r, info)
+}
+
+func writeFault(w http.ResponseWriter, fault Fault) {
+	if fault.Status != http.StatusTooManyRequests {
+		http.Error(w, http.StatusText(fault.Status), fault.Status)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Retry-After", strconv.Itoa(int(fault.RetryAfter/time.Second)))
+	w.WriteHeader(http.StatusTooManyRequests)
+	fmt.Fprintf(w, "No more than %d requests per minute allowed", fault.Limit)
+}
+
+func (s *Simulator) registerOrder(w http.ResponseWriter, r *http.Request) {
+	var dto registerOrderDto
+	if err := render.DecodeJSON(r.Body, &dto); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := s.RegisterOrder(dto.Order, dto.Goods, dto.Progression...)
+	switch {
+	case errors.Is(err, ErrOrderExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func (s *Simulator) addRule(w http.ResponseWriter, r *http.Request) {
+	var rule Rule
+	if err := render.DecodeJSON(r.Body, &rule); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := s.AddRule(rule)
+	switch {
+	case errors.Is(err, ErrRuleExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (s *Simulator) setProgression(w http.ResponseWriter, r *http.Request) {
+	var dto progressionDto
+	if err := render.DecodeJSON(r.Body, &dto); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.SetProgression(dto.Progression...); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Simulator) injectFault(w http.ResponseWriter, r *http.Request) {
+	var dto faultDto
+	if err := render.DecodeJSON(r.Body, &dto); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fault := Fault{
+		Status:     dto.Status,
+		RetryAfter: time.Duration(dto.RetryAfter) * time.Second,
+		Limit:      dto.Limit,
+		Count:      dto.Count,
+	}
+	if dto.Delay != "" {
+		delay, err := time.ParseDuration(dto.Delay)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%s: %s", ErrInvalidFault, err), http.StatusBadRequest)
+			return
+		}
+		fault.Delay = delay
+	}
+
+	if err := s.InjectFault(fault); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Simulator) clearFaults(w http.ResponseWriter, _ *http.Request) {
+	s.ClearFaults()
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,174 @@
+package accrualsim
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	sim := New()
+	server := httptest.NewServer(sim.Handler())
+	defer server.Close()
+
+	do := func(method, path, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return res
+	}
+	readBody := func(res *http.Response) string {
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, err)
+		return string(body)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "register rule",
+			method:     http.MethodPost,
+			path:       "/api/goods",
+			body:       `{"match": "Bork", "reward": 10, "reward_type": "%"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "duplicate rule",
+			method:     http.MethodPost,
+			path:       "/api/goods",
+			body:       `{"match": "Bork", "reward": 5, "reward_type": "pt"}`,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "invalid rule",
+			method:     http.MethodPost,
+			path:       "/api/goods",
+			body:       `{"match": "Bork"`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "register order",
+			method:     http.MethodPost,
+			path:       "/api/orders",
+			body:       `{"order": "12345678903", "goods": [{"description": "Чайник Bork", "price": 7000}], "progression": ["PROCESSED"]}`,
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "duplicate order",
+			method:     http.MethodPost,
+			path:       "/api/orders",
+			body:       `{"order": "12345678903", "goods": []}`,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "invalid order number",
+			method:     http.MethodPost,
+			path:       "/api/orders",
+			body:       `{"order": "12345678900", "goods": []}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "processed order",
+			method:     http.MethodGet,
+			path:       "/api/orders/12345678903",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"order":"12345678903","status":"PROCESSED","accrual":700}`,
+		},
+		{
+			name:       "unknown order",
+			method:     http.MethodGet,
+			path:       "/api/orders/79927398713",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "invalid progression",
+			method:     http.MethodPut,
+			path:       "/sim/progression",
+			body:       `{"progression": []}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid fault",
+			method:     http.MethodPost,
+			path:       "/sim/faults",
+			body:       `{"delay": "soon"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := do(tt.method, tt.path, tt.body)
+			body := readBody(res)
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, body)
+			}
+		})
+	}
+
+	t.Run("too many requests", func(t *testing.T) {
+		res := do(http.MethodPost, "/sim/faults", `{"status": 429, "retry_after": 60, "limit": 10, "count": 1}`)
+		readBody(res)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		res = do(http.MethodGet, "/api/orders/12345678903", "")
+		body := readBody(res)
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "60", res.Header.Get("Retry-After"))
+		assert.Equal(t, "No more than 10 requests per minute allowed", body)
+
+		// Ошибка была одноразовой.
+		res = do(http.MethodGet, "/api/orders/12345678903", "")
+		readBody(res)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("internal error until cleared", func(t *testing.T) {
+		res := do(http.MethodPost, "/sim/faults", `{"status": 500}`)
+		readBody(res)
+
+		for i := 0; i < 2; i++ {
+			res = do(http.MethodGet, "/api/orders/12345678903", "")
+			readBody(res)
+			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		}
+
+		res = do(http.MethodDelete, "/sim/faults", "")
+		readBody(res)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		res = do(http.MethodGet, "/api/orders/12345678903", "")
+		readBody(res)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		res := do(http.MethodPost, "/sim/faults", `{"delay": "10s", "count": 1}`)
+		readBody(res)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/orders/12345678903", nil)
+		assert.NoError(t, err)
+		start := time.Now()
+		_, err = http.DefaultClient.Do(req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
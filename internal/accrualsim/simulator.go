@@ -0,0 +1,255 @@
+// Package accrualsim simulates the accrual system gophermart polls for order
+// rewards. It implements the GET /api/orders/{number} contract together with
+// the order and reward rule registration of the real service, plays scripted
+// status progressions and injects faults on demand.
+//
+// The simulator runs standalone as cmd/accrualsim or in-process:
+//
+//	sim := accrualsim.New()
+//	server := httptest.NewServer(sim.Handler())
+//	defer server.Close()
+package accrualsim
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/utils"
+)
+
+type Status string
+
+const (
+	StatusRegistered Status = "REGISTERED"
+	StatusInvalid    Status = "INVALID"
+	StatusProcessing Status = "PROCESSING"
+	StatusProcessed  Status = "PROCESSED"
+)
+
+func (s Status) isFinal() bool {
+	return s == StatusInvalid || s == StatusProcessed
+}
+
+// DefaultProgression is played for orders registered without their own.
+var DefaultProgression = []Status{StatusRegistered, StatusProcessing, StatusProcessed}
+
+// Rewards are calculated the same way as by the built-in accrual engine.
+type (
+	Rule       = entity.RewardRule
+	Good       = entity.Good
+	RewardType = entity.RewardType
+)
+
+const (
+	RewardPercent = entity.RewardPercent
+	RewardPoints  = entity.RewardPoints
+)
+
+// OrderInfo is the answer of the accrual system about an order.
+type OrderInfo struct {
+	Order   string        `json:"order"`
+	Status  Status        `json:"status"`
+	Accrual *entity.Money `json:"accrual,omitempty"`
+}
+
+// Fault makes the next Count requests for order information misbehave, or
+// all of them if Count is zero, until ClearFaults is called. The response is
+// delayed by Delay, which simulates a timeout when it exceeds the client
+// timeout, and then answered with Status if it is not zero. RetryAfter and
+// Limit are sent with 429 responses.
+type Fault struct {
+	Status     int
+	Delay      time.Duration
+	RetryAfter time.Duration
+	Limit      int
+	Count      int
+}
+
+var (
+	ErrInvalidOrder = errors.New("invalid order")
+	ErrOrderExists  = errors.New("order is already registered")
+	ErrInvalidRule  = common.ErrInvalidRewardRule
+	ErrRuleExists   = errors.New("reward rule is already registered")
+	ErrInvalidFault = errors.New("invalid fault")
+)
+
+type order struct {
+	goods       []Good
+	progression []Status
+	step        int
+}
+
+type Simulator struct {
+	mu          sync.Mutex
+	rules       []Rule
+	orders      map[string]*order
+	progression []Status
+	faults      []Fault
+}
+
+func New() *Simulator {
+	return &Simulator{
+		orders:      make(map[string]*order),
+		progression: DefaultProgression,
+	}
+}
+
+// SetProgression changes the statuses played for orders registered later
+// without their own progression.
+func (s *Simulator) SetProgression(progression ...Status) error {
+	if err := validateProgression(progression); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.progression = progression
+	return nil
+}
+
+func validateProgression(progression []Status) error {
+	if len(progression) == 0 {
+		return fmt.Errorf("%w: empty progression", ErrInvalidOrder)
+	}
+	for i, status := range progression {
+		switch status {
+		case StatusRegistered, StatusProcessing, StatusInvalid, StatusProcessed:
+		default:
+			return fmt.Errorf("%w: unknown status %q", ErrInvalidOrder, status)
+		}
+		if status.isFinal() && i != len(progression)-1 {
+			return fmt.Errorf("%w: final status %s is not the last one", ErrInvalidOrder, status)
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) AddRule(rule Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.rules {
+		if existing.Match == rule.Match {
+			return ErrRuleExists
+		}
+	}
+	s.rules = append(s.rules, rule)
+	return nil
+}
+
+// RegisterOrder accepts an order for calculation. Every poll of the order
+// moves it one step along progression, or along the default progression if
+// none is given; the last status is kept.
+func (s *Simulator) RegisterOrder(number string, goods []Good, progression ...Status) error {
+	if !utils.IsOrderNumberValid(number) {
+		return fmt.Errorf("%w: number %q", ErrInvalidOrder, number)
+	}
+	for _, good := range goods {
+		if err := good.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidOrder, err)
+		}
+	}
+	if len(progression) > 0 {
+		if err := validateProgression(progression); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.orders[number]; ok {
+		return ErrOrderExists
+	}
+	if len(progression) == 0 {
+		progression = s.progression
+	}
+	s.orders[number] = &order{
+		goods:       goods,
+		progression: progression,
+	}
+	return nil
+}
+
+// Poll returns the current state of the order and advances it. The second
+// value is false for an unknown order.
+func (s *Simulator) Poll(number string) (OrderInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[number]
+	if !ok {
+		return OrderInfo{}, false
+	}
+
+	info := OrderInfo{
+		Order:  number,
+		Status: o.progression[o.step],
+	}
+	if info.Status == StatusProcessed {
+		// An order whose accrual does not fit is rejected, as the real
+		// system would do.
+		accrual, err := entity.CalculateAccrual(o.goods, s.rules)
+		if err != nil {
+			info.Status = StatusInvalid
+		} else {
+			info.Accrual = &accrual
+		}
+	}
+	if o.step < len(o.progression)-1 {
+		o.step++
+	}
+	return info, true
+}
+
+func (s *Simulator) InjectFault(fault Fault) error {
+	if fault.Status == 0 && fault.Delay <= 0 {
+		return fmt.Errorf("%w: a status or a delay is required", ErrInvalidFault)
+	}
+	if fault.Status != 0 && (fault.Status < 400 || fault.Status > 599) {
+		return fmt.Errorf("%w: status %d is not an error", ErrInvalidFault, fault.Status)
+	}
+	if fault.Count < 0 {
+		return fmt.Errorf("%w: negative count", ErrInvalidFault)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, fault)
+	return nil
+}
+
+func (s *Simulator) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// nextFault takes the fault the next request is affected by.
+func (s *Simulator) nextFault() (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.faults) == 0 {
+		return Fault{}, false
+	}
+	fault := s.faults[0]
+	if fault.Count > 0 {
+		s.faults[0].Count--
+		if s.faults[0].Count == 0 {
+			s.faults = s.faults[1:]
+		}
+	}
+	return fault, true
+}
@@ -0,0 +1,159 @@
+package accrualsim
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	orderNumber  = "12345678903"
+	otherNumber  = "79927398713"
+	invalidOrder = "12345678900"
+)
+
+func TestSimulator_Poll(t *testing.T) {
+	sim := New()
+	assert.NoError(t, sim.AddRule(Rule{Match: "Bork", Reward: 1000, RewardType: RewardPercent}))
+	assert.NoError(t, sim.AddRule(Rule{Match: "Miele", Reward: 5000, RewardType: RewardPoints}))
+
+	goods := []Good{
+		{Description: "Чайник Bork", Price: 700000},
+		{Description: "Стиральная машинка Miele", Price: 4500050},
+		{Description: "Утюг", Price: 300000},
+	}
+	assert.NoError(t, sim.RegisterOrder(orderNumber, goods))
+
+	var statuses []Status
+	for i := 0; i < 4; i++ {
+		info, ok := sim.Poll(orderNumber)
+		assert.True(t, ok)
+		statuses = append(statuses, info.Status)
+		if info.Status != StatusProcessed {
+			assert.Nil(t, info.Accrual)
+			continue
+		}
+		// 10% от 7000 + 50 баллов за Miele, утюг не подходит ни под одно правило.
+		if assert.NotNil(t, info.Accrual) {
+			assert.Equal(t, entity.Money(75000), *info.Accrual)
+		}
+	}
+	assert.Equal(t, []Status{StatusRegistered, StatusProcessing, StatusProcessed, StatusProcessed}, statuses)
+
+	_, ok := sim.Poll(otherNumber)
+	assert.False(t, ok)
+}
+
+func TestSimulator_RegisterOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		number      string
+		progression []Status
+		wantErr     error
+	}{
+		{name: "default progression", number: orderNumber},
+		{name: "own progression", number: orderNumber, progression: []Status{StatusProcessing, StatusInvalid}},
+		{name: "invalid number", number: invalidOrder, wantErr: ErrInvalidOrder},
+		{name: "unknown status", number: orderNumber, progression: []Status{"NEW"}, wantErr: ErrInvalidOrder},
+		{
+			name:        "final status in the middle",
+			number:      orderNumber,
+			progression: []Status{StatusInvalid, StatusProcessed},
+			wantErr:     ErrInvalidOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := New()
+			err := sim.RegisterOrder(tt.number, nil, tt.progression...)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("price out of range", func(t *testing.T) {
+		sim := New()
+		goods := []Good{{Description: "Утюг", Price: entity.MaxPrice + 1}}
+		assert.ErrorIs(t, sim.RegisterOrder(orderNumber, goods), ErrInvalidOrder)
+	})
+
+	t.Run("accrual out of range", func(t *testing.T) {
+		sim := New()
+		assert.NoError(t, sim.SetProgression(StatusProcessed))
+		assert.NoError(t, sim.AddRule(Rule{Match: "Miele", Reward: math.MaxInt64, RewardType: RewardPoints}))
+		assert.NoError(t, sim.RegisterOrder(orderNumber, []Good{{Description: "Miele"}, {Description: "Miele"}}))
+
+		info, _ := sim.Poll(orderNumber)
+		assert.Equal(t, StatusInvalid, info.Status)
+		assert.Nil(t, info.Accrual)
+	})
+
+	t.Run("duplicate order", func(t *testing.T) {
+		sim := New()
+		assert.NoError(t, sim.RegisterOrder(orderNumber, nil))
+		assert.ErrorIs(t, sim.RegisterOrder(orderNumber, nil), ErrOrderExists)
+	})
+
+	t.Run("default progression is changed", func(t *testing.T) {
+		sim := New()
+		assert.NoError(t, sim.SetProgression(StatusRegistered, StatusInvalid))
+		assert.NoError(t, sim.RegisterOrder(orderNumber, nil))
+
+		info, _ := sim.Poll(orderNumber)
+		assert.Equal(t, StatusRegistered, info.Status)
+		info, _ = sim.Poll(orderNumber)
+		assert.Equal(t, StatusInvalid, info.Status)
+		assert.Nil(t, info.Accrual)
+	})
+}
+
+func TestSimulator_AddRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr error
+	}{
+		{name: "percent", rule: Rule{Match: "Bork", Reward: 1000, RewardType: RewardPercent}},
+		{name: "points", rule: Rule{Match: "Bork", Reward: 1000, RewardType: RewardPoints}},
+		{name: "empty match", rule: Rule{Reward: 1000, RewardType: RewardPoints}, wantErr: ErrInvalidRule},
+		{name: "zero reward", rule: Rule{Match: "Bork", RewardType: RewardPoints}, wantErr: ErrInvalidRule},
+		{name: "unknown type", rule: Rule{Match: "Bork", Reward: 1000, RewardType: "x"}, wantErr: ErrInvalidRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, New().AddRule(tt.rule), tt.wantErr)
+		})
+	}
+
+	t.Run("duplicate match", func(t *testing.T) {
+		sim := New()
+		assert.NoError(t, sim.AddRule(Rule{Match: "Bork", Reward: 1000, RewardType: RewardPercent}))
+		assert.ErrorIs(t, sim.AddRule(Rule{Match: "Bork", Reward: 500, RewardType: RewardPoints}), ErrRuleExists)
+	})
+}
+
+func TestSimulator_faults(t *testing.T) {
+	sim := New()
+	assert.ErrorIs(t, sim.InjectFault(Fault{}), ErrInvalidFault)
+	assert.ErrorIs(t, sim.InjectFault(Fault{Status: 200}), ErrInvalidFault)
+	assert.ErrorIs(t, sim.InjectFault(Fault{Status: 500, Count: -1}), ErrInvalidFault)
+
+	assert.NoError(t, sim.InjectFault(Fault{Status: 500, Count: 2}))
+	assert.NoError(t, sim.InjectFault(Fault{Status: 429}))
+
+	var statuses []int
+	for i := 0; i < 4; i++ {
+		fault, ok := sim.nextFault()
+		assert.True(t, ok)
+		statuses = append(statuses, fault.Status)
+	}
+	// Ошибка без счётчика действует до ClearFaults.
+	assert.Equal(t, []int{500, 500, 429, 429}, statuses)
+
+	sim.ClearFaults()
+	_, ok := sim.nextFault()
+	assert.False(t, ok)
+}
@@ -2,39 +2,87 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
+	"fmt"
+	accrualcallbackhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/accrualcallback"
+	accrualenginehandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/accrualengine"
+	adminhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/admin"
+	apikeyhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/apikey"
 	authhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/auth"
 	balancehandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/balance"
+	healthhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/health"
+	jwkshandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/jwks"
 	orderhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/order"
+	partnerhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/partner"
+	passwordhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/password"
 	withdrawalhandler "github.com/MxTrap/gophermart/internal/gophermart/controller/http/handlers/withdrawal"
 	"github.com/MxTrap/gophermart/internal/gophermart/services/accrual"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/accrualcallback"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/accrualengine"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/admin"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/apikey"
 	"github.com/MxTrap/gophermart/internal/gophermart/services/auth"
 	"github.com/MxTrap/gophermart/internal/gophermart/services/balance"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/hasher"
 	"github.com/MxTrap/gophermart/internal/gophermart/services/jwt"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/ledger"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/loginguard"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/notifier"
 	"github.com/MxTrap/gophermart/internal/gophermart/services/order"
 	"github.com/MxTrap/gophermart/internal/gophermart/services/orderworker"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/partner"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/password"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/ratelimiter"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/replayguard"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/revocation"
 	"github.com/MxTrap/gophermart/internal/gophermart/services/storage"
 	"github.com/MxTrap/gophermart/internal/gophermart/services/withdrawal"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"strings"
 	"time"
 
 	"github.com/MxTrap/gophermart/config"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/controller/http"
 	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/MxTrap/gophermart/internal/gophermart/migrator"
 	"github.com/MxTrap/gophermart/internal/gophermart/repository/postgres"
+	accrualenginerepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/accrualengine"
+	apikeyrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/apikey"
+	auditrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/audit"
 	balancerepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/balance"
 	"github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/combined"
+	ledgerrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/ledger"
+	loginattemptrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/loginattempt"
+	noncerepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/nonce"
 	orderrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/order"
+	passwordresetrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/passwordreset"
+	queuerepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/queue"
+	refreshtokenrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/refreshtoken"
+	revocationrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/revocation"
 	userrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/user"
 	withdrawalrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/withdrawal"
 	"github.com/MxTrap/gophermart/logger"
 )
 
+// accrualSource tells the order worker the accrual of an order: the
+// accrual system or the built-in engine.
+type accrualSource interface {
+	GetOrderAccrual(ctx context.Context, number string) (entity.Order, error)
+}
+
 type App struct {
 	pgStorage      *postgres.Storage
 	httpController *http.Controller
 	orderWorker    *orderworker.OrderWorkerService
+	accrual        *accrual.AccrualService
+	notifier       *notifier.WebhookNotifier
+	ledger         *ledger.LedgerService
+	revocations    *revocation.RevocationService
+	loginGuard     *loginguard.LoginGuard
+	replayGuard    *replayguard.ReplayGuard
 	logger         *logger.Logger
 }
 
@@ -54,44 +102,245 @@ func NewApp(ctx context.Context, log *logger.Logger, cfg *config.Config) (*App,
 	}
 
 	userRepo := userrepo.NewUserRepository(postgresStorage.Pool)
-	orderRepo := orderrepo.NewOrderRepository(postgresStorage.Pool)
+	// Orders whose status is reported by callbacks are polled only as a
+	// fallback, once the callback is overdue.
+	var firstPollDelay time.Duration
+	pollDelay := storage.DefaultPollDelay
+	if cfg.AccrualCallbacks {
+		firstPollDelay = cfg.AccrualCallbackTimeout
+		pollDelay = cfg.AccrualCallbackTimeout
+	}
+	orderRepo := orderrepo.NewOrderRepository(postgresStorage.Pool, firstPollDelay)
 	balanceRepo := balancerepo.NewBalanceRepository(postgresStorage.Pool)
 	withdrawalRepo := withdrawalrepo.NewWithdrawnRepo(postgresStorage.Pool)
-	orderBalanceRepo := combined.NewOrderBalanceRepo(postgresStorage.Pool, orderRepo, balanceRepo)
-	balanceWithdrawalRepo := combined.NewBalanceWithdrawnRepo(postgresStorage.Pool, balanceRepo, withdrawalRepo)
+	queueRepo := queuerepo.NewQueueRepository(postgresStorage.Pool)
+	ledgerRepo := ledgerrepo.NewLedgerRepository(postgresStorage.Pool)
+	refreshTokenRepo := refreshtokenrepo.NewRefreshTokenRepository(postgresStorage.Pool)
+	revocationRepo := revocationrepo.NewRevocationRepository(postgresStorage.Pool)
+	loginAttemptRepo := loginattemptrepo.NewLoginAttemptRepository(postgresStorage.Pool)
+	nonceRepo := noncerepo.NewNonceRepository(postgresStorage.Pool)
+	passwordResetRepo := passwordresetrepo.NewPasswordResetRepository(postgresStorage.Pool)
+	auditRepo := auditrepo.NewAuditRepository(postgresStorage.Pool)
+	apiKeyRepo := apikeyrepo.NewAPIKeyRepository(postgresStorage.Pool)
+	accrualEngineRepo := accrualenginerepo.NewAccrualEngineRepository(postgresStorage.Pool)
+	orderBalanceRepo := combined.NewOrderBalanceRepo(postgresStorage.Pool, orderRepo, ledgerRepo)
+	balanceWithdrawalRepo := combined.NewBalanceWithdrawnRepo(postgresStorage.Pool, ledgerRepo, withdrawalRepo)
+	auditedRepo := combined.NewAuditedRepo(postgresStorage.Pool, orderRepo, ledgerRepo, auditRepo)
 
-	storageSvc := storage.NewStorageService()
-	jwtSvc := jwt.NewJWTService("very secret")
-	orderSvc := order.NewOrderService(log, storageSvc, orderRepo)
+	storageSvc := storage.NewStorageService(log, queueRepo, cfg.AccrualMaxAttempts, pollDelay)
+	jwtSvc, err := newJWTService(log, cfg)
+	if err != nil {
+		return nil, err
+	}
+	orderSvc := order.NewOrderService(log, orderRepo)
 	balanceSvc := balance.NewBalanceService(log, balanceRepo)
-	accrualSvc := accrual.NewAccrualService(log, cfg.AccrualAddress)
+	ledgerSvc := ledger.NewLedgerService(log, ledgerRepo)
+	accrualBreaker := accrual.NewCircuitBreaker(log, cfg.AccrualBreakerThreshold, cfg.AccrualBreakerProbeInterval)
+	accrualSvc := accrual.NewAccrualService(
+		log,
+		cfg.AccrualAddress,
+		cfg.AccrualRequestTimeout,
+		cfg.AccrualTimeout,
+		accrualBreaker,
+	)
 	withdrawalSvc := withdrawal.NewWithdrawalService(log, balanceWithdrawalRepo, withdrawalRepo)
-	authSvc := auth.NewAuthService(log, userRepo, jwtSvc, 15*time.Hour)
-	orderWorkerSvc := orderworker.NewOrderWorkerService(log, accrualSvc, storageSvc, orderBalanceRepo)
+	revocationSvc := revocation.NewRevocationService(log, revocationRepo, cfg.AccessTokenTTL)
+	if err := revocationSvc.Sync(ctx); err != nil {
+		return nil, err
+	}
+	loginGuard := loginguard.NewLoginGuard(log, loginAttemptRepo)
+	replayGuard := replayguard.NewReplayGuard(log, nonceRepo, 2*cfg.SignatureMaxSkew)
+	passwordHasher, err := hasher.NewPasswordHasher(hasher.Argon2Params{
+		Memory:  cfg.Argon2Memory,
+		Time:    cfg.Argon2Time,
+		Threads: cfg.Argon2Threads,
+	})
+	if err != nil {
+		return nil, err
+	}
+	authSvc := auth.NewAuthService(
+		log,
+		userRepo,
+		passwordHasher,
+		refreshTokenRepo,
+		revocationSvc,
+		loginGuard,
+		jwtSvc,
+		cfg.AccessTokenTTL,
+		cfg.RefreshTokenTTL,
+	)
+	resetNotifier := notifier.NewWebhookNotifier(log, cfg.PasswordResetNotifyURL)
+	passwordSvc := password.NewPasswordService(
+		log,
+		userRepo,
+		passwordHasher,
+		passwordResetRepo,
+		authSvc,
+		loginGuard,
+		resetNotifier,
+		cfg.PasswordResetTTL,
+	)
+	adminSvc := admin.NewAdminService(log, userRepo, auditedRepo, auditRepo, storageSvc, loginGuard)
+	apiKeySvc := apikey.NewAPIKeyService(log, apiKeyRepo, auditRepo)
+	partnerSvc := partner.NewPartnerService(log, userRepo, orderSvc)
+	accrualEngine := accrualengine.NewAccrualEngine(log, accrualEngineRepo)
+	var accrualSource accrualSource = accrualSvc
+	if cfg.AccrualEngine {
+		log.Info("built-in accrual engine enabled, the accrual system is not polled")
+		accrualSource = accrualEngine
+	}
+	orderWorkerSvc := orderworker.NewOrderWorkerService(
+		log,
+		accrualSource,
+		storageSvc,
+		orderBalanceRepo,
+		ratelimiter.NewRateLimiter(),
+		accrualBreaker,
+	)
+
+	trustedProxies, err := middlewares.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	if len(trustedProxies) == 0 {
+		log.Warn("trusted proxies are not set, clients are identified by the peer address")
+	}
 
 	httpController := http.NewController(cfg.HTTPAdress)
 	httpController.RegisterMiddlewares(
 		middlewares.LoggerMiddleware(log),
+		middlewares.NewClientIPMiddleware(trustedProxies).Resolve,
 		middleware.Compress(5, "application/json"),
 	)
 
-	authMiddleware := middlewares.NewAuhtorizationMiddleware(jwtSvc)
+	authMiddleware := middlewares.NewAuhtorizationMiddleware(jwtSvc, revocationSvc)
 
-	authHandler := authhandler.NewAuthHandler(authSvc)
+	authHandler := authhandler.NewAuthHandler(authMiddleware, authSvc)
+	passwordHandler := passwordhandler.NewPasswordHandler(authMiddleware, passwordSvc)
 	ordersHandler := orderhandler.NewOrdersHandler(authMiddleware, orderSvc)
 	balanceHandler := balancehandler.NewBalanceHandler(authMiddleware, balanceSvc, withdrawalSvc)
 	withdrawalHandler := withdrawalhandler.NewWithdrawalHandler(authMiddleware, withdrawalSvc)
 
-	httpController.AddHandler("/user", authHandler, ordersHandler, balanceHandler, withdrawalHandler)
+	httpController.AddHandler("/user", authHandler, passwordHandler, ordersHandler, balanceHandler, withdrawalHandler)
+	if cfg.PasswordResetNotifyURL != "" {
+		httpController.AddHandler("/user", passwordhandler.NewPasswordResetHandler(passwordSvc))
+	} else {
+		log.Warn("password reset notify url is not set, password reset is disabled")
+	}
+	httpController.AddRootHandler(
+		jwkshandler.NewJWKSHandler(jwtSvc),
+		healthhandler.NewHealthHandler(accrualBreaker),
+	)
+
+	adminHandler := adminhandler.NewAdminHandler(
+		adminSvc,
+		storageSvc,
+		ledgerSvc,
+		orderSvc,
+		balanceSvc,
+		withdrawalSvc,
+	)
+	httpController.AddHandler("/admin", adminHandler, apikeyhandler.NewAPIKeyHandler(apiKeySvc))
+	httpController.ProtectHandler(
+		"/admin",
+		authMiddleware.Validate,
+		middlewares.RequireRole(entity.RoleSupport, entity.RoleAdmin),
+	)
+
+	httpController.AddHandler("/partner", partnerhandler.NewPartnerHandler(partnerSvc))
+	if cfg.AccrualEngine {
+		httpController.AddHandler("/partner", func(r chi.Router) {
+			r.Route("/accrual", accrualenginehandler.NewAccrualEngineHandler(accrualEngine))
+		})
+	}
+
+	// The accrual system calls back with an API key as partners do, so the
+	// endpoints share the middlewares.
+	partnerPaths := []string{"/partner"}
+	if cfg.AccrualCallbacks {
+		log.Infof("accrual callbacks enabled, orders are polled after %s without a callback", cfg.AccrualCallbackTimeout)
+		callbackSvc := accrualcallback.NewCallbackService(
+			log,
+			orderRepo,
+			orderBalanceRepo,
+			storageSvc,
+			cfg.AccrualCallbackTimeout,
+		)
+		httpController.AddHandler("/internal/accrual", accrualcallbackhandler.NewAccrualCallbackHandler(callbackSvc))
+		partnerPaths = append(partnerPaths, "/internal/accrual")
+	}
+	apiKeyMiddleware := middlewares.NewAPIKeyMiddleware(apiKeySvc)
+	var signatureMiddleware *middlewares.SignatureMiddleware
+	if cfg.PartnerSignatures {
+		signatureMiddleware = middlewares.NewSignatureMiddleware(replayGuard, cfg.SignatureMaxSkew)
+	}
+	for _, path := range partnerPaths {
+		httpController.ProtectHandler(path, apiKeyMiddleware.Validate)
+		if signatureMiddleware != nil {
+			httpController.ProtectHandler(path, signatureMiddleware.Verify)
+		}
+	}
 
 	return &App{
 		pgStorage:      postgresStorage,
 		httpController: httpController,
 		orderWorker:    orderWorkerSvc,
+		accrual:        accrualSvc,
+		notifier:       resetNotifier,
+		ledger:         ledgerSvc,
+		revocations:    revocationSvc,
+		loginGuard:     loginGuard,
+		replayGuard:    replayGuard,
 		logger:         log,
 	}, nil
 }
 
+// newJWTService loads the configured signing and verification keys. Without
+// a key file tokens are signed with a random secret: they are not accepted
+// by other instances and after a restart.
+func newJWTService(log *logger.Logger, cfg *config.Config) (*jwt.JwtService, error) {
+	var signing jwt.Key
+	if cfg.JWTKeyFile == "" {
+		if cfg.JWTAlgorithm != "HS256" {
+			return nil, fmt.Errorf("jwt key file is required for %s", cfg.JWTAlgorithm)
+		}
+		// Every instance signs with its own secret, so tokens are rejected by
+		// the other replicas and after a restart. This is fine for a single
+		// instance, e.g. in tests, but not for a deployment.
+		log.Warn("jwt key file is not set, signing access tokens with a random secret: " +
+			"tokens are valid only on this instance until it restarts, set -jwt-key-file in production")
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		signing = jwt.NewHMACKey(cfg.JWTKeyID, secret)
+	} else {
+		key, err := jwt.LoadSigningKey(cfg.JWTAlgorithm, cfg.JWTKeyID, cfg.JWTKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt signing key: %w", err)
+		}
+		signing = key
+	}
+
+	var verification []jwt.Key
+	for _, pair := range strings.Split(cfg.JWTVerificationKeys, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(pair, "=")
+		if !ok {
+			kid, path = "", pair
+		}
+		key, err := jwt.LoadVerificationKey(kid, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt verification key %s: %w", path, err)
+		}
+		verification = append(verification, key)
+	}
+
+	return jwt.NewJWTService(signing, verification...)
+}
+
 func (a *App) Run(ctx context.Context) {
 	go func() {
 		err := a.httpController.Start()
@@ -101,6 +350,13 @@ func (a *App) Run(ctx context.Context) {
 	}()
 
 	go a.orderWorker.Run(ctx)
+	go a.revocations.Run(ctx)
+	go a.loginGuard.Run(ctx)
+	go a.replayGuard.Run(ctx)
+	go func() {
+		// Mismatches are logged by the check itself.
+		_, _ = a.ledger.Check(ctx)
+	}()
 	a.logger.Info("App started")
 }
 
@@ -109,6 +365,12 @@ func (a *App) Stop(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := a.accrual.Close(); err != nil {
+		a.logger.Error("failed to close accrual client: ", err)
+	}
+	if err := a.notifier.Close(); err != nil {
+		a.logger.Error("failed to close notifier client: ", err)
+	}
 	a.pgStorage.Stop()
 	a.logger.Info("App stopped")
 
@@ -1,6 +1,10 @@
 package common
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
@@ -13,11 +17,22 @@ var (
 var (
 	ErrInvalidToken    = errors.New("invalid_token")
 	ErrTokenHasExpired = errors.New("token_has_expired")
+	ErrTokenReused     = errors.New("token_reused")
+	ErrTokenRevoked    = errors.New("token_revoked")
 )
 
 var (
 	ErrUserNotFound     = errors.New("user not found")
 	ErrUserAlreadyExist = errors.New("user already exist")
+	ErrUnknownRole      = errors.New("unknown role")
+	ErrForbidden        = errors.New("forbidden")
+	ErrExternalIDTaken  = errors.New("external id is taken by another user")
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrUnknownScope   = errors.New("unknown api key scope")
 )
 
 var (
@@ -25,6 +40,37 @@ var (
 	ErrOrderAlreadyExist        = errors.New("order number already exist")
 	ErrOrderRegisteredByAnother = errors.New("order registered by another user")
 	ErrNonExistentOrder         = errors.New("order does not exist")
+	ErrUnknownOrderStatus       = errors.New("unknown order status")
+	ErrIllegalStatusTransition  = errors.New("illegal order status transition")
+	ErrAccrualOrderExists       = errors.New("order is already registered for accrual")
+	ErrInvalidRewardRule        = errors.New("invalid reward rule")
+	ErrRewardRuleExists         = errors.New("reward rule already exist")
+)
+
+var (
+	ErrInsufficientBalance  = errors.New("insufficient balance")
+	ErrInvalidAmount        = errors.New("invalid amount")
+	ErrInvalidHistoryFilter = errors.New("invalid history filter")
+	ErrInvalidReason        = errors.New("reason is required")
+)
+
+var (
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrCircuitOpen     = errors.New("accrual system is unavailable, circuit is open")
 )
 
-var ErrInsufficientBalance = errors.New("insufficient balance")
+// TooManyRequestsError carries the throttling hints returned together with
+// a 429 response: how long to wait and, if known, how many requests per
+// minute are allowed.
+type TooManyRequestsError struct {
+	RetryAfter time.Duration
+	Limit      int
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return fmt.Sprintf("%s: retry after %s, limit %d rpm", ErrTooManyRequests, e.RetryAfter, e.Limit)
+}
+
+func (e *TooManyRequestsError) Unwrap() error {
+	return ErrTooManyRequests
+}
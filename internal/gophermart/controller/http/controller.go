@@ -8,19 +8,22 @@ import (
 )
 
 type Controller struct {
-	router   chi.Router
-	server   *http.Server
-	host     string
-	handlers map[string][]func(chi.Router)
+	router       chi.Router
+	server       *http.Server
+	host         string
+	handlers     map[string][]func(chi.Router)
+	protections  map[string][]func(http.Handler) http.Handler
+	rootHandlers []func(chi.Router)
 }
 
 func NewController(host string) *Controller {
 	r := chi.NewRouter()
 
 	return &Controller{
-		router:   r,
-		host:     host,
-		handlers: make(map[string][]func(chi.Router)),
+		router:      r,
+		host:        host,
+		handlers:    make(map[string][]func(chi.Router)),
+		protections: make(map[string][]func(http.Handler) http.Handler),
 	}
 }
 
@@ -39,10 +42,26 @@ func (c *Controller) AddHandler(path string, group ...func(chi.Router)) {
 	c.handlers[path] = group
 }
 
+// ProtectHandler applies middlewares to all handlers registered under path,
+// e.g. to require a role for a route group.
+func (c *Controller) ProtectHandler(path string, middlewares ...func(http.Handler) http.Handler) {
+	c.protections[path] = append(c.protections[path], middlewares...)
+}
+
+// AddRootHandler registers handlers outside of /api, e.g. for well-known
+// URIs.
+func (c *Controller) AddRootHandler(group ...func(chi.Router)) {
+	c.rootHandlers = append(c.rootHandlers, group...)
+}
+
 func (c *Controller) registerHandlers() {
+	for _, handler := range c.rootHandlers {
+		handler(c.router)
+	}
 	c.router.Route("/api", func(r chi.Router) {
 		for path, group := range c.handlers {
 			r.Route(path, func(r chi.Router) {
+				r.Use(c.protections[path]...)
 				for _, handler := range group {
 					handler(r)
 				}
@@ -1,7 +1,6 @@
 package http
 
 import (
-	"context"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"io"
@@ -25,8 +24,7 @@ func TestNewController(t *testing.T) {
 }
 
 func TestController_RegisterMiddlewares(t *testing.T) {
-	host := "localhost:8086"
-	ctrl := NewController(host)
+	ctrl := NewController(":8080")
 
 	called := 0
 	middleware1 := func(next http.Handler) http.Handler {
@@ -49,14 +47,17 @@ func TestController_RegisterMiddlewares(t *testing.T) {
 		})
 	})
 
-	go ctrl.Start()
+	ctrl.registerHandlers()
+
+	// Сервер поднимается синхронно, чтобы запрос не опередил его запуск
+	server := httptest.NewServer(ctrl.router)
+	defer server.Close()
 
-	resp, err := http.Get("http://" + host)
+	resp, err := http.Get(server.URL)
 	if err != nil {
 		assert.FailNow(t, err.Error())
 	}
 	defer resp.Body.Close()
-	ctrl.Stop(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
@@ -117,6 +118,60 @@ func TestController_registerHandlers(t *testing.T) {
 	assert.Equal(t, "user handler", string(body))
 }
 
+func TestController_AddRootHandler(t *testing.T) {
+	ctrl := NewController(":8080")
+	ctrl.AddRootHandler(func(r chi.Router) {
+		r.Get("/.well-known/test", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("root handler"))
+		})
+	})
+	ctrl.registerHandlers()
+
+	server := httptest.NewServer(ctrl.router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/.well-known/test")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "root handler", string(readResponseBody(resp)))
+
+	resp, err = http.Get(server.URL + "/api/.well-known/test")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestController_ProtectHandler(t *testing.T) {
+	ctrl := NewController(":8080")
+
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+	ok := func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+	}
+	ctrl.AddHandler("/admin", ok)
+	ctrl.AddHandler("/user", ok)
+	ctrl.ProtectHandler("/admin", deny)
+	ctrl.registerHandlers()
+
+	server := httptest.NewServer(ctrl.router)
+	defer server.Close()
+
+	// Защита применяется только к своей группе маршрутов
+	resp, err := http.Get(server.URL + "/api/admin/")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = http.Get(server.URL + "/api/user/")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
+
 func readResponseBody(resp *http.Response) []byte {
 	var body []byte
 	if resp.Body != nil {
@@ -0,0 +1,146 @@
+package accrualcallback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// maxUpdates limits the number of orders reported by one callback.
+const maxUpdates = 100
+
+type callbackService interface {
+	Apply(ctx context.Context, updates []entity.Order) []error
+}
+
+type accrualCallbackHandler struct {
+	service callbackService
+}
+
+// NewAccrualCallbackHandler registers the endpoint the accrual system pushes
+// order statuses to. It is authorized by API key with the accrual:callback
+// scope.
+func NewAccrualCallbackHandler(service callbackService) func(chi.Router) {
+	h := &accrualCallbackHandler{
+		service: service,
+	}
+	return func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireScope(entity.ScopeAccrualCallback))
+			r.Post("/callback", h.Callback)
+		})
+	}
+}
+
+type updateRequest struct {
+	Order   string      `json:"order"`
+	Status  string      `json:"status"`
+	Accrual json.Number `json:"accrual,omitempty"`
+
+	status  entity.OrderStatus
+	accrual *entity.Money
+}
+
+type callbackRequest []updateRequest
+
+func (req *callbackRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	if len(*req) == 0 {
+		errs.Add("", "must contain at least one order")
+		return errs
+	}
+	if len(*req) > maxUpdates {
+		errs.Add("", fmt.Sprintf("must contain at most %d orders", maxUpdates))
+		return errs
+	}
+	for i := range *req {
+		update := &(*req)[i]
+		field := func(name string) string {
+			return fmt.Sprintf("[%d].%s", i, name)
+		}
+
+		errs.OrderNumber(field("order"), update.Order)
+
+		status, err := entity.ParseAccrualStatus(update.Status)
+		if err != nil {
+			errs.Add(field("status"), "must be REGISTERED, PROCESSING, INVALID or PROCESSED")
+			continue
+		}
+		update.status = status
+
+		if update.Accrual == "" {
+			continue
+		}
+		if status != entity.OrderProcessed {
+			errs.Add(field("accrual"), "is allowed only for PROCESSED orders")
+			continue
+		}
+		accrual, ok := errs.Amount(field("accrual"), update.Accrual)
+		if ok && accrual < 0 {
+			errs.Add(field("accrual"), "must not be negative")
+			continue
+		}
+		update.accrual = &accrual
+	}
+	return errs
+}
+
+type resultDTO struct {
+	Order  string `json:"order"`
+	Result string `json:"result"`
+}
+
+type callbackResponse struct {
+	Results []resultDTO `json:"results"`
+}
+
+// Callback applies the reported statuses and answers with a result per
+// order. Applying a status is idempotent, so the accrual system may deliver
+// the whole callback again after 500 Internal Server Error.
+func (h *accrualCallbackHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	var req callbackRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	updates := make([]entity.Order, 0, len(req))
+	for _, update := range req {
+		updates = append(updates, entity.Order{
+			Number:  update.Order,
+			Status:  update.status,
+			Accrual: update.accrual,
+		})
+	}
+
+	errs := h.service.Apply(r.Context(), updates)
+
+	status := http.StatusOK
+	res := callbackResponse{Results: make([]resultDTO, 0, len(updates))}
+	for i, update := range updates {
+		result := "applied"
+		switch {
+		case errs[i] == nil:
+		case errors.Is(errs[i], common.ErrNonExistentOrder):
+			result = "unknown_order"
+		case errors.Is(errs[i], common.ErrIllegalStatusTransition):
+			result = "illegal_transition"
+		default:
+			result = "error"
+			status = http.StatusInternalServerError
+		}
+		res.Results = append(res.Results, resultDTO{Order: update.Number, Result: result})
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, res)
+}
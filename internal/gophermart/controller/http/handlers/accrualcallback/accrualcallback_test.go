@@ -0,0 +1,161 @@
+package accrualcallback
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// keyMiddleware authorizes every request with key.
+type keyMiddleware struct {
+	key entity.APIKey
+}
+
+func (m keyMiddleware) Validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middlewares.APIKeyKey("APIKey"), m.key)))
+	})
+}
+
+func newTestRouter(service callbackService, key entity.APIKey) chi.Router {
+	router := chi.NewRouter()
+	router.Use(keyMiddleware{key: key}.Validate)
+	NewAccrualCallbackHandler(service)(router)
+	return router
+}
+
+func TestAccrualCallbackHandler_Callback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockcallbackService(ctrl)
+	key := entity.APIKey{ID: 5, Scopes: []entity.APIKeyScope{entity.ScopeAccrualCallback}}
+	router := newTestRouter(mockService, key)
+	accrual := entity.Money(72998)
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "applied",
+			body: `[{"order": "12345678903", "status": "PROCESSED", "accrual": 729.98},
+				{"order": "79927398713", "status": "REGISTERED"}]`,
+			setupMock: func() {
+				mockService.EXPECT().
+					Apply(gomock.Any(), []entity.Order{
+						{Number: "12345678903", Status: entity.OrderProcessed, Accrual: &accrual},
+						{Number: "79927398713", Status: entity.OrderNew},
+					}).
+					Return([]error{nil, nil})
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"results":[{"order":"12345678903","result":"applied"},{"order":"79927398713","result":"applied"}]}`,
+		},
+		{
+			name: "rejected updates",
+			body: `[{"order": "12345678903", "status": "PROCESSING"}, {"order": "79927398713", "status": "INVALID"}]`,
+			setupMock: func() {
+				mockService.EXPECT().
+					Apply(gomock.Any(), gomock.Any()).
+					Return([]error{common.ErrNonExistentOrder, common.ErrIllegalStatusTransition})
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"results":[{"order":"12345678903","result":"unknown_order"},{"order":"79927398713","result":"illegal_transition"}]}`,
+		},
+		{
+			// Повторная доставка безопасна, поэтому при ошибке просим повторить
+			name: "internal error",
+			body: `[{"order": "12345678903", "status": "PROCESSING"}, {"order": "79927398713", "status": "INVALID"}]`,
+			setupMock: func() {
+				mockService.EXPECT().
+					Apply(gomock.Any(), gomock.Any()).
+					Return([]error{nil, common.ErrInternalError})
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"results":[{"order":"12345678903","result":"applied"},{"order":"79927398713","result":"error"}]}`,
+		},
+		{
+			name:         "empty list",
+			body:         `[]`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "too many orders",
+			body:         "[" + strings.Repeat(`{"order": "12345678903", "status": "PROCESSING"},`, maxUpdates) + `{"order": "12345678903", "status": "PROCESSING"}]`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "invalid number",
+			body:         `[{"order": "12345678900", "status": "PROCESSING"}]`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"errors":[{"field":"[0].order","message":"is not a valid order number"}]}`,
+		},
+		{
+			name:         "unknown status",
+			body:         `[{"order": "12345678903", "status": "DONE"}]`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "accrual of unprocessed order",
+			body:         `[{"order": "12345678903", "status": "PROCESSING", "accrual": 10}]`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "negative accrual",
+			body:         `[{"order": "12345678903", "status": "PROCESSED", "accrual": -10}]`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "single object",
+			body:         `{"order": "12345678903", "status": "PROCESSED"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+
+	t.Run("key without scope", func(t *testing.T) {
+		router := newTestRouter(mockService, entity.APIKey{ID: 6, Scopes: []entity.APIKeyScope{entity.ScopeAccrualWrite}})
+
+		req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(`[{"order": "12345678903", "status": "PROCESSED"}]`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,149 @@
+package accrualengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+)
+
+type accrualEngine interface {
+	AddRule(ctx context.Context, rule entity.RewardRule) error
+	RegisterOrder(ctx context.Context, number string, goods []entity.Good) error
+}
+
+type accrualEngineHandler struct {
+	engine accrualEngine
+}
+
+// NewAccrualEngineHandler registers the endpoints of the built-in accrual
+// engine. They accept the same requests as the accrual system, so that a shop
+// does not depend on which one is used. They are mounted with the partner
+// endpoints and authorized by API key the same way.
+func NewAccrualEngineHandler(engine accrualEngine) func(chi.Router) {
+	h := &accrualEngineHandler{
+		engine: engine,
+	}
+	return func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireScope(entity.ScopeAccrualWrite))
+			r.Post("/orders", h.RegisterOrder)
+			r.Post("/goods", h.AddRule)
+		})
+	}
+}
+
+type goodRequest struct {
+	Description string      `json:"description"`
+	Price       json.Number `json:"price"`
+}
+
+type registerOrderRequest struct {
+	Order string        `json:"order"`
+	Goods []goodRequest `json:"goods"`
+
+	goods []entity.Good
+}
+
+func (req *registerOrderRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.OrderNumber("order", req.Order)
+	req.goods = make([]entity.Good, 0, len(req.Goods))
+	for _, good := range req.Goods {
+		price, ok := errs.Amount("price", good.Price)
+		if ok && price < 0 {
+			errs.Add("price", "must not be negative")
+		} else if ok && price > entity.MaxPrice {
+			errs.Add("price", "must not exceed "+entity.MaxPrice.String())
+		}
+		req.goods = append(req.goods, entity.Good{Description: good.Description, Price: price})
+	}
+	return errs
+}
+
+func (h *accrualEngineHandler) RegisterOrder(w http.ResponseWriter, r *http.Request) {
+	var req registerOrderRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	err := h.engine.RegisterOrder(r.Context(), req.Order, req.goods)
+	if err == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if errors.Is(err, common.ErrAccrualOrderExists) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidOrderNumber) || errors.Is(err, common.ErrInvalidAmount) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+type ruleRequest struct {
+	Match      string      `json:"match"`
+	Reward     json.Number `json:"reward"`
+	RewardType string      `json:"reward_type"`
+
+	reward entity.Money
+}
+
+func (req *ruleRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Required("match", req.Match)
+	req.reward, _ = errs.PositiveAmount("reward", req.Reward)
+	switch entity.RewardType(req.RewardType) {
+	case entity.RewardPercent:
+		if req.reward > entity.MaxPercentReward {
+			errs.Add("reward", "must not exceed 100 percent")
+		}
+	case entity.RewardPoints:
+	default:
+		errs.Add("reward_type", `must be "%" or "pt"`)
+	}
+	return errs
+}
+
+func (h *accrualEngineHandler) AddRule(w http.ResponseWriter, r *http.Request) {
+	var req ruleRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	rule := entity.RewardRule{
+		Match:      req.Match,
+		Reward:     req.reward,
+		RewardType: entity.RewardType(req.RewardType),
+	}
+	err := h.engine.AddRule(r.Context(), rule)
+	if err == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if errors.Is(err, common.ErrRewardRuleExists) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidRewardRule) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
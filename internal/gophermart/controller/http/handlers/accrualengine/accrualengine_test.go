@@ -0,0 +1,206 @@
+package accrualengine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// keyMiddleware authorizes every request with key.
+type keyMiddleware struct {
+	key entity.APIKey
+}
+
+func (m keyMiddleware) Validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middlewares.APIKeyKey("APIKey"), m.key)))
+	})
+}
+
+func newTestRouter(engine accrualEngine, key entity.APIKey) chi.Router {
+	router := chi.NewRouter()
+	router.Use(keyMiddleware{key: key}.Validate)
+	NewAccrualEngineHandler(engine)(router)
+	return router
+}
+
+func TestAccrualEngineHandler_RegisterOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEngine := NewMockaccrualEngine(ctrl)
+	router := newTestRouter(mockEngine, entity.APIKey{ID: 5, Scopes: []entity.APIKeyScope{entity.ScopeAccrualWrite}})
+	goods := []entity.Good{
+		{Description: "Чайник Bork", Price: 700000},
+		{Description: "Утюг", Price: 299999},
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    func()
+		expectedCode int
+	}{
+		{
+			name: "registered",
+			body: `{"order": "12345678903", "goods": [{"description": "Чайник Bork", "price": 7000}, {"description": "Утюг", "price": 2999.99}]}`,
+			setupMock: func() {
+				mockEngine.EXPECT().RegisterOrder(gomock.Any(), "12345678903", goods).Return(nil)
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "already registered",
+			body: `{"order": "12345678903", "goods": []}`,
+			setupMock: func() {
+				mockEngine.EXPECT().
+					RegisterOrder(gomock.Any(), "12345678903", []entity.Good{}).
+					Return(common.ErrAccrualOrderExists)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "engine error",
+			body: `{"order": "12345678903", "goods": []}`,
+			setupMock: func() {
+				mockEngine.EXPECT().
+					RegisterOrder(gomock.Any(), "12345678903", []entity.Good{}).
+					Return(common.ErrInternalError)
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "invalid number",
+			body:         `{"order": "12345678900", "goods": []}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "negative price",
+			body:         `{"order": "12345678903", "goods": [{"description": "Утюг", "price": -1}]}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "price too large",
+			body:         `{"order": "12345678903", "goods": [{"description": "Утюг", "price": 10000000000.01}]}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "price out of range",
+			body:         `{"order": "12345678903", "goods": [{"description": "Утюг", "price": 100000000000000000000}]}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "malformed body",
+			body:         `{"order": 12345678903}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestAccrualEngineHandler_AddRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEngine := NewMockaccrualEngine(ctrl)
+	router := newTestRouter(mockEngine, entity.APIKey{ID: 5, Scopes: []entity.APIKeyScope{entity.ScopeAccrualWrite}})
+	rule := entity.RewardRule{Match: "Bork", Reward: 750, RewardType: entity.RewardPercent}
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    func()
+		expectedCode int
+	}{
+		{
+			name: "added",
+			body: `{"match": "Bork", "reward": 7.5, "reward_type": "%"}`,
+			setupMock: func() {
+				mockEngine.EXPECT().AddRule(gomock.Any(), rule).Return(nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "duplicate",
+			body: `{"match": "Bork", "reward": 7.5, "reward_type": "%"}`,
+			setupMock: func() {
+				mockEngine.EXPECT().AddRule(gomock.Any(), rule).Return(common.ErrRewardRuleExists)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "unknown reward type",
+			body:         `{"match": "Bork", "reward": 10, "reward_type": "x"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "zero reward",
+			body:         `{"match": "Bork", "reward": 0, "reward_type": "pt"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "percent above price",
+			body:         `{"match": "Bork", "reward": 100.01, "reward_type": "%"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "malformed body",
+			body:         `{"match": "Bork"`,
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/goods", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+
+	t.Run("key without scope", func(t *testing.T) {
+		router := newTestRouter(mockEngine, entity.APIKey{ID: 4, Scopes: []entity.APIKeyScope{entity.ScopeOrdersWrite}})
+
+		req := httptest.NewRequest(http.MethodPost, "/goods", strings.NewReader(`{"match": "Bork", "reward": 10, "reward_type": "%"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: accrualengine.go
+
+// Package accrualengine is a generated GoMock package.
+package accrualengine
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockaccrualEngine is a mock of accrualEngine interface.
+type MockaccrualEngine struct {
+	ctrl     *gomock.Controller
+	recorder *MockaccrualEngineMockRecorder
+}
+
+// MockaccrualEngineMockRecorder is the mock recorder for MockaccrualEngine.
+type MockaccrualEngineMockRecorder struct {
+	mock *MockaccrualEngine
+}
+
+// NewMockaccrualEngine creates a new mock instance.
+func NewMockaccrualEngine(ctrl *gomock.Controller) *MockaccrualEngine {
+	mock := &MockaccrualEngine{ctrl: ctrl}
+	mock.recorder = &MockaccrualEngineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockaccrualEngine) EXPECT() *MockaccrualEngineMockRecorder {
+	return m.recorder
+}
+
+// AddRule mocks base method.
+func (m *MockaccrualEngine) AddRule(ctx context.Context, rule entity.RewardRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRule indicates an expected call of AddRule.
+func (mr *MockaccrualEngineMockRecorder) AddRule(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRule", reflect.TypeOf((*MockaccrualEngine)(nil).AddRule), ctx, rule)
+}
+
+// RegisterOrder mocks base method.
+func (m *MockaccrualEngine) RegisterOrder(ctx context.Context, number string, goods []entity.Good) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterOrder", ctx, number, goods)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterOrder indicates an expected call of RegisterOrder.
+func (mr *MockaccrualEngineMockRecorder) RegisterOrder(ctx, number, goods interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOrder", reflect.TypeOf((*MockaccrualEngine)(nil).RegisterOrder), ctx, number, goods)
+}
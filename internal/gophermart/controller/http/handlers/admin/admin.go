@@ -0,0 +1,589 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/utils"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type adminService interface {
+	SearchUsers(ctx context.Context, prefix string) ([]entity.User, error)
+	SetExternalID(ctx context.Context, actorID int64, userID int64, externalID string) error
+	Requeue(ctx context.Context, actorID int64, number string) error
+	InvalidateOrder(ctx context.Context, actorID int64, number string, reason string) error
+	Adjust(ctx context.Context, actorID int64, userID int64, amount entity.Money, reason string) error
+	Unlock(ctx context.Context, actorID int64, login string) error
+	AuditLog(ctx context.Context, limit int, offset int) ([]entity.AuditEntry, error)
+}
+
+type queueService interface {
+	DeadLetters(ctx context.Context) ([]entity.DeadLetter, error)
+}
+
+type ledgerService interface {
+	Check(ctx context.Context) ([]entity.BalanceMismatch, error)
+}
+
+type orderService interface {
+	GetAll(ctx context.Context, userID int64) ([]entity.Order, error)
+}
+
+type balanceService interface {
+	Get(ctx context.Context, userID int64) (entity.Balance, error)
+}
+
+type withdrawalService interface {
+	GetAll(ctx context.Context, userID int64) ([]entity.Withdrawal, error)
+}
+
+type adminHandler struct {
+	adminSvc      adminService
+	queueSvc      queueService
+	ledgerSvc     ledgerService
+	orderSvc      orderService
+	balanceSvc    balanceService
+	withdrawalSvc withdrawalService
+}
+
+// NewAdminHandler registers the operator endpoints. They are meant for the
+// support and admin roles, see http.Controller.ProtectHandler. Support only
+// looks up users, their orders, withdrawals and balances and the orders stuck
+// in the queue, to answer customers. Every endpoint changing anything, the
+// ledger check and the audit log of operator actions require the admin role.
+func NewAdminHandler(
+	adminSvc adminService,
+	queueSvc queueService,
+	ledgerSvc ledgerService,
+	orderSvc orderService,
+	balanceSvc balanceService,
+	withdrawalSvc withdrawalService,
+) func(chi.Router) {
+	h := &adminHandler{
+		adminSvc:      adminSvc,
+		queueSvc:      queueSvc,
+		ledgerSvc:     ledgerSvc,
+		orderSvc:      orderSvc,
+		balanceSvc:    balanceSvc,
+		withdrawalSvc: withdrawalSvc,
+	}
+	adminOnly := middlewares.RequireRole(entity.RoleAdmin)
+	return func(r chi.Router) {
+		r.Route("/orders", func(r chi.Router) {
+			r.Get("/dead-letters", h.GetDeadLetters)
+			r.With(adminOnly).Post("/{number}/requeue", h.Requeue)
+			r.With(adminOnly).Post("/{number}/invalidate", h.Invalidate)
+		})
+		r.Route("/ledger", func(r chi.Router) {
+			r.With(adminOnly).Get("/mismatches", h.GetMismatches)
+			r.With(adminOnly).Post("/users/{userID}/adjustments", h.Adjust)
+		})
+		r.Route("/users", func(r chi.Router) {
+			r.Get("/", h.SearchUsers)
+			r.Get("/{userID}/orders", h.GetOrders)
+			r.Get("/{userID}/withdrawals", h.GetWithdrawals)
+			r.Get("/{userID}/balance", h.GetBalance)
+			r.With(adminOnly).Put("/{userID}/external-id", h.SetExternalID)
+			r.With(adminOnly).Post("/{login}/unlock", h.Unlock)
+		})
+		r.With(adminOnly).Get("/audit", h.GetAuditLog)
+	}
+}
+
+// userIDParam reads the userID URL parameter. It writes 400 and returns
+// false if the parameter is not a number.
+func userIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return 0, false
+	}
+	return userID, true
+}
+
+type userDTO struct {
+	ID         int64        `json:"id"`
+	Login      string       `json:"login"`
+	Role       string       `json:"role"`
+	ExternalID *string      `json:"external_id,omitempty"`
+	Balance    entity.Money `json:"balance"`
+	Withdrawn  entity.Money `json:"withdrawn"`
+}
+
+// SearchUsers finds the users whose login starts with the login query
+// parameter.
+func (h *adminHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	login := r.URL.Query().Get("login")
+	if login == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	users, err := h.adminSvc.SearchUsers(r.Context(), login)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(users) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	usersDto := make([]userDTO, 0, len(users))
+	for _, user := range users {
+		usersDto = append(usersDto, userDTO{
+			ID:         user.ID,
+			Login:      user.Login,
+			Role:       string(user.Role),
+			ExternalID: user.ExternalID,
+			Balance:    user.Balance,
+			Withdrawn:  user.Withdrawn,
+		})
+	}
+
+	render.JSON(w, r, usersDto)
+}
+
+type externalIDRequest struct {
+	ExternalID string `json:"external_id"`
+}
+
+func (req *externalIDRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Length("external_id", req.ExternalID, 0, 64)
+	return errs
+}
+
+// SetExternalID sets the id partner systems know the user by. An empty id
+// removes it.
+func (h *adminHandler) SetExternalID(w http.ResponseWriter, r *http.Request) {
+	actorID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req externalIDRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	err = h.adminSvc.SetExternalID(r.Context(), actorID, userID, req.ExternalID)
+	if err == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if errors.Is(err, common.ErrUserNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if errors.Is(err, common.ErrExternalIDTaken) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+type orderDTO struct {
+	Number     string        `json:"number"`
+	Status     string        `json:"status"`
+	Accrual    *entity.Money `json:"accrual,omitempty"`
+	Attempts   int           `json:"attempts"`
+	UploadedAt string        `json:"uploaded_at"`
+}
+
+func (h *adminHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	orders, err := h.orderSvc.GetAll(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(orders) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	ordersDto := make([]orderDTO, 0, len(orders))
+	for _, o := range orders {
+		ordersDto = append(ordersDto, orderDTO{
+			Number:     o.Number,
+			Status:     string(o.Status),
+			Accrual:    o.Accrual,
+			Attempts:   o.Attempts,
+			UploadedAt: o.UploadedAt.Format(time.RFC3339),
+		})
+	}
+
+	render.JSON(w, r, ordersDto)
+}
+
+type withdrawalDTO struct {
+	Order       string       `json:"order"`
+	Sum         entity.Money `json:"sum"`
+	ProcessedAt string       `json:"processed_at"`
+}
+
+func (h *adminHandler) GetWithdrawals(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	withdrawals, err := h.withdrawalSvc.GetAll(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(withdrawals) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	withdrawalsDto := make([]withdrawalDTO, 0, len(withdrawals))
+	for _, withdrawal := range withdrawals {
+		withdrawalsDto = append(withdrawalsDto, withdrawalDTO{
+			Order:       withdrawal.Order,
+			Sum:         withdrawal.Sum,
+			ProcessedAt: withdrawal.ProcessedAt.Format(time.RFC3339),
+		})
+	}
+
+	render.JSON(w, r, withdrawalsDto)
+}
+
+type balanceDTO struct {
+	Current   entity.Money `json:"current"`
+	Withdrawn entity.Money `json:"withdrawn"`
+}
+
+func (h *adminHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	balance, err := h.balanceSvc.Get(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, balanceDTO{Current: balance.Balance, Withdrawn: balance.Withdrawn})
+}
+
+type deadLetterDTO struct {
+	Number         string `json:"number"`
+	UserID         int64  `json:"user_id"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	LastError      string `json:"last_error"`
+	UploadedAt     string `json:"uploaded_at"`
+	DeadLetteredAt string `json:"dead_lettered_at"`
+}
+
+func (h *adminHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	deadLetters, err := h.queueSvc.DeadLetters(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(deadLetters) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	deadLettersDto := make([]deadLetterDTO, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		deadLettersDto = append(deadLettersDto, deadLetterDTO{
+			Number:         deadLetter.Number,
+			UserID:         deadLetter.UserID,
+			Status:         string(deadLetter.Status),
+			Attempts:       deadLetter.Attempts,
+			LastError:      deadLetter.LastError,
+			UploadedAt:     deadLetter.UploadedAt.Format(time.RFC3339),
+			DeadLetteredAt: deadLetter.DeadLetteredAt.Format(time.RFC3339),
+		})
+	}
+
+	render.JSON(w, r, deadLettersDto)
+}
+
+// Requeue makes a pending order due, so that the accrual system is polled
+// for it again.
+func (h *adminHandler) Requeue(w http.ResponseWriter, r *http.Request) {
+	actorID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	number := chi.URLParam(r, "number")
+
+	err = h.adminSvc.Requeue(r.Context(), actorID, number)
+	if err == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if errors.Is(err, common.ErrNonExistentOrder) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if errors.Is(err, common.ErrIllegalStatusTransition) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+type invalidationRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (req *invalidationRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Required("reason", req.Reason)
+	return errs
+}
+
+// Invalidate marks a pending order INVALID, so that it is not polled and
+// accrues nothing.
+func (h *adminHandler) Invalidate(w http.ResponseWriter, r *http.Request) {
+	actorID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	number := chi.URLParam(r, "number")
+
+	var req invalidationRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	err = h.adminSvc.InvalidateOrder(r.Context(), actorID, number, req.Reason)
+	if err == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidReason) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if errors.Is(err, common.ErrNonExistentOrder) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if errors.Is(err, common.ErrIllegalStatusTransition) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+type mismatchDTO struct {
+	UserID          int64        `json:"user_id"`
+	Balance         entity.Money `json:"balance"`
+	Withdrawn       entity.Money `json:"withdrawn"`
+	LedgerBalance   entity.Money `json:"ledger_balance"`
+	LedgerWithdrawn entity.Money `json:"ledger_withdrawn"`
+}
+
+func (h *adminHandler) GetMismatches(w http.ResponseWriter, r *http.Request) {
+	mismatches, err := h.ledgerSvc.Check(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(mismatches) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	mismatchesDto := make([]mismatchDTO, 0, len(mismatches))
+	for _, m := range mismatches {
+		mismatchesDto = append(mismatchesDto, mismatchDTO(m))
+	}
+
+	render.JSON(w, r, mismatchesDto)
+}
+
+type adjustmentRequest struct {
+	Amount json.Number `json:"amount"`
+	Reason string      `json:"reason"`
+
+	amount entity.Money
+}
+
+func (req *adjustmentRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	if amount, ok := errs.Amount("amount", req.Amount); ok {
+		if amount == 0 {
+			errs.Add("amount", "must not be zero")
+		}
+		req.amount = amount
+	}
+	errs.Required("reason", req.Reason)
+	return errs
+}
+
+func (h *adminHandler) Adjust(w http.ResponseWriter, r *http.Request) {
+	actorID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	userID, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req adjustmentRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	err = h.adminSvc.Adjust(r.Context(), actorID, userID, req.amount, req.Reason)
+	if err == nil {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidAmount) {
+		validation.WriteError(w, r, validation.Errors{{Field: "amount", Message: "must not be zero"}})
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidReason) {
+		validation.WriteError(w, r, validation.Errors{{Field: "reason", Message: "is required"}})
+		return
+	}
+
+	if errors.Is(err, common.ErrUserNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if errors.Is(err, common.ErrInsufficientBalance) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+// Unlock lifts the lockout of a login after too many failed login attempts.
+func (h *adminHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	actorID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	login := chi.URLParam(r, "login")
+	if login == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminSvc.Unlock(r.Context(), actorID, login); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type auditEntryDTO struct {
+	ID        int64         `json:"id"`
+	ActorID   int64         `json:"actor_id"`
+	Action    string        `json:"action"`
+	Target    string        `json:"target"`
+	Amount    *entity.Money `json:"amount,omitempty"`
+	Reason    string        `json:"reason,omitempty"`
+	CreatedAt string        `json:"created_at"`
+}
+
+// GetAuditLog returns a page (limit, offset) of the audit log, the latest
+// entries first.
+func (h *adminHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	var limit, offset int
+	var err error
+	query := r.URL.Query()
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := h.adminSvc.AuditLog(r.Context(), limit, offset)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidHistoryFilter) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	entriesDto := make([]auditEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		entriesDto = append(entriesDto, auditEntryDTO{
+			ID:        entry.ID,
+			ActorID:   entry.ActorID,
+			Action:    string(entry.Action),
+			Target:    entry.Target,
+			Amount:    entry.Amount,
+			Reason:    entry.Reason,
+			CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	render.JSON(w, r, entriesDto)
+}
@@ -0,0 +1,750 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func withActor(req *http.Request, actorID int64) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), middlewares.UserIDKey("UserID"), actorID))
+}
+
+func TestNewAdminHandler_roles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Проверяется только доступ, поэтому сервисы принимают любые вызовы.
+	mockAdminSvc := NewMockadminService(ctrl)
+	mockAdminSvc.EXPECT().SearchUsers(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockAdminSvc.EXPECT().SetExternalID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockAdminSvc.EXPECT().Requeue(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockAdminSvc.EXPECT().InvalidateOrder(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockAdminSvc.EXPECT().Adjust(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockAdminSvc.EXPECT().Unlock(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockAdminSvc.EXPECT().AuditLog(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockQueueSvc := NewMockqueueService(ctrl)
+	mockQueueSvc.EXPECT().DeadLetters(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockLedgerSvc := NewMockledgerService(ctrl)
+	mockLedgerSvc.EXPECT().Check(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockOrderSvc := NewMockorderService(ctrl)
+	mockOrderSvc.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockBalanceSvc := NewMockbalanceService(ctrl)
+	mockBalanceSvc.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.Balance{}, nil).AnyTimes()
+	mockWithdrawalSvc := NewMockwithdrawalService(ctrl)
+	mockWithdrawalSvc.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	router := chi.NewRouter()
+	NewAdminHandler(
+		mockAdminSvc,
+		mockQueueSvc,
+		mockLedgerSvc,
+		mockOrderSvc,
+		mockBalanceSvc,
+		mockWithdrawalSvc,
+	)(router)
+
+	routes := []struct {
+		method  string
+		pattern string
+		target  string
+		support bool
+	}{
+		{http.MethodGet, "/orders/dead-letters", "/orders/dead-letters", true},
+		{http.MethodPost, "/orders/{number}/requeue", "/orders/12345678903/requeue", false},
+		{http.MethodPost, "/orders/{number}/invalidate", "/orders/12345678903/invalidate", false},
+		{http.MethodGet, "/ledger/mismatches", "/ledger/mismatches", false},
+		{http.MethodPost, "/ledger/users/{userID}/adjustments", "/ledger/users/2/adjustments", false},
+		{http.MethodGet, "/users/", "/users/?login=test", true},
+		{http.MethodGet, "/users/{userID}/orders", "/users/2/orders", true},
+		{http.MethodGet, "/users/{userID}/withdrawals", "/users/2/withdrawals", true},
+		{http.MethodGet, "/users/{userID}/balance", "/users/2/balance", true},
+		{http.MethodPut, "/users/{userID}/external-id", "/users/2/external-id", false},
+		{http.MethodPost, "/users/{login}/unlock", "/users/testuser/unlock", false},
+		{http.MethodGet, "/audit", "/audit", false},
+	}
+
+	t.Run("every route is covered", func(t *testing.T) {
+		var registered, covered []string
+		err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			registered = append(registered, method+" "+route)
+			return nil
+		})
+		assert.NoError(t, err)
+		for _, route := range routes {
+			covered = append(covered, route.method+" "+route.pattern)
+		}
+		assert.ElementsMatch(t, registered, covered)
+	})
+
+	for _, route := range routes {
+		for _, role := range []entity.Role{entity.RoleSupport, entity.RoleAdmin} {
+			allowed := role == entity.RoleAdmin || route.support
+			t.Run(fmt.Sprintf("%s %s %s", role, route.method, route.pattern), func(t *testing.T) {
+				req := httptest.NewRequest(route.method, route.target, nil)
+				claims := entity.AccessClaims{UserID: 1, Role: role}
+				req = req.WithContext(context.WithValue(req.Context(), middlewares.ClaimsKey("Claims"), claims))
+				req = withActor(req, claims.UserID)
+				rr := httptest.NewRecorder()
+
+				router.ServeHTTP(rr, req)
+
+				if allowed {
+					assert.NotEqual(t, http.StatusForbidden, rr.Code)
+					assert.NotEqual(t, http.StatusNotFound, rr.Code)
+				} else {
+					assert.Equal(t, http.StatusForbidden, rr.Code)
+				}
+			})
+		}
+	}
+}
+
+func TestAdminHandler_GetDeadLetters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueueSvc := NewMockqueueService(ctrl)
+	h := &adminHandler{queueSvc: mockQueueSvc}
+
+	deadLetteredAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	deadLetters := []entity.DeadLetter{
+		{
+			Order: entity.Order{
+				UserID:     1,
+				Number:     "12345678903",
+				Status:     entity.OrderNew,
+				UploadedAt: deadLetteredAt.Add(-time.Hour),
+				Attempts:   10,
+			},
+			LastError:      common.ErrNonExistentOrder.Error(),
+			DeadLetteredAt: deadLetteredAt,
+		},
+	}
+
+	t.Run("successful retrieval", func(t *testing.T) {
+		mockQueueSvc.EXPECT().
+			DeadLetters(gomock.Any()).
+			Return(deadLetters, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/orders/dead-letters", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetDeadLetters(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response []deadLetterDTO
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []deadLetterDTO{
+			{
+				Number:         "12345678903",
+				UserID:         1,
+				Status:         string(entity.OrderNew),
+				Attempts:       10,
+				LastError:      common.ErrNonExistentOrder.Error(),
+				UploadedAt:     "2025-01-02T02:04:05Z",
+				DeadLetteredAt: "2025-01-02T03:04:05Z",
+			},
+		}, response)
+	})
+
+	t.Run("no dead letters", func(t *testing.T) {
+		mockQueueSvc.EXPECT().
+			DeadLetters(gomock.Any()).
+			Return(nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/orders/dead-letters", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetDeadLetters(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("internal server error", func(t *testing.T) {
+		mockQueueSvc.EXPECT().
+			DeadLetters(gomock.Any()).
+			Return(nil, errors.New("database error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/orders/dead-letters", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetDeadLetters(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
+
+func TestAdminHandler_Requeue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminSvc := NewMockadminService(ctrl)
+	h := &adminHandler{adminSvc: mockAdminSvc}
+	number := "12345678903"
+
+	tests := []struct {
+		name         string
+		svcErr       error
+		expectedCode int
+	}{
+		{"successful requeue", nil, http.StatusAccepted},
+		{"unknown order", common.ErrNonExistentOrder, http.StatusNotFound},
+		{"order is final", common.ErrIllegalStatusTransition, http.StatusConflict},
+		{"internal server error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdminSvc.EXPECT().
+				Requeue(gomock.Any(), int64(7), number).
+				Return(tt.svcErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/orders/"+number+"/requeue", nil)
+			req = withActor(withURLParam(req, "number", number), 7)
+			rr := httptest.NewRecorder()
+
+			h.Requeue(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestAdminHandler_GetMismatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLedgerSvc := NewMockledgerService(ctrl)
+	h := &adminHandler{ledgerSvc: mockLedgerSvc}
+
+	t.Run("mismatches found", func(t *testing.T) {
+		mockLedgerSvc.EXPECT().
+			Check(gomock.Any()).
+			Return([]entity.BalanceMismatch{
+				{UserID: 1, Balance: 72998, Withdrawn: 100, LedgerBalance: 72898, LedgerWithdrawn: 100},
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/ledger/mismatches", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetMismatches(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t,
+			`[{"user_id":1,"balance":729.98,"withdrawn":1,"ledger_balance":728.98,"ledger_withdrawn":1}]`,
+			rr.Body.String())
+	})
+
+	t.Run("consistent", func(t *testing.T) {
+		mockLedgerSvc.EXPECT().
+			Check(gomock.Any()).
+			Return(nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/ledger/mismatches", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetMismatches(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("internal server error", func(t *testing.T) {
+		mockLedgerSvc.EXPECT().
+			Check(gomock.Any()).
+			Return(nil, errors.New("database error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/ledger/mismatches", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetMismatches(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
+
+func TestAdminHandler_Adjust(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminSvc := NewMockadminService(ctrl)
+	h := &adminHandler{adminSvc: mockAdminSvc}
+
+	tests := []struct {
+		name          string
+		userID        string
+		body          string
+		setupMock     func()
+		expectedCode  int
+		expectedField string
+	}{
+		{
+			name:   "successful adjustment",
+			userID: "1",
+			body:   `{"amount": -10.5, "reason": "duplicate accrual"}`,
+			setupMock: func() {
+				mockAdminSvc.EXPECT().
+					Adjust(gomock.Any(), int64(7), int64(1), entity.Money(-1050), "duplicate accrual").
+					Return(nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid user id",
+			userID:       "abc",
+			body:         `{"amount": 1, "reason": "bonus"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "malformed body",
+			userID:       "1",
+			body:         `{"amount": 1, "reason": "bonus", "type": "ACCRUAL"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "too precise amount",
+			userID:       "1",
+			body:         `{"amount": 0.001, "reason": "bonus"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "zero amount without reason",
+			userID:       "1",
+			body:         `{"amount": 0, "reason": ""}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "zero amount rejected by service",
+			userID: "1",
+			body:   `{"amount": 1, "reason": "bonus"}`,
+			setupMock: func() {
+				mockAdminSvc.EXPECT().
+					Adjust(gomock.Any(), int64(7), int64(1), entity.Money(100), "bonus").
+					Return(common.ErrInvalidAmount)
+			},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedField: "amount",
+		},
+		{
+			name:   "reason rejected by service",
+			userID: "1",
+			body:   `{"amount": 1, "reason": "bonus"}`,
+			setupMock: func() {
+				mockAdminSvc.EXPECT().
+					Adjust(gomock.Any(), int64(7), int64(1), entity.Money(100), "bonus").
+					Return(common.ErrInvalidReason)
+			},
+			expectedCode:  http.StatusUnprocessableEntity,
+			expectedField: "reason",
+		},
+		{
+			name:   "user not found",
+			userID: "2",
+			body:   `{"amount": 1, "reason": "bonus"}`,
+			setupMock: func() {
+				mockAdminSvc.EXPECT().
+					Adjust(gomock.Any(), int64(7), int64(2), entity.Money(100), "bonus").
+					Return(common.ErrUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "insufficient balance",
+			userID: "1",
+			body:   `{"amount": -1000, "reason": "fraud"}`,
+			setupMock: func() {
+				mockAdminSvc.EXPECT().
+					Adjust(gomock.Any(), int64(7), int64(1), entity.Money(-100000), "fraud").
+					Return(common.ErrInsufficientBalance)
+			},
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/ledger/users/"+tt.userID+"/adjustments", strings.NewReader(tt.body))
+			req = withActor(withURLParam(req, "userID", tt.userID), 7)
+			rr := httptest.NewRecorder()
+
+			h.Adjust(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedField != "" {
+				assert.Contains(t, rr.Body.String(), `"field":"`+tt.expectedField+`"`)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_Unlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminSvc := NewMockadminService(ctrl)
+	h := &adminHandler{adminSvc: mockAdminSvc}
+
+	tests := []struct {
+		name         string
+		svcErr       error
+		expectedCode int
+	}{
+		{"successful unlock", nil, http.StatusNoContent},
+		{"internal server error", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdminSvc.EXPECT().
+				Unlock(gomock.Any(), int64(7), "testuser").
+				Return(tt.svcErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/testuser/unlock", nil)
+			req = withActor(withURLParam(req, "login", "testuser"), 7)
+			rr := httptest.NewRecorder()
+
+			h.Unlock(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestAdminHandler_SearchUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminSvc := NewMockadminService(ctrl)
+	h := &adminHandler{adminSvc: mockAdminSvc}
+
+	tests := []struct {
+		name         string
+		query        string
+		setupMock    func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:  "users found",
+			query: "?login=test",
+			setupMock: func() {
+				mockAdminSvc.EXPECT().
+					SearchUsers(gomock.Any(), "test").
+					Return([]entity.User{
+						{ID: 1, Login: "testuser", Role: entity.RoleUser, Balance: 1050, Withdrawn: 200},
+					}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"id":1,"login":"testuser","role":"user","balance":10.5,"withdrawn":2}]`,
+		},
+		{
+			name:  "no users",
+			query: "?login=nobody",
+			setupMock: func() {
+				mockAdminSvc.EXPECT().
+					SearchUsers(gomock.Any(), "nobody").
+					Return(nil, nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "missing login",
+			query:        "",
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "internal server error",
+			query: "?login=test",
+			setupMock: func() {
+				mockAdminSvc.EXPECT().
+					SearchUsers(gomock.Any(), "test").
+					Return(nil, common.ErrInternalError)
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodGet, "/users"+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			h.SearchUsers(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminHandler_userViews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderSvc := NewMockorderService(ctrl)
+	mockBalanceSvc := NewMockbalanceService(ctrl)
+	mockWithdrawalSvc := NewMockwithdrawalService(ctrl)
+	h := &adminHandler{orderSvc: mockOrderSvc, balanceSvc: mockBalanceSvc, withdrawalSvc: mockWithdrawalSvc}
+
+	uploadedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	accrual := entity.Money(50000)
+
+	t.Run("orders", func(t *testing.T) {
+		mockOrderSvc.EXPECT().
+			GetAll(gomock.Any(), int64(2)).
+			Return([]entity.Order{
+				{UserID: 2, Number: "12345678903", Status: entity.OrderProcessed, Accrual: &accrual, UploadedAt: uploadedAt, Attempts: 3},
+			}, nil)
+
+		req := withURLParam(httptest.NewRequest(http.MethodGet, "/users/2/orders", nil), "userID", "2")
+		rr := httptest.NewRecorder()
+
+		h.GetOrders(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t,
+			`[{"number":"12345678903","status":"PROCESSED","accrual":500,"attempts":3,"uploaded_at":"2025-01-02T03:04:05Z"}]`,
+			rr.Body.String())
+	})
+
+	t.Run("withdrawals", func(t *testing.T) {
+		mockWithdrawalSvc.EXPECT().
+			GetAll(gomock.Any(), int64(2)).
+			Return([]entity.Withdrawal{{Order: "2377225624", Sum: 50000, ProcessedAt: uploadedAt}}, nil)
+
+		req := withURLParam(httptest.NewRequest(http.MethodGet, "/users/2/withdrawals", nil), "userID", "2")
+		rr := httptest.NewRecorder()
+
+		h.GetWithdrawals(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[{"order":"2377225624","sum":500,"processed_at":"2025-01-02T03:04:05Z"}]`, rr.Body.String())
+	})
+
+	t.Run("no withdrawals", func(t *testing.T) {
+		mockWithdrawalSvc.EXPECT().
+			GetAll(gomock.Any(), int64(2)).
+			Return(nil, nil)
+
+		req := withURLParam(httptest.NewRequest(http.MethodGet, "/users/2/withdrawals", nil), "userID", "2")
+		rr := httptest.NewRecorder()
+
+		h.GetWithdrawals(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("balance", func(t *testing.T) {
+		mockBalanceSvc.EXPECT().
+			Get(gomock.Any(), int64(2)).
+			Return(entity.Balance{Balance: 72998, Withdrawn: 100}, nil)
+
+		req := withURLParam(httptest.NewRequest(http.MethodGet, "/users/2/balance", nil), "userID", "2")
+		rr := httptest.NewRecorder()
+
+		h.GetBalance(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"current":729.98,"withdrawn":1}`, rr.Body.String())
+	})
+
+	t.Run("balance error", func(t *testing.T) {
+		mockBalanceSvc.EXPECT().
+			Get(gomock.Any(), int64(2)).
+			Return(entity.Balance{}, errors.New("database error"))
+
+		req := withURLParam(httptest.NewRequest(http.MethodGet, "/users/2/balance", nil), "userID", "2")
+		rr := httptest.NewRecorder()
+
+		h.GetBalance(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		req := withURLParam(httptest.NewRequest(http.MethodGet, "/users/abc/orders", nil), "userID", "abc")
+		rr := httptest.NewRecorder()
+
+		h.GetOrders(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestAdminHandler_Invalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminSvc := NewMockadminService(ctrl)
+	h := &adminHandler{adminSvc: mockAdminSvc}
+	number := "12345678903"
+
+	tests := []struct {
+		name         string
+		body         string
+		svcErr       error
+		callsSvc     bool
+		expectedCode int
+	}{
+		{"successful invalidation", `{"reason": "fraud"}`, nil, true, http.StatusNoContent},
+		{"missing reason", `{"reason": ""}`, nil, false, http.StatusUnprocessableEntity},
+		{"malformed body", `{"reason": `, nil, false, http.StatusBadRequest},
+		{"unknown order", `{"reason": "fraud"}`, common.ErrNonExistentOrder, true, http.StatusNotFound},
+		{"order is final", `{"reason": "fraud"}`, common.ErrIllegalStatusTransition, true, http.StatusConflict},
+		{"internal server error", `{"reason": "fraud"}`, errors.New("database error"), true, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.callsSvc {
+				mockAdminSvc.EXPECT().
+					InvalidateOrder(gomock.Any(), int64(7), number, "fraud").
+					Return(tt.svcErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/orders/"+number+"/invalidate", strings.NewReader(tt.body))
+			req = withActor(withURLParam(req, "number", number), 7)
+			rr := httptest.NewRecorder()
+
+			h.Invalidate(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestAdminHandler_GetAuditLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminSvc := NewMockadminService(ctrl)
+	h := &adminHandler{adminSvc: mockAdminSvc}
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	amount := entity.Money(-1050)
+
+	t.Run("page of entries", func(t *testing.T) {
+		mockAdminSvc.EXPECT().
+			AuditLog(gomock.Any(), 10, 20).
+			Return([]entity.AuditEntry{
+				{ID: 2, ActorID: 7, Action: entity.AuditBalanceAdjusted, Target: "1", Amount: &amount, Reason: "duplicate accrual", CreatedAt: createdAt},
+				{ID: 1, ActorID: 7, Action: entity.AuditOrderRequeued, Target: "12345678903", CreatedAt: createdAt},
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/audit?limit=10&offset=20", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetAuditLog(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[
+			{"id":2,"actor_id":7,"action":"BALANCE_ADJUSTED","target":"1","amount":-10.5,"reason":"duplicate accrual","created_at":"2025-01-02T03:04:05Z"},
+			{"id":1,"actor_id":7,"action":"ORDER_REQUEUED","target":"12345678903","created_at":"2025-01-02T03:04:05Z"}
+		]`, rr.Body.String())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		mockAdminSvc.EXPECT().
+			AuditLog(gomock.Any(), 0, 0).
+			Return(nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetAuditLog(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("malformed limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/audit?limit=ten", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetAuditLog(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("negative offset", func(t *testing.T) {
+		mockAdminSvc.EXPECT().
+			AuditLog(gomock.Any(), 0, -1).
+			Return(nil, common.ErrInvalidHistoryFilter)
+
+		req := httptest.NewRequest(http.MethodGet, "/audit?offset=-1", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetAuditLog(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestAdminHandler_SetExternalID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminSvc := NewMockadminService(ctrl)
+	h := &adminHandler{adminSvc: mockAdminSvc}
+
+	tests := []struct {
+		name         string
+		body         string
+		svcErr       error
+		callsSvc     bool
+		expectedCode int
+	}{
+		{"successful update", `{"external_id": "pos-42"}`, nil, true, http.StatusNoContent},
+		{"unknown user", `{"external_id": "pos-42"}`, common.ErrUserNotFound, true, http.StatusNotFound},
+		{"taken by another user", `{"external_id": "pos-42"}`, common.ErrExternalIDTaken, true, http.StatusConflict},
+		{"too long", `{"external_id": "` + strings.Repeat("x", 65) + `"}`, nil, false, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.callsSvc {
+				mockAdminSvc.EXPECT().
+					SetExternalID(gomock.Any(), int64(7), int64(1), "pos-42").
+					Return(tt.svcErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/users/1/external-id", strings.NewReader(tt.body))
+			req = withActor(withURLParam(req, "userID", "1"), 7)
+			rr := httptest.NewRecorder()
+
+			h.SetExternalID(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
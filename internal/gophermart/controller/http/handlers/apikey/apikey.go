@@ -0,0 +1,178 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/utils"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type apiKeyService interface {
+	Create(ctx context.Context, actorID int64, name string, scopes []entity.APIKeyScope) (entity.APIKey, entity.Token, error)
+	List(ctx context.Context) ([]entity.APIKey, error)
+	Revoke(ctx context.Context, actorID int64, id int64) error
+}
+
+type apiKeyHandler struct {
+	service apiKeyService
+}
+
+// NewAPIKeyHandler registers the management of partner API keys. It is meant
+// to be mounted next to the admin handler: creating and revoking keys
+// requires the admin role.
+func NewAPIKeyHandler(service apiKeyService) func(chi.Router) {
+	h := &apiKeyHandler{
+		service: service,
+	}
+	adminOnly := middlewares.RequireRole(entity.RoleAdmin)
+	return func(r chi.Router) {
+		r.Route("/api-keys", func(r chi.Router) {
+			r.Get("/", h.List)
+			r.With(adminOnly).Post("/", h.Create)
+			r.With(adminOnly).Delete("/{id}", h.Revoke)
+		})
+	}
+}
+
+type apiKeyDTO struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Prefix    string   `json:"prefix"`
+	Scopes    []string `json:"scopes"`
+	CreatedBy int64    `json:"created_by"`
+	CreatedAt string   `json:"created_at"`
+	RevokedAt string   `json:"revoked_at,omitempty"`
+}
+
+func mapKeyToDTO(key entity.APIKey) apiKeyDTO {
+	dto := apiKeyDTO{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    make([]string, 0, len(key.Scopes)),
+		CreatedBy: key.CreatedBy,
+		CreatedAt: key.CreatedAt.Format(time.RFC3339),
+	}
+	for _, scope := range key.Scopes {
+		dto.Scopes = append(dto.Scopes, string(scope))
+	}
+	if key.RevokedAt != nil {
+		dto.RevokedAt = key.RevokedAt.Format(time.RFC3339)
+	}
+	return dto
+}
+
+func (h *apiKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.List(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(keys) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	keysDto := make([]apiKeyDTO, 0, len(keys))
+	for _, key := range keys {
+		keysDto = append(keysDto, mapKeyToDTO(key))
+	}
+
+	render.JSON(w, r, keysDto)
+}
+
+type createRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+
+	scopes []entity.APIKeyScope
+}
+
+func (req *createRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	if errs.Required("name", req.Name) {
+		errs.Length("name", req.Name, 1, 64)
+	}
+	if len(req.Scopes) == 0 {
+		errs.Add("scopes", "is required")
+	}
+	for _, s := range req.Scopes {
+		scope, err := entity.ParseAPIKeyScope(s)
+		if err != nil {
+			errs.Add("scopes", "unknown scope "+strconv.Quote(s))
+			continue
+		}
+		req.scopes = append(req.scopes, scope)
+	}
+	return errs
+}
+
+type createdKeyDTO struct {
+	apiKeyDTO
+	// Key and SigningSecret are shown only once.
+	Key           string `json:"key"`
+	SigningSecret string `json:"signing_secret"`
+}
+
+func (h *apiKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actorID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req createRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	key, token, err := h.service.Create(r.Context(), actorID, req.Name, req.scopes)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, createdKeyDTO{
+		apiKeyDTO:     mapKeyToDTO(key),
+		Key:           string(token),
+		SigningSecret: key.SigningSecret,
+	})
+}
+
+func (h *apiKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	actorID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Revoke(r.Context(), actorID, id)
+	if err == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if errors.Is(err, common.ErrAPIKeyNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
@@ -0,0 +1,143 @@
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequest(method, target, body string, role entity.Role) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	claims := entity.AccessClaims{UserID: 7, Role: role}
+	ctx := context.WithValue(req.Context(), middlewares.ClaimsKey("Claims"), claims)
+	ctx = context.WithValue(ctx, middlewares.UserIDKey("UserID"), claims.UserID)
+	return req.WithContext(ctx)
+}
+
+func TestAPIKeyHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockapiKeyService(ctrl)
+	router := chi.NewRouter()
+	NewAPIKeyHandler(mockService)(router)
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	key := entity.APIKey{
+		ID:        3,
+		Name:      "pos",
+		Prefix:    "gmk_abcdefgh",
+		Scopes:    []entity.APIKeyScope{entity.ScopeOrdersWrite},
+		CreatedBy: 7,
+		CreatedAt: createdAt,
+	}
+	created := key
+	created.SigningSecret = "gms_signing-secret"
+
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		body         string
+		role         entity.Role
+		setupMock    func()
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:   "support lists keys",
+			method: http.MethodGet,
+			target: "/api-keys",
+			role:   entity.RoleSupport,
+			setupMock: func() {
+				mockService.EXPECT().List(gomock.Any()).Return([]entity.APIKey{key}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"id":3,"name":"pos","prefix":"gmk_abcdefgh","scopes":["orders:write"],"created_by":7,"created_at":"2025-01-02T03:04:05Z"}]`,
+		},
+		{
+			name:   "admin creates key",
+			method: http.MethodPost,
+			target: "/api-keys",
+			body:   `{"name": "pos", "scopes": ["orders:write"]}`,
+			role:   entity.RoleAdmin,
+			setupMock: func() {
+				mockService.EXPECT().
+					Create(gomock.Any(), int64(7), "pos", []entity.APIKeyScope{entity.ScopeOrdersWrite}).
+					Return(created, entity.Token("gmk_abcdefgh-secret"), nil)
+			},
+			expectedCode: http.StatusCreated,
+			expectedBody: `{"id":3,"name":"pos","prefix":"gmk_abcdefgh","scopes":["orders:write"],"created_by":7,"created_at":"2025-01-02T03:04:05Z","key":"gmk_abcdefgh-secret","signing_secret":"gms_signing-secret"}`,
+		},
+		{
+			name:         "unknown scope",
+			method:       http.MethodPost,
+			target:       "/api-keys",
+			body:         `{"name": "pos", "scopes": ["orders:delete"]}`,
+			role:         entity.RoleAdmin,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "support can not create keys",
+			method:       http.MethodPost,
+			target:       "/api-keys",
+			body:         `{"name": "pos", "scopes": ["orders:write"]}`,
+			role:         entity.RoleSupport,
+			setupMock:    func() {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:   "admin revokes key",
+			method: http.MethodDelete,
+			target: "/api-keys/3",
+			role:   entity.RoleAdmin,
+			setupMock: func() {
+				mockService.EXPECT().Revoke(gomock.Any(), int64(7), int64(3)).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "revoke unknown key",
+			method: http.MethodDelete,
+			target: "/api-keys/4",
+			role:   entity.RoleAdmin,
+			setupMock: func() {
+				mockService.EXPECT().Revoke(gomock.Any(), int64(7), int64(4)).Return(common.ErrAPIKeyNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "support can not revoke keys",
+			method:       http.MethodDelete,
+			target:       "/api-keys/3",
+			role:         entity.RoleSupport,
+			setupMock:    func() {},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, newRequest(tt.method, tt.target, tt.body, tt.role))
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -2,20 +2,28 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"io"
 	"net/http"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/utils"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
 )
 
 type authService interface {
-	Login(ctx context.Context, user entity.User) (entity.Token, error)
-	RegisterNewUser(ctx context.Context, user entity.User) (entity.Token, error)
+	Login(ctx context.Context, user entity.User, ip string) (entity.TokenPair, error)
+	RegisterNewUser(ctx context.Context, user entity.User) (entity.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken entity.Token) (entity.TokenPair, error)
+	Logout(ctx context.Context, claims entity.AccessClaims, refreshToken entity.Token) error
+	LogoutAll(ctx context.Context, userID int64) error
+}
+
+type authMiddleware interface {
+	Validate(next http.Handler) http.Handler
 }
 
 type handler struct {
@@ -23,45 +31,87 @@ type handler struct {
 }
 
 type TokenDto struct {
-	Token string `json:"access_token"`
+	Token        string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
-func NewAuthHandler(service authService) func(chi.Router) {
+func NewAuthHandler(middleware authMiddleware, service authService) func(chi.Router) {
 	h := &handler{service: service}
 	return func(r chi.Router) {
 		r.Post("/login", h.LoginHandler)
 		r.Post("/register", h.RegisterHandler)
+		r.Post("/token/refresh", h.RefreshHandler)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Validate)
+			r.Post("/logout", h.LogoutHandler)
+			r.Post("/logout/all", h.LogoutAllHandler)
+		})
 	}
 }
 
-func (h *handler) readUser(r *http.Request) (entity.User, error) {
-	var user entity.User
+type loginRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		return user, err
-	}
+func (req *loginRequest) user() entity.User {
+	return entity.User{Login: req.Login, Password: req.Password}
+}
+
+func (req *loginRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Required("login", req.Login)
+	errs.Required("password", req.Password)
+	return errs
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (req *refreshRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Required("refresh_token", req.RefreshToken)
+	return errs
+}
+
+// logoutRequest carries the refresh token to revoke along with the access
+// token. It is optional, unlike in refreshRequest.
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+func (req *logoutRequest) Validate() validation.Errors {
+	return nil
+}
 
-	if err := json.Unmarshal(body, &user); err != nil {
-		return user, err
+func (h *handler) readUser(w http.ResponseWriter, r *http.Request) (entity.User, error) {
+	var req loginRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		return entity.User{}, err
 	}
-	return user, nil
+	return req.user(), nil
 }
 
-func (h *handler) sendTokens(w http.ResponseWriter, token entity.Token) {
-	w.Header().Set("Authorization", string(token))
-	w.WriteHeader(http.StatusOK)
+// sendTokens returns the access token in the Authorization header, as
+// clients obtaining it on login expect, and the whole pair in the body.
+func (h *handler) sendTokens(w http.ResponseWriter, r *http.Request, tokens entity.TokenPair) {
+	w.Header().Set("Authorization", string(tokens.AccessToken))
+	render.JSON(w, r, TokenDto{
+		Token:        string(tokens.AccessToken),
+		RefreshToken: string(tokens.RefreshToken),
+	})
 }
 
 func (h *handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
-	user, err := h.readUser(r)
+	user, err := h.readUser(w, r)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		validation.WriteError(w, r, err)
 		return
 	}
-	tokens, err := h.service.Login(r.Context(), user)
+	tokens, err := h.service.Login(r.Context(), user, utils.ClientIP(r))
 	if err == nil {
-		h.sendTokens(w, tokens)
+		h.sendTokens(w, r, tokens)
 		return
 	}
 
@@ -69,19 +119,22 @@ func (h *handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if utils.WriteTooManyRequests(w, err) {
+		return
+	}
 	w.WriteHeader(http.StatusInternalServerError)
 }
 
 func (h *handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
-	user, err := h.readUser(r)
+	user, err := h.readUser(w, r)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		validation.WriteError(w, r, err)
 		return
 	}
 
 	tokens, err := h.service.RegisterNewUser(r.Context(), user)
 	if err == nil {
-		h.sendTokens(w, tokens)
+		h.sendTokens(w, r, tokens)
 		return
 	}
 
@@ -92,3 +145,66 @@ func (h *handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusInternalServerError)
 }
+
+func (h *handler) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	tokens, err := h.service.Refresh(r.Context(), entity.Token(req.RefreshToken))
+	if err == nil {
+		h.sendTokens(w, r, tokens)
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidToken) ||
+		errors.Is(err, common.ErrTokenHasExpired) ||
+		errors.Is(err, common.ErrTokenReused) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+// LogoutHandler revokes the access token of the request. The refresh token
+// in the body is optional: clients which stored it should send it to revoke
+// it as well. Without it, in an empty body or an empty object, only the
+// access token is revoked.
+func (h *handler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	claims, err := utils.GetClaims(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req logoutRequest
+	if r.ContentLength != 0 {
+		if err := validation.DecodeJSON(w, r, &req); err != nil {
+			validation.WriteError(w, r, err)
+			return
+		}
+	}
+
+	if err := h.service.Logout(r.Context(), claims, entity.Token(req.RefreshToken)); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) LogoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.LogoutAll(r.Context(), userID); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,9 +2,11 @@ package auth
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/go-chi/chi/v5"
 	"github.com/golang/mock/gomock"
@@ -13,6 +15,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewAuthHandler(t *testing.T) {
@@ -20,7 +23,12 @@ func TestNewAuthHandler(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := NewMockauthService(ctrl)
-	handlerFunc := NewAuthHandler(mockService)
+	mockMiddleware := NewMockauthMiddleware(ctrl)
+	mockMiddleware.EXPECT().
+		Validate(gomock.Any()).
+		DoAndReturn(func(next http.Handler) http.Handler { return next }).
+		AnyTimes()
+	handlerFunc := NewAuthHandler(mockMiddleware, mockService)
 
 	t.Run("handler registration", func(t *testing.T) {
 		router := chi.NewRouter()
@@ -29,7 +37,7 @@ func TestNewAuthHandler(t *testing.T) {
 		// Проверяем, что маршруты зарегистрированы
 		routes := router.Routes()
 		assert.NotEmpty(t, routes)
-		var loginFound, registerFound bool
+		var loginFound, registerFound, refreshFound, logoutFound, logoutAllFound bool
 		for _, route := range routes {
 			if route.Pattern == "/login" {
 				loginFound = true
@@ -37,50 +45,87 @@ func TestNewAuthHandler(t *testing.T) {
 			if route.Pattern == "/register" {
 				registerFound = true
 			}
+			if route.Pattern == "/token/refresh" {
+				refreshFound = true
+			}
+			if route.Pattern == "/logout" {
+				logoutFound = true
+			}
+			if route.Pattern == "/logout/all" {
+				logoutAllFound = true
+			}
 		}
 		assert.True(t, loginFound, "POST /login route should be registered")
 		assert.True(t, registerFound, "POST /register route should be registered")
+		assert.True(t, refreshFound, "POST /token/refresh route should be registered")
+		assert.True(t, logoutFound, "POST /logout route should be registered")
+		assert.True(t, logoutAllFound, "POST /logout/all route should be registered")
 	})
 }
 
 func TestHandler_readUser(t *testing.T) {
 	h := &handler{}
 
-	t.Run("valid user JSON", func(t *testing.T) {
-		user := entity.User{Login: "testuser", Password: "password123"}
-		body, _ := json.Marshal(user)
-		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
-
-		result, err := h.readUser(req)
-		assert.NoError(t, err)
-		assert.Equal(t, user, result)
-	})
-
-	t.Run("invalid JSON", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("invalid json"))
-		result, err := h.readUser(req)
-		assert.Error(t, err)
-		assert.Equal(t, entity.User{}, result)
-	})
-
-	t.Run("empty body", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodPost, "/", nil)
-		result, err := h.readUser(req)
-		assert.Error(t, err)
-		assert.Equal(t, entity.User{}, result)
-	})
+	tests := []struct {
+		name         string
+		body         string
+		expectedUser entity.User
+		expectedErr  error
+	}{
+		{
+			name:         "valid user JSON",
+			body:         `{"login": "testuser", "password": "password123"}`,
+			expectedUser: entity.User{Login: "testuser", Password: "password123"},
+		},
+		{
+			name:        "invalid JSON",
+			body:        "invalid json",
+			expectedErr: validation.ErrMalformedRequest,
+		},
+		{
+			name:        "empty body",
+			body:        "",
+			expectedErr: validation.ErrMalformedRequest,
+		},
+		{
+			// Баланс не может быть задан при регистрации
+			name:        "unknown field",
+			body:        `{"login": "testuser", "password": "password123", "balance": 100}`,
+			expectedErr: validation.ErrMalformedRequest,
+		},
+		{
+			name:        "wrong field type",
+			body:        `{"login": 42, "password": "password123"}`,
+			expectedErr: validation.ErrMalformedRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			result, err := h.readUser(httptest.NewRecorder(), req)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedUser, result)
+		})
+	}
 }
 
 func TestHandler_sendTokens(t *testing.T) {
 	h := &handler{}
-	token := entity.Token("jwt-token")
+	token := entity.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"}
 
 	rr := httptest.NewRecorder()
-	h.sendTokens(rr, token)
+	h.sendTokens(rr, httptest.NewRequest(http.MethodPost, "/login", nil), token)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
-	assert.Equal(t, string(token), rr.Header().Get("Authorization"))
-	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, "jwt-token", rr.Header().Get("Authorization"))
+	assert.JSONEq(t, `{"access_token": "jwt-token", "refresh_token": "refresh-token"}`, rr.Body.String())
+}
+
+// withClientIP sets the client address as the ClientIPMiddleware does.
+func withClientIP(req *http.Request, ip string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), middlewares.ClientIPKey("ClientIP"), ip))
 }
 
 func TestHandler_LoginHandler(t *testing.T) {
@@ -91,12 +136,27 @@ func TestHandler_LoginHandler(t *testing.T) {
 	h := &handler{service: mockService}
 
 	user := entity.User{Login: "testuser", Password: "password123"}
-	token := entity.Token("jwt-token")
-	body, _ := json.Marshal(user)
+	token := entity.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"}
+	body := []byte(`{"login": "testuser", "password": "password123"}`)
 
 	t.Run("successful login", func(t *testing.T) {
 		mockService.EXPECT().
-			Login(gomock.Any(), user).
+			Login(gomock.Any(), user, "192.0.2.1").
+			Return(token, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+		rr := httptest.NewRecorder()
+
+		h.LoginHandler(rr, withClientIP(req, "192.0.2.1"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, string(token.AccessToken), rr.Header().Get("Authorization"))
+	})
+
+	t.Run("unknown client ip", func(t *testing.T) {
+		// Без доверенных прокси адрес клиента неизвестен
+		mockService.EXPECT().
+			Login(gomock.Any(), user, "").
 			Return(token, nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
@@ -105,31 +165,30 @@ func TestHandler_LoginHandler(t *testing.T) {
 		h.LoginHandler(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code)
-		assert.Equal(t, string(token), rr.Header().Get("Authorization"))
 	})
 
 	t.Run("invalid credentials", func(t *testing.T) {
 		mockService.EXPECT().
-			Login(gomock.Any(), user).
-			Return(entity.Token(""), common.ErrInvalidCredentials)
+			Login(gomock.Any(), user, "192.0.2.1").
+			Return(entity.TokenPair{}, common.ErrInvalidCredentials)
 
 		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
 		rr := httptest.NewRecorder()
 
-		h.LoginHandler(rr, req)
+		h.LoginHandler(rr, withClientIP(req, "192.0.2.1"))
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
 
 	t.Run("internal server error", func(t *testing.T) {
 		mockService.EXPECT().
-			Login(gomock.Any(), user).
-			Return(entity.Token(""), errors.New("server error"))
+			Login(gomock.Any(), user, "192.0.2.1").
+			Return(entity.TokenPair{}, errors.New("server error"))
 
 		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
 		rr := httptest.NewRecorder()
 
-		h.LoginHandler(rr, req)
+		h.LoginHandler(rr, withClientIP(req, "192.0.2.1"))
 
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	})
@@ -138,10 +197,37 @@ func TestHandler_LoginHandler(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("invalid json"))
 		rr := httptest.NewRecorder()
 
-		h.LoginHandler(rr, req)
+		h.LoginHandler(rr, withClientIP(req, "192.0.2.1"))
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
+
+	t.Run("empty credentials", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"login": "", "password": ""}`))
+		rr := httptest.NewRecorder()
+
+		h.LoginHandler(rr, withClientIP(req, "192.0.2.1"))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.JSONEq(t, `{"errors": [
+			{"field": "login", "message": "is required"},
+			{"field": "password", "message": "is required"}
+		]}`, rr.Body.String())
+	})
+
+	t.Run("login locked", func(t *testing.T) {
+		mockService.EXPECT().
+			Login(gomock.Any(), user, "192.0.2.1").
+			Return(entity.TokenPair{}, &common.TooManyRequestsError{RetryAfter: 1500 * time.Millisecond})
+
+		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+		rr := httptest.NewRecorder()
+
+		h.LoginHandler(rr, withClientIP(req, "192.0.2.1"))
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+	})
 }
 
 func TestHandler_RegisterHandler(t *testing.T) {
@@ -152,8 +238,8 @@ func TestHandler_RegisterHandler(t *testing.T) {
 	h := &handler{service: mockService}
 
 	user := entity.User{Login: "testuser", Password: "password123"}
-	token := entity.Token("jwt-token")
-	body, _ := json.Marshal(user)
+	token := entity.TokenPair{AccessToken: "jwt-token", RefreshToken: "refresh-token"}
+	body := []byte(`{"login": "testuser", "password": "password123"}`)
 
 	t.Run("successful registration", func(t *testing.T) {
 		mockService.EXPECT().
@@ -166,13 +252,13 @@ func TestHandler_RegisterHandler(t *testing.T) {
 		h.RegisterHandler(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code)
-		assert.Equal(t, string(token), rr.Header().Get("Authorization"))
+		assert.Equal(t, string(token.AccessToken), rr.Header().Get("Authorization"))
 	})
 
 	t.Run("user already exists", func(t *testing.T) {
 		mockService.EXPECT().
 			RegisterNewUser(gomock.Any(), user).
-			Return(entity.Token(""), common.ErrUserAlreadyExist)
+			Return(entity.TokenPair{}, common.ErrUserAlreadyExist)
 
 		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(body))
 		rr := httptest.NewRecorder()
@@ -185,7 +271,7 @@ func TestHandler_RegisterHandler(t *testing.T) {
 	t.Run("internal server error", func(t *testing.T) {
 		mockService.EXPECT().
 			RegisterNewUser(gomock.Any(), user).
-			Return(entity.Token(""), errors.New("server error"))
+			Return(entity.TokenPair{}, errors.New("server error"))
 
 		req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(body))
 		rr := httptest.NewRecorder()
@@ -203,4 +289,228 @@ func TestHandler_RegisterHandler(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"login": "testuser"}`))
+		rr := httptest.NewRecorder()
+
+		h.RegisterHandler(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	})
+
+	t.Run("short credentials", func(t *testing.T) {
+		// Длина логина и пароля не ограничивается, как и раньше
+		shortUser := entity.User{Login: "ab", Password: "123"}
+		mockService.EXPECT().
+			RegisterNewUser(gomock.Any(), shortUser).
+			Return(token, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"login": "ab", "password": "123"}`))
+		rr := httptest.NewRecorder()
+
+		h.RegisterHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+}
+
+func TestHandler_RefreshHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockauthService(ctrl)
+	h := &handler{service: mockService}
+
+	refreshToken := entity.Token("refresh-token")
+	token := entity.TokenPair{AccessToken: "jwt-token", RefreshToken: "next-refresh-token"}
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    func()
+		expectedCode int
+	}{
+		{
+			name: "successful refresh",
+			body: `{"refresh_token": "refresh-token"}`,
+			setupMock: func() {
+				mockService.EXPECT().Refresh(gomock.Any(), refreshToken).Return(token, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "unknown token",
+			body: `{"refresh_token": "refresh-token"}`,
+			setupMock: func() {
+				mockService.EXPECT().Refresh(gomock.Any(), refreshToken).Return(entity.TokenPair{}, common.ErrInvalidToken)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "expired token",
+			body: `{"refresh_token": "refresh-token"}`,
+			setupMock: func() {
+				mockService.EXPECT().Refresh(gomock.Any(), refreshToken).Return(entity.TokenPair{}, common.ErrTokenHasExpired)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "reused token",
+			body: `{"refresh_token": "refresh-token"}`,
+			setupMock: func() {
+				mockService.EXPECT().Refresh(gomock.Any(), refreshToken).Return(entity.TokenPair{}, common.ErrTokenReused)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "internal server error",
+			body: `{"refresh_token": "refresh-token"}`,
+			setupMock: func() {
+				mockService.EXPECT().Refresh(gomock.Any(), refreshToken).Return(entity.TokenPair{}, errors.New("server error"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "missing token",
+			body:         `{}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "invalid request body",
+			body:         "invalid json",
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h.RefreshHandler(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, string(token.AccessToken), rr.Header().Get("Authorization"))
+				assert.JSONEq(t, `{"access_token": "jwt-token", "refresh_token": "next-refresh-token"}`, rr.Body.String())
+			}
+		})
+	}
+}
+
+func withClaims(req *http.Request, claims entity.AccessClaims) *http.Request {
+	ctx := context.WithValue(req.Context(), middlewares.UserIDKey("UserID"), claims.UserID)
+	ctx = context.WithValue(ctx, middlewares.ClaimsKey("Claims"), claims)
+	return req.WithContext(ctx)
+}
+
+func TestHandler_LogoutHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockauthService(ctrl)
+	h := &handler{service: mockService}
+	claims := entity.AccessClaims{ID: "jti", UserID: 1}
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    func()
+		expectedCode int
+	}{
+		{
+			name: "without body",
+			setupMock: func() {
+				mockService.EXPECT().Logout(gomock.Any(), claims, entity.Token("")).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "empty object",
+			body: `{}`,
+			setupMock: func() {
+				mockService.EXPECT().Logout(gomock.Any(), claims, entity.Token("")).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "with refresh token",
+			body: `{"refresh_token": "refresh-token"}`,
+			setupMock: func() {
+				mockService.EXPECT().Logout(gomock.Any(), claims, entity.Token("refresh-token")).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "invalid request body",
+			body:         `{"token": "refresh-token"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "internal server error",
+			setupMock: func() {
+				mockService.EXPECT().Logout(gomock.Any(), claims, entity.Token("")).Return(common.ErrInternalError)
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/logout", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h.LogoutHandler(rr, withClaims(req, claims))
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+		rr := httptest.NewRecorder()
+
+		h.LogoutHandler(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestHandler_LogoutAllHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockauthService(ctrl)
+	h := &handler{service: mockService}
+	claims := entity.AccessClaims{ID: "jti", UserID: 1}
+
+	t.Run("successful logout", func(t *testing.T) {
+		mockService.EXPECT().LogoutAll(gomock.Any(), claims.UserID).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/logout/all", nil)
+		rr := httptest.NewRecorder()
+
+		h.LogoutAllHandler(rr, withClaims(req, claims))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("internal server error", func(t *testing.T) {
+		mockService.EXPECT().LogoutAll(gomock.Any(), claims.UserID).Return(common.ErrInternalError)
+
+		req := httptest.NewRequest(http.MethodPost, "/logout/all", nil)
+		rr := httptest.NewRecorder()
+
+		h.LogoutAllHandler(rr, withClaims(req, claims))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
 }
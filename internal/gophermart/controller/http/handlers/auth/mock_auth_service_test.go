@@ -6,6 +6,7 @@ package auth
 
 import (
 	context "context"
+	http "net/http"
 	reflect "reflect"
 
 	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
@@ -36,25 +37,68 @@ func (m *MockauthService) EXPECT() *MockauthServiceMockRecorder {
 }
 
 // Login mocks base method.
-func (m *MockauthService) Login(ctx context.Context, user entity.User) (entity.Token, error) {
+func (m *MockauthService) Login(ctx context.Context, user entity.User, ip string) (entity.TokenPair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, user)
-	ret0, _ := ret[0].(entity.Token)
+	ret := m.ctrl.Call(m, "Login", ctx, user, ip)
+	ret0, _ := ret[0].(entity.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockauthServiceMockRecorder) Login(ctx, user interface{}) *gomock.Call {
+func (mr *MockauthServiceMockRecorder) Login(ctx, user, ip interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockauthService)(nil).Login), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockauthService)(nil).Login), ctx, user, ip)
+}
+
+// Logout mocks base method.
+func (m *MockauthService) Logout(ctx context.Context, claims entity.AccessClaims, refreshToken entity.Token) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, claims, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockauthServiceMockRecorder) Logout(ctx, claims, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockauthService)(nil).Logout), ctx, claims, refreshToken)
+}
+
+// LogoutAll mocks base method.
+func (m *MockauthService) LogoutAll(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockauthServiceMockRecorder) LogoutAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockauthService)(nil).LogoutAll), ctx, userID)
+}
+
+// Refresh mocks base method.
+func (m *MockauthService) Refresh(ctx context.Context, refreshToken entity.Token) (entity.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(entity.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockauthServiceMockRecorder) Refresh(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockauthService)(nil).Refresh), ctx, refreshToken)
 }
 
 // RegisterNewUser mocks base method.
-func (m *MockauthService) RegisterNewUser(ctx context.Context, user entity.User) (entity.Token, error) {
+func (m *MockauthService) RegisterNewUser(ctx context.Context, user entity.User) (entity.TokenPair, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RegisterNewUser", ctx, user)
-	ret0, _ := ret[0].(entity.Token)
+	ret0, _ := ret[0].(entity.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -64,3 +108,40 @@ func (mr *MockauthServiceMockRecorder) RegisterNewUser(ctx, user interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterNewUser", reflect.TypeOf((*MockauthService)(nil).RegisterNewUser), ctx, user)
 }
+
+// MockauthMiddleware is a mock of authMiddleware interface.
+type MockauthMiddleware struct {
+	ctrl     *gomock.Controller
+	recorder *MockauthMiddlewareMockRecorder
+}
+
+// MockauthMiddlewareMockRecorder is the mock recorder for MockauthMiddleware.
+type MockauthMiddlewareMockRecorder struct {
+	mock *MockauthMiddleware
+}
+
+// NewMockauthMiddleware creates a new mock instance.
+func NewMockauthMiddleware(ctrl *gomock.Controller) *MockauthMiddleware {
+	mock := &MockauthMiddleware{ctrl: ctrl}
+	mock.recorder = &MockauthMiddlewareMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockauthMiddleware) EXPECT() *MockauthMiddlewareMockRecorder {
+	return m.recorder
+}
+
+// Validate mocks base method.
+func (m *MockauthMiddleware) Validate(next http.Handler) http.Handler {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", next)
+	ret0, _ := ret[0].(http.Handler)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockauthMiddlewareMockRecorder) Validate(next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockauthMiddleware)(nil).Validate), next)
+}
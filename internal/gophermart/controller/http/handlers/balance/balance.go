@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/utils"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 
 	"github.com/go-chi/chi/v5"
@@ -17,6 +20,7 @@ import (
 
 type balanceService interface {
 	Get(ctx context.Context, userID int64) (entity.Balance, error)
+	History(ctx context.Context, userID int64, filter entity.HistoryFilter) ([]entity.BalanceHistoryEntry, error)
 }
 
 type withdrawalService interface {
@@ -45,6 +49,7 @@ func NewBalanceHandler(
 		r.Route("/balance", func(r chi.Router) {
 			r.Use(middleware.Validate)
 			r.Post("/withdraw", h.Withdraw)
+			r.Get("/history", h.GetHistory)
 			r.Get("/", h.GetBalance)
 		})
 
@@ -64,13 +69,27 @@ func (h *balanceHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
 	}
 
 	balanceDTO := struct {
-		Current   float32 `json:"current"`
-		Withdrawn float32 `json:"withdrawn"`
+		Current   entity.Money `json:"current"`
+		Withdrawn entity.Money `json:"withdrawn"`
 	}{Current: balance.Balance, Withdrawn: balance.Withdrawn}
 
 	render.JSON(w, r, balanceDTO)
 }
 
+type withdrawRequest struct {
+	Order string      `json:"order"`
+	Sum   json.Number `json:"sum"`
+
+	sum entity.Money
+}
+
+func (req *withdrawRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.OrderNumber("order", req.Order)
+	req.sum, _ = errs.PositiveAmount("sum", req.Sum)
+	return errs
+}
+
 func (h *balanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	userID, err := utils.GetUserID(r.Context())
 	if err != nil {
@@ -78,19 +97,13 @@ func (h *balanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var withdrawal entity.Withdrawal
-
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	if err := json.Unmarshal(body, &withdrawal); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	var req withdrawRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
 		return
 	}
 
+	withdrawal := entity.Withdrawal{Order: req.Order, Sum: req.sum}
 	err = h.withdrawalSvc.Withdraw(r.Context(), userID, withdrawal)
 	if err == nil {
 		w.WriteHeader(http.StatusOK)
@@ -102,10 +115,92 @@ func (h *balanceHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if errors.Is(err, common.ErrInvalidOrderNumber) {
+	if errors.Is(err, common.ErrInvalidOrderNumber) || errors.Is(err, common.ErrInvalidAmount) {
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		return
 	}
 
 	w.WriteHeader(http.StatusInternalServerError)
 }
+
+type historyEntryDTO struct {
+	Type      string       `json:"type"`
+	Amount    entity.Money `json:"amount"`
+	Balance   entity.Money `json:"balance"`
+	Reference string       `json:"reference,omitempty"`
+	CreatedAt string       `json:"created_at"`
+}
+
+// parseHistoryFilter reads the period (from, to in RFC 3339) and the page
+// (limit, offset) of the history from the query string.
+func parseHistoryFilter(query url.Values) (entity.HistoryFilter, error) {
+	var (
+		filter entity.HistoryFilter
+		err    error
+	)
+
+	if v := query.Get("from"); v != "" {
+		if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, err
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return filter, err
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return filter, err
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+func (h *balanceHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := parseHistoryFilter(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.balanceSvc.History(r.Context(), userID, filter)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidHistoryFilter) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(history) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	historyDto := make([]historyEntryDTO, 0, len(history))
+	for _, entry := range history {
+		historyDto = append(historyDto, historyEntryDTO{
+			Type:      string(entry.Type),
+			Amount:    entry.Amount,
+			Balance:   entry.Balance,
+			Reference: entry.Reference,
+			CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	render.JSON(w, r, historyDto)
+}
@@ -14,6 +14,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBalanceHandler_GetBalance(t *testing.T) {
@@ -26,8 +27,8 @@ func TestBalanceHandler_GetBalance(t *testing.T) {
 
 	userID := int64(123)
 	balance := entity.Balance{
-		Balance:   100.50,
-		Withdrawn: 50.25,
+		Balance:   10050,
+		Withdrawn: 5025,
 	}
 
 	t.Run("successful balance retrieval", func(t *testing.T) {
@@ -45,13 +46,13 @@ func TestBalanceHandler_GetBalance(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rr.Code)
 
 		var response struct {
-			Current   float32 `json:"Balance"`
-			Withdrawn float32 `json:"withdrawn"`
+			Current   float64 `json:"current"`
+			Withdrawn float64 `json:"withdrawn"`
 		}
 		err := json.Unmarshal(rr.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, balance.Balance, response.Current)
-		assert.Equal(t, balance.Withdrawn, response.Withdrawn)
+		assert.Equal(t, 100.50, response.Current)
+		assert.Equal(t, 50.25, response.Withdrawn)
 	})
 
 	t.Run("unauthorized - no user ID", func(t *testing.T) {
@@ -89,10 +90,10 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 
 	userID := int64(123)
 	withdrawal := entity.Withdrawal{
-		Order: "12345",
-		Sum:   50.25,
+		Order: "2377225624",
+		Sum:   5025,
 	}
-	body, _ := json.Marshal(withdrawal)
+	body := []byte(`{"order": "2377225624", "sum": 50.25}`)
 
 	t.Run("successful withdrawal", func(t *testing.T) {
 		mockWithdrawalSvc.EXPECT().
@@ -126,7 +127,7 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 
 		h.Withdraw(rr, req)
 
-		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
 
 	t.Run("insufficient balance", func(t *testing.T) {
@@ -174,3 +175,151 @@ func TestBalanceHandler_Withdraw(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	})
 }
+
+func TestBalanceHandler_GetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBalanceSvc := NewMockbalanceService(ctrl)
+	h := &balanceHandler{balanceSvc: mockBalanceSvc}
+
+	userID := int64(123)
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	history := []entity.BalanceHistoryEntry{
+		{Type: entity.LedgerWithdrawal, Amount: -75110, Balance: 24890, Reference: "2377225624", CreatedAt: createdAt},
+		{Type: entity.LedgerAccrual, Amount: 100000, Balance: 100000, Reference: "12345678903", CreatedAt: createdAt.Add(-time.Hour)},
+	}
+
+	newRequest := func(query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/balance/history"+query, nil)
+		ctx := context.WithValue(req.Context(), middlewares.UserIDKey("UserID"), userID)
+		return req.WithContext(ctx)
+	}
+
+	t.Run("successful history retrieval", func(t *testing.T) {
+		mockBalanceSvc.EXPECT().
+			History(gomock.Any(), userID, entity.HistoryFilter{
+				From:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:     time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+				Limit:  10,
+				Offset: 10,
+			}).
+			Return(history, nil)
+
+		rr := httptest.NewRecorder()
+		h.GetHistory(rr, newRequest("?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z&limit=10&offset=10"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[
+			{"type":"WITHDRAWAL","amount":-751.1,"balance":248.9,"reference":"2377225624","created_at":"2025-01-02T03:04:05Z"},
+			{"type":"ACCRUAL","amount":1000,"balance":1000,"reference":"12345678903","created_at":"2025-01-02T02:04:05Z"}
+		]`, rr.Body.String())
+	})
+
+	t.Run("no history", func(t *testing.T) {
+		mockBalanceSvc.EXPECT().
+			History(gomock.Any(), userID, entity.HistoryFilter{}).
+			Return(nil, nil)
+
+		rr := httptest.NewRecorder()
+		h.GetHistory(rr, newRequest(""))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("unauthorized - no user ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/balance/history", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetHistory(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("malformed query", func(t *testing.T) {
+		for _, query := range []string{"?from=yesterday", "?to=2025-01-01", "?limit=ten", "?offset=1.5"} {
+			rr := httptest.NewRecorder()
+			h.GetHistory(rr, newRequest(query))
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		mockBalanceSvc.EXPECT().
+			History(gomock.Any(), userID, entity.HistoryFilter{Limit: -1}).
+			Return(nil, common.ErrInvalidHistoryFilter)
+
+		rr := httptest.NewRecorder()
+		h.GetHistory(rr, newRequest("?limit=-1"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("internal server error", func(t *testing.T) {
+		mockBalanceSvc.EXPECT().
+			History(gomock.Any(), userID, entity.HistoryFilter{}).
+			Return(nil, errors.New("database error"))
+
+		rr := httptest.NewRecorder()
+		h.GetHistory(rr, newRequest(""))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
+
+func TestBalanceHandler_Withdraw_validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Сервис не должен вызываться для невалидных запросов
+	mockWithdrawalSvc := NewMockwithdrawalService(ctrl)
+	h := &balanceHandler{withdrawalSvc: mockWithdrawalSvc}
+	userID := int64(123)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedCode   int
+		expectedFields []string
+	}{
+		{"malformed JSON", `{"order": "2377225624", "sum": }`, http.StatusBadRequest, []string{""}},
+		{"empty body", ``, http.StatusBadRequest, []string{""}},
+		{"unknown field", `{"order": "2377225624", "sum": 1, "user_id": 1}`, http.StatusBadRequest, []string{""}},
+		{"sum as object", `{"order": "2377225624", "sum": {}}`, http.StatusBadRequest, []string{""}},
+		{"two objects", `{"order": "2377225624", "sum": 1}{}`, http.StatusBadRequest, []string{""}},
+		{"missing fields", `{}`, http.StatusUnprocessableEntity, []string{"order", "sum"}},
+		{"negative sum", `{"order": "2377225624", "sum": -10}`, http.StatusUnprocessableEntity, []string{"sum"}},
+		{"zero sum", `{"order": "2377225624", "sum": 0}`, http.StatusUnprocessableEntity, []string{"sum"}},
+		{"three decimals", `{"order": "2377225624", "sum": 10.005}`, http.StatusUnprocessableEntity, []string{"sum"}},
+		{"order fails Luhn check", `{"order": "2377225625", "sum": 10}`, http.StatusUnprocessableEntity, []string{"order"}},
+		{"order with letters", `{"order": "23772256a4", "sum": 10}`, http.StatusUnprocessableEntity, []string{"order"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/balance/withdraw", strings.NewReader(tt.body))
+			ctx := context.WithValue(req.Context(), middlewares.UserIDKey("UserID"), userID)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			h.Withdraw(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+
+			var response struct {
+				Errors []struct {
+					Field   string `json:"field"`
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			var fields []string
+			for _, fe := range response.Errors {
+				fields = append(fields, fe.Field)
+				assert.NotEmpty(t, fe.Message)
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
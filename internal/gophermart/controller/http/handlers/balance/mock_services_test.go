@@ -51,6 +51,21 @@ func (mr *MockbalanceServiceMockRecorder) Get(ctx, userID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockbalanceService)(nil).Get), ctx, userID)
 }
 
+// History mocks base method.
+func (m *MockbalanceService) History(ctx context.Context, userID int64, filter entity.HistoryFilter) ([]entity.BalanceHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, userID, filter)
+	ret0, _ := ret[0].([]entity.BalanceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockbalanceServiceMockRecorder) History(ctx, userID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockbalanceService)(nil).History), ctx, userID, filter)
+}
+
 // MockwithdrawalService is a mock of withdrawalService interface.
 type MockwithdrawalService struct {
 	ctrl     *gomock.Controller
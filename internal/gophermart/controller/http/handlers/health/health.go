@@ -0,0 +1,50 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/services/accrual"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const (
+	statusOK       = "ok"
+	statusDegraded = "degraded"
+)
+
+type accrualBreaker interface {
+	Snapshot() accrual.BreakerSnapshot
+}
+
+type healthHandler struct {
+	accrual accrualBreaker
+}
+
+type healthResponse struct {
+	Status  string                  `json:"status"`
+	Accrual accrual.BreakerSnapshot `json:"accrual"`
+}
+
+// NewHealthHandler reports whether the service is up and the state of its
+// dependencies. Orders are still accepted while the accrual system is down,
+// so an open circuit degrades the service but does not fail the check.
+func NewHealthHandler(breaker accrualBreaker) func(chi.Router) {
+	h := &healthHandler{accrual: breaker}
+	return func(r chi.Router) {
+		r.Get("/health", h.GetHealth)
+	}
+}
+
+func (h *healthHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
+	res := healthResponse{
+		Status:  statusOK,
+		Accrual: h.accrual.Snapshot(),
+	}
+	if res.Accrual.State != accrual.BreakerClosed {
+		res.Status = statusDegraded
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	render.JSON(w, r, res)
+}
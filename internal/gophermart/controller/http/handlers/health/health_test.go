@@ -0,0 +1,61 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/services/accrual"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubBreaker struct {
+	snapshot accrual.BreakerSnapshot
+}
+
+func (s stubBreaker) Snapshot() accrual.BreakerSnapshot {
+	return s.snapshot
+}
+
+func TestHealthHandler(t *testing.T) {
+	probeAt := time.Date(2025, 1, 1, 0, 0, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		snapshot accrual.BreakerSnapshot
+		wantBody string
+	}{
+		{
+			name:     "closed circuit",
+			snapshot: accrual.BreakerSnapshot{State: accrual.BreakerClosed},
+			wantBody: `{"status":"ok","accrual":{"state":"closed","consecutive_failures":0}}`,
+		},
+		{
+			name:     "open circuit",
+			snapshot: accrual.BreakerSnapshot{State: accrual.BreakerOpen, Failures: 5, ProbeAt: &probeAt},
+			wantBody: `{"status":"degraded","accrual":{"state":"open","consecutive_failures":5,"probe_at":"2025-01-01T00:00:30Z"}}`,
+		},
+		{
+			name:     "half-open circuit",
+			snapshot: accrual.BreakerSnapshot{State: accrual.BreakerHalfOpen, Failures: 5},
+			wantBody: `{"status":"degraded","accrual":{"state":"half-open","consecutive_failures":5}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			NewHealthHandler(stubBreaker{tt.snapshot})(router)
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+			assert.JSONEq(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package jwks
+
+import (
+	"net/http"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/services/jwt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type keyProvider interface {
+	JWKS() jwt.JWKSet
+}
+
+type jwksHandler struct {
+	keys keyProvider
+}
+
+// NewJWKSHandler publishes the public keys access tokens are signed with, so
+// that partner services can verify the tokens themselves.
+func NewJWKSHandler(keys keyProvider) func(chi.Router) {
+	h := &jwksHandler{keys: keys}
+	return func(r chi.Router) {
+		r.Get("/.well-known/jwks.json", h.GetJWKS)
+	}
+}
+
+func (h *jwksHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	// Keys change only on restart, clients may cache them for a while.
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	render.JSON(w, r, h.keys.JWKS())
+}
@@ -0,0 +1,39 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/services/jwt"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	key, err := jwt.NewPrivateKey("current", private)
+	assert.NoError(t, err)
+	svc, err := jwt.NewJWTService(key)
+	assert.NoError(t, err)
+
+	router := chi.NewRouter()
+	NewJWKSHandler(svc)(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Type"), "application/json")
+	assert.NotEmpty(t, rr.Header().Get("Cache-Control"))
+
+	var set jwt.JWKSet
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &set))
+	assert.Equal(t, svc.JWKS(), set)
+	assert.Equal(t, "current", set.Keys[0].KeyID)
+	assert.NotEmpty(t, set.Keys[0].X)
+}
@@ -89,16 +89,16 @@ func (h *orderHandler) SaveOrderHandler(w http.ResponseWriter, r *http.Request)
 }
 
 type orderDTO struct {
-	Number     string   `json:"number"`
-	Status     string   `json:"status"`
-	Accrual    *float32 `json:"accrual,omitempty"`
-	UploadedAt string   `json:"uploaded_at"`
+	Number     string        `json:"number"`
+	Status     string        `json:"status"`
+	Accrual    *entity.Money `json:"accrual,omitempty"`
+	UploadedAt string        `json:"uploaded_at"`
 }
 
 func (*orderHandler) mapOrderToDTO(o entity.Order) orderDTO {
 	return orderDTO{
 		Number:     o.Number,
-		Status:     o.Status,
+		Status:     string(o.Status),
 		Accrual:    o.Accrual,
 		UploadedAt: o.UploadedAt.Format(time.RFC3339),
 	}
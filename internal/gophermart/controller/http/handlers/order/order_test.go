@@ -144,7 +144,7 @@ func TestOrderHandler_SaveOrderHandler(t *testing.T) {
 func TestOrderHandler_mapOrderToDTO(t *testing.T) {
 	h := &orderHandler{}
 	uploadedAt := time.Now().Truncate(time.Second)
-	accrual := float32(100.50)
+	accrual := entity.Money(10050)
 	order := entity.Order{
 		Number:     "12345",
 		Status:     entity.OrderProcessed,
@@ -156,7 +156,7 @@ func TestOrderHandler_mapOrderToDTO(t *testing.T) {
 
 	expected := orderDTO{
 		Number:     order.Number,
-		Status:     order.Status,
+		Status:     string(order.Status),
 		Accrual:    order.Accrual,
 		UploadedAt: uploadedAt.Format(time.RFC3339),
 	}
@@ -172,7 +172,7 @@ func TestOrderHandler_GetAllHandler(t *testing.T) {
 
 	userID := int64(123)
 	uploadedAt := time.Now().Truncate(time.Second)
-	accrual := float32(100.50)
+	accrual := entity.Money(10050)
 	orders := []entity.Order{
 		{
 			Number:     "12345",
@@ -201,7 +201,7 @@ func TestOrderHandler_GetAllHandler(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, response, 1)
 		assert.Equal(t, orders[0].Number, response[0].Number)
-		assert.Equal(t, orders[0].Status, response[0].Status)
+		assert.Equal(t, string(orders[0].Status), response[0].Status)
 		assert.Equal(t, orders[0].Accrual, response[0].Accrual)
 		assert.Equal(t, orders[0].UploadedAt.Format(time.RFC3339), response[0].UploadedAt)
 	})
@@ -0,0 +1,95 @@
+package partner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+)
+
+type partnerService interface {
+	RegisterOrder(ctx context.Context, key entity.APIKey, customer entity.PartnerCustomer, number string) error
+}
+
+type partnerHandler struct {
+	service partnerService
+}
+
+// NewPartnerHandler registers the server-to-server endpoints of partner
+// systems. They are authorized by API key instead of a user's token, see
+// middlewares.APIKeyMiddleware and http.Controller.ProtectHandler.
+func NewPartnerHandler(service partnerService) func(chi.Router) {
+	h := &partnerHandler{
+		service: service,
+	}
+	return func(r chi.Router) {
+		r.Route("/orders", func(r chi.Router) {
+			r.With(middlewares.RequireScope(entity.ScopeOrdersWrite)).Post("/", h.RegisterOrder)
+		})
+	}
+}
+
+type registerOrderRequest struct {
+	Number     string `json:"number"`
+	Login      string `json:"login"`
+	ExternalID string `json:"external_id"`
+}
+
+func (req *registerOrderRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Required("number", req.Number)
+	if (req.Login == "") == (req.ExternalID == "") {
+		errs.Add("", "exactly one of login and external_id is required")
+	}
+	return errs
+}
+
+// RegisterOrder registers an order on behalf of the user identified by login
+// or external id. It answers like the user's own order upload.
+func (h *partnerHandler) RegisterOrder(w http.ResponseWriter, r *http.Request) {
+	key, ok := r.Context().Value(middlewares.APIKeyKey("APIKey")).(entity.APIKey)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req registerOrderRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	customer := entity.PartnerCustomer{Login: req.Login, ExternalID: req.ExternalID}
+	err := h.service.RegisterOrder(r.Context(), key, customer, req.Number)
+	if err == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if errors.Is(err, common.ErrOrderAlreadyExist) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if errors.Is(err, common.ErrOrderRegisteredByAnother) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidOrderNumber) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if errors.Is(err, common.ErrUserNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
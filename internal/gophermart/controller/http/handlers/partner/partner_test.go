@@ -0,0 +1,134 @@
+package partner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// keyMiddleware authorizes every request with key.
+type keyMiddleware struct {
+	key entity.APIKey
+}
+
+func (m keyMiddleware) Validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middlewares.APIKeyKey("APIKey"), m.key)))
+	})
+}
+
+func TestPartnerHandler_RegisterOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := NewMockpartnerService(ctrl)
+	key := entity.APIKey{ID: 3, Scopes: []entity.APIKeyScope{entity.ScopeOrdersWrite}}
+	router := chi.NewRouter()
+	router.Use(keyMiddleware{key: key}.Validate)
+	NewPartnerHandler(mockService)(router)
+
+	tests := []struct {
+		name         string
+		body         string
+		setupMock    func()
+		expectedCode int
+	}{
+		{
+			name: "registered by login",
+			body: `{"number": "12345678903", "login": "testuser"}`,
+			setupMock: func() {
+				mockService.EXPECT().
+					RegisterOrder(gomock.Any(), key, entity.PartnerCustomer{Login: "testuser"}, "12345678903").
+					Return(nil)
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "already registered",
+			body: `{"number": "12345678903", "external_id": "pos-42"}`,
+			setupMock: func() {
+				mockService.EXPECT().
+					RegisterOrder(gomock.Any(), key, entity.PartnerCustomer{ExternalID: "pos-42"}, "12345678903").
+					Return(common.ErrOrderAlreadyExist)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "registered by another user",
+			body: `{"number": "12345678903", "login": "testuser"}`,
+			setupMock: func() {
+				mockService.EXPECT().
+					RegisterOrder(gomock.Any(), key, gomock.Any(), "12345678903").
+					Return(common.ErrOrderRegisteredByAnother)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "invalid number",
+			body: `{"number": "12345678900", "login": "testuser"}`,
+			setupMock: func() {
+				mockService.EXPECT().
+					RegisterOrder(gomock.Any(), key, gomock.Any(), "12345678900").
+					Return(common.ErrInvalidOrderNumber)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unknown user",
+			body: `{"number": "12345678903", "external_id": "pos-43"}`,
+			setupMock: func() {
+				mockService.EXPECT().
+					RegisterOrder(gomock.Any(), key, gomock.Any(), "12345678903").
+					Return(common.ErrUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "both login and external id",
+			body:         `{"number": "12345678903", "login": "testuser", "external_id": "pos-42"}`,
+			setupMock:    func() {},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:         "malformed body",
+			body:         `12345678903`,
+			setupMock:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+
+	t.Run("key without scope", func(t *testing.T) {
+		router := chi.NewRouter()
+		router.Use(keyMiddleware{key: entity.APIKey{ID: 4}}.Validate)
+		NewPartnerHandler(mockService)(router)
+
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"number": "12345678903", "login": "testuser"}`))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
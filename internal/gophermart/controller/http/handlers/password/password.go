@@ -0,0 +1,152 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/utils"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/validation"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type passwordService interface {
+	ChangePassword(ctx context.Context, userID int64, current string, password string, ip string) error
+	RequestReset(ctx context.Context, login string, ip string) error
+	ResetPassword(ctx context.Context, token entity.Token, password string) error
+}
+
+type authMiddleware interface {
+	Validate(next http.Handler) http.Handler
+}
+
+type handler struct {
+	service passwordService
+}
+
+func NewPasswordHandler(middleware authMiddleware, service passwordService) func(chi.Router) {
+	h := &handler{service: service}
+	return func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Validate)
+			r.Post("/password", h.ChangeHandler)
+		})
+	}
+}
+
+// NewPasswordResetHandler registers the unauthenticated reset endpoints. They
+// are only mounted when reset tokens can be delivered to their users.
+func NewPasswordResetHandler(service passwordService) func(chi.Router) {
+	h := &handler{service: service}
+	return func(r chi.Router) {
+		r.Post("/password/reset", h.RequestResetHandler)
+		r.Post("/password/reset/confirm", h.ResetHandler)
+	}
+}
+
+type changeRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (req *changeRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Required("current_password", req.CurrentPassword)
+	errs.NewPassword("new_password", req.NewPassword)
+	return errs
+}
+
+type resetRequest struct {
+	Login string `json:"login"`
+}
+
+func (req *resetRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Required("login", req.Login)
+	return errs
+}
+
+type confirmRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (req *confirmRequest) Validate() validation.Errors {
+	var errs validation.Errors
+	errs.Required("token", req.Token)
+	errs.NewPassword("new_password", req.NewPassword)
+	return errs
+}
+
+// ChangeHandler changes the password of the logged in user. All sessions are
+// revoked, the client has to log in with the new password.
+func (h *handler) ChangeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserID(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req changeRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	err = h.service.ChangePassword(r.Context(), userID, req.CurrentPassword, req.NewPassword, utils.ClientIP(r))
+	if err == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidCredentials) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if utils.WriteTooManyRequests(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+// RequestResetHandler accepts the request whether the login exists or not,
+// unless the login or the client sends too many of them.
+func (h *handler) RequestResetHandler(w http.ResponseWriter, r *http.Request) {
+	var req resetRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	if err := h.service.RequestReset(r.Context(), req.Login, utils.ClientIP(r)); err != nil {
+		if utils.WriteTooManyRequests(w, err) {
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *handler) ResetHandler(w http.ResponseWriter, r *http.Request) {
+	var req confirmRequest
+	if err := validation.DecodeJSON(w, r, &req); err != nil {
+		validation.WriteError(w, r, err)
+		return
+	}
+
+	err := h.service.ResetPassword(r.Context(), entity.Token(req.Token), req.NewPassword)
+	if err == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if errors.Is(err, common.ErrInvalidToken) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+}
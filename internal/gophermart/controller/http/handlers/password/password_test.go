@@ -0,0 +1,175 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPasswordHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMiddleware := NewMockauthMiddleware(ctrl)
+	mockMiddleware.EXPECT().
+		Validate(gomock.Any()).
+		DoAndReturn(func(next http.Handler) http.Handler { return next }).
+		AnyTimes()
+
+	router := chi.NewRouter()
+	NewPasswordHandler(mockMiddleware, NewMockpasswordService(ctrl))(router)
+
+	patterns := make(map[string]bool)
+	for _, route := range router.Routes() {
+		patterns[route.Pattern] = true
+	}
+	assert.True(t, patterns["/password"], "POST /password route should be registered")
+	assert.False(t, patterns["/password/reset"], "reset routes are registered separately")
+}
+
+func TestNewPasswordResetHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router := chi.NewRouter()
+	NewPasswordResetHandler(NewMockpasswordService(ctrl))(router)
+
+	patterns := make(map[string]bool)
+	for _, route := range router.Routes() {
+		patterns[route.Pattern] = true
+	}
+	assert.True(t, patterns["/password/reset"], "POST /password/reset route should be registered")
+	assert.True(t, patterns["/password/reset/confirm"], "POST /password/reset/confirm route should be registered")
+}
+
+func TestHandler_ChangeHandler(t *testing.T) {
+	userID := int64(1)
+	body := `{"current_password": "password123", "new_password": "newpassword"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		authorized     bool
+		serviceErr     error
+		callService    bool
+		expectedStatus int
+	}{
+		{"success", body, true, nil, true, http.StatusNoContent},
+		{"unauthorized", body, false, nil, false, http.StatusUnauthorized},
+		{"short new password", `{"current_password": "password123", "new_password": "short"}`, true, nil, false, http.StatusUnprocessableEntity},
+		{"invalid current password", body, true, common.ErrInvalidCredentials, true, http.StatusForbidden},
+		{"locked", body, true, &common.TooManyRequestsError{RetryAfter: time.Minute}, true, http.StatusTooManyRequests},
+		{"service error", body, true, errors.New("db error"), true, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockpasswordService(ctrl)
+			if tt.callService {
+				mockService.EXPECT().
+					ChangePassword(gomock.Any(), userID, "password123", "newpassword", "192.0.2.1").
+					Return(tt.serviceErr)
+			}
+			h := &handler{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/password", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), middlewares.ClientIPKey("ClientIP"), "192.0.2.1"))
+			if tt.authorized {
+				req = req.WithContext(context.WithValue(req.Context(), middlewares.UserIDKey("UserID"), userID))
+			}
+			rr := httptest.NewRecorder()
+
+			h.ChangeHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestHandler_RequestResetHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		callService    bool
+		expectedStatus int
+	}{
+		{"accepted", `{"login": "testuser"}`, nil, true, http.StatusAccepted},
+		{"missing login", `{}`, nil, false, http.StatusUnprocessableEntity},
+		{"locked", `{"login": "testuser"}`, &common.TooManyRequestsError{RetryAfter: time.Minute}, true, http.StatusTooManyRequests},
+		{"service error", `{"login": "testuser"}`, errors.New("db error"), true, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockpasswordService(ctrl)
+			if tt.callService {
+				mockService.EXPECT().RequestReset(gomock.Any(), "testuser", gomock.Any()).Return(tt.serviceErr)
+			}
+			h := &handler{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/password/reset", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h.RequestResetHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestHandler_ResetHandler(t *testing.T) {
+	body := `{"token": "reset-token", "new_password": "newpassword"}`
+
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		callService    bool
+		expectedStatus int
+	}{
+		{"success", body, nil, true, http.StatusNoContent},
+		{"missing token", `{"new_password": "newpassword"}`, nil, false, http.StatusUnprocessableEntity},
+		{"invalid token", body, common.ErrInvalidToken, true, http.StatusBadRequest},
+		{"service error", body, errors.New("db error"), true, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockpasswordService(ctrl)
+			if tt.callService {
+				mockService.EXPECT().
+					ResetPassword(gomock.Any(), entity.Token("reset-token"), "newpassword").
+					Return(tt.serviceErr)
+			}
+			h := &handler{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/password/reset/confirm", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h.ResetHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -40,9 +40,9 @@ func NewWithdrawalHandler(
 }
 
 type withdrawalDTO struct {
-	Order       string  `json:"order"`
-	Sum         float32 `json:"sum"`
-	ProcessedAt string  `json:"processed_at"`
+	Order       string       `json:"order"`
+	Sum         entity.Money `json:"sum"`
+	ProcessedAt string       `json:"processed_at"`
 }
 
 func (h *withdrawalHandler) GetAll(w http.ResponseWriter, r *http.Request) {
@@ -26,7 +26,7 @@ func TestWithdrawalHandler_GetAll(t *testing.T) {
 	withdrawals := []entity.Withdrawal{
 		{
 			Order:       "12345",
-			Sum:         50.25,
+			Sum:         5025,
 			ProcessedAt: processedAt,
 		},
 	}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+)
+
+// APIKeyHeader carries the API key of a partner system.
+const APIKeyHeader = "X-API-Key"
+
+type apiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, token entity.Token) (entity.APIKey, error)
+}
+
+// APIKeyMiddleware authorizes server-to-server requests of partner systems
+// by API key, see APIKeyHeader.
+type APIKeyMiddleware struct {
+	keys apiKeyAuthenticator
+}
+
+func NewAPIKeyMiddleware(keys apiKeyAuthenticator) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		keys: keys,
+	}
+}
+
+// APIKeyKey is the context key of the entity.APIKey of the request.
+type APIKeyKey string
+
+func (m *APIKeyMiddleware) Validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(APIKeyHeader)
+		if token == "" {
+			http.Error(w, common.ErrInvalidAPIKey.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		key, err := m.keys.Authenticate(r.Context(), entity.Token(token))
+		if err != nil {
+			if errors.Is(err, common.ErrInvalidAPIKey) {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		ctx := context.WithValue(r.Context(), APIKeyKey("APIKey"), key)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireScope lets through only requests authorized with an API key having
+// scope. It has to run after APIKeyMiddleware.
+func RequireScope(scope entity.APIKeyScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := r.Context().Value(APIKeyKey("APIKey")).(entity.APIKey)
+			if !ok {
+				http.Error(w, common.ErrInvalidAPIKey.Error(), http.StatusUnauthorized)
+				return
+			}
+			if !key.HasScope(scope) {
+				http.Error(w, common.ErrForbidden.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
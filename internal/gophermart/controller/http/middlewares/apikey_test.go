@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyMiddleware_Validate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keys := NewMockapiKeyAuthenticator(ctrl)
+	middleware := NewAPIKeyMiddleware(keys)
+	key := entity.APIKey{ID: 3, Name: "pos", Scopes: []entity.APIKeyScope{entity.ScopeOrdersWrite}}
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, key, r.Context().Value(APIKeyKey("APIKey")))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name         string
+		header       string
+		setupMock    func()
+		expectedCode int
+	}{
+		{
+			name:   "valid key",
+			header: "gmk_valid",
+			setupMock: func() {
+				keys.EXPECT().Authenticate(gomock.Any(), entity.Token("gmk_valid")).Return(key, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "missing key",
+			setupMock:    func() {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid or revoked key",
+			header: "gmk_revoked",
+			setupMock: func() {
+				keys.EXPECT().Authenticate(gomock.Any(), entity.Token("gmk_revoked")).Return(entity.APIKey{}, common.ErrInvalidAPIKey)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:   "internal error",
+			header: "gmk_valid",
+			setupMock: func() {
+				keys.EXPECT().Authenticate(gomock.Any(), entity.Token("gmk_valid")).Return(entity.APIKey{}, common.ErrInternalError)
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(APIKeyHeader, tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			middleware.Validate(nextHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	middleware := RequireScope(entity.ScopeOrdersWrite)
+
+	tests := []struct {
+		name         string
+		key          *entity.APIKey
+		expectedCode int
+	}{
+		{"key with scope", &entity.APIKey{ID: 1, Scopes: []entity.APIKeyScope{entity.ScopeOrdersWrite}}, http.StatusOK},
+		{"key without scope", &entity.APIKey{ID: 1}, http.StatusForbidden},
+		{"no key", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.key != nil {
+				req = req.WithContext(context.WithValue(req.Context(), APIKeyKey("APIKey"), *tt.key))
+			}
+			rr := httptest.NewRecorder()
+
+			middleware(nextHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
@@ -9,21 +9,30 @@ import (
 )
 
 type tokenValidator interface {
-	Parse(token entity.Token) (int64, error)
+	Parse(token entity.Token) (entity.AccessClaims, error)
+}
+
+type revocationChecker interface {
+	IsRevoked(claims entity.AccessClaims) bool
 }
 
 type AuhtorizationMiddleware struct {
-	validator tokenValidator
+	validator   tokenValidator
+	revocations revocationChecker
 }
 
-func NewAuhtorizationMiddleware(val tokenValidator) *AuhtorizationMiddleware {
+func NewAuhtorizationMiddleware(val tokenValidator, revocations revocationChecker) *AuhtorizationMiddleware {
 	return &AuhtorizationMiddleware{
-		validator: val,
+		validator:   val,
+		revocations: revocations,
 	}
 }
 
 type UserIDKey string
 
+// ClaimsKey is the context key of the entity.AccessClaims of the request.
+type ClaimsKey string
+
 func (m *AuhtorizationMiddleware) Validate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -32,12 +41,17 @@ func (m *AuhtorizationMiddleware) Validate(next http.Handler) http.Handler {
 			return
 		}
 
-		userID, err := m.validator.Parse(entity.Token(authHeader))
+		claims, err := m.validator.Parse(entity.Token(authHeader))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		ctx := context.WithValue(r.Context(), UserIDKey("UserID"), userID)
+		if m.revocations.IsRevoked(claims) {
+			http.Error(w, common.ErrTokenRevoked.Error(), http.StatusUnauthorized)
+			return
+		}
+		ctx := context.WithValue(r.Context(), UserIDKey("UserID"), claims.UserID)
+		ctx = context.WithValue(ctx, ClaimsKey("Claims"), claims)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
@@ -16,7 +16,8 @@ func TestAuthorizationMiddleware_Validate(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockValidator := NewMocktokenValidator(ctrl)
-	middleware := NewAuhtorizationMiddleware(mockValidator)
+	mockRevocations := NewMockrevocationChecker(ctrl)
+	middleware := NewAuhtorizationMiddleware(mockValidator, mockRevocations)
 
 	// Создаем тестовый обработчик для проверки
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -25,16 +26,24 @@ func TestAuthorizationMiddleware_Validate(t *testing.T) {
 			http.Error(w, "user ID not found in context", http.StatusInternalServerError)
 			return
 		}
+		if _, ok := r.Context().Value(ClaimsKey("Claims")).(entity.AccessClaims); !ok {
+			http.Error(w, "claims not found in context", http.StatusInternalServerError)
+			return
+		}
 		w.Write([]byte("userID: " + string(rune(userID))))
 	})
 
+	claims := entity.AccessClaims{ID: "jti", UserID: 123}
+
 	t.Run("valid token", func(t *testing.T) {
-		userID := int64(123)
 		token := "valid-token"
 
 		mockValidator.EXPECT().
 			Parse(entity.Token(token)).
-			Return(userID, nil)
+			Return(claims, nil)
+		mockRevocations.EXPECT().
+			IsRevoked(claims).
+			Return(false)
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Set("Authorization", token)
@@ -47,6 +56,27 @@ func TestAuthorizationMiddleware_Validate(t *testing.T) {
 
 	})
 
+	t.Run("revoked token", func(t *testing.T) {
+		token := "revoked-token"
+
+		mockValidator.EXPECT().
+			Parse(entity.Token(token)).
+			Return(claims, nil)
+		mockRevocations.EXPECT().
+			IsRevoked(claims).
+			Return(true)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", token)
+		rr := httptest.NewRecorder()
+
+		handler := middleware.Validate(nextHandler)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), common.ErrTokenRevoked.Error())
+	})
+
 	t.Run("missing authorization header", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		rr := httptest.NewRecorder()
@@ -64,7 +94,7 @@ func TestAuthorizationMiddleware_Validate(t *testing.T) {
 
 		mockValidator.EXPECT().
 			Parse(entity.Token(token)).
-			Return(int64(0), parseErr)
+			Return(entity.AccessClaims{}, parseErr)
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Set("Authorization", token)
@@ -83,7 +113,7 @@ func TestAuthorizationMiddleware_Validate(t *testing.T) {
 
 		mockValidator.EXPECT().
 			Parse(entity.Token(token)).
-			Return(int64(0), genericErr)
+			Return(entity.AccessClaims{}, genericErr)
 
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Set("Authorization", token)
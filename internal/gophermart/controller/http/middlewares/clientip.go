@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ForwardedForHeader lists the addresses a request has been forwarded from,
+// the client first.
+const ForwardedForHeader = "X-Forwarded-For"
+
+// ClientIPKey is the context key of the client address of the request.
+type ClientIPKey string
+
+// ClientIPMiddleware resolves the address of the client behind trusted
+// proxies, e.g. the load balancer in front of the replicas. Without trusted
+// proxies clients are expected to connect directly: the peer is the client
+// and ForwardedForHeader, set by the client itself, is ignored.
+type ClientIPMiddleware struct {
+	trusted []netip.Prefix
+}
+
+func NewClientIPMiddleware(trusted []netip.Prefix) *ClientIPMiddleware {
+	return &ClientIPMiddleware{
+		trusted: trusted,
+	}
+}
+
+// ParseTrustedProxies parses a comma separated list of proxy addresses and
+// networks in CIDR notation.
+func ParseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", item, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", item, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func (m *ClientIPMiddleware) Resolve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ip := m.clientIP(r); ip != "" {
+			r = r.WithContext(context.WithValue(r.Context(), ClientIPKey("ClientIP"), ip))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP walks the chain of proxies from the peer back to the client and
+// returns the first address which is not a trusted proxy. Addresses before
+// it are set by the client and ignored.
+func (m *ClientIPMiddleware) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return ""
+	}
+
+	var chain []string
+	for _, header := range r.Header.Values(ForwardedForHeader) {
+		chain = append(chain, strings.Split(header, ",")...)
+	}
+	for i := len(chain) - 1; i >= 0 && m.isTrusted(addr); i-- {
+		addr, err = netip.ParseAddr(strings.TrimSpace(chain[i]))
+		if err != nil {
+			return ""
+		}
+	}
+	return addr.Unmap().String()
+}
+
+func (m *ClientIPMiddleware) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range m.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies("10.0.0.0/8, 192.0.2.10,2001:db8::/32")
+	assert.NoError(t, err)
+	assert.Len(t, prefixes, 3)
+	assert.Equal(t, "192.0.2.10/32", prefixes[1].String())
+
+	prefixes, err = ParseTrustedProxies("")
+	assert.NoError(t, err)
+	assert.Empty(t, prefixes)
+
+	_, err = ParseTrustedProxies("10.0.0.0/8,proxy")
+	assert.Error(t, err)
+}
+
+func TestClientIPMiddleware_Resolve(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		trusted    bool
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		{"no trusted proxies", false, "192.0.2.1:1234", nil, "192.0.2.1"},
+		// Без доверенных прокси заголовок задаётся клиентом
+		{"no trusted proxies with header", false, "10.0.0.1:1234", []string{"198.51.100.1"}, "10.0.0.1"},
+		{"direct client", true, "192.0.2.1:1234", nil, "192.0.2.1"},
+		{"header of untrusted peer ignored", true, "192.0.2.1:1234", []string{"198.51.100.1"}, "192.0.2.1"},
+		{"behind proxy", true, "10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed entries ignored", true, "10.0.0.1:1234", []string{"203.0.113.7, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of proxies", true, "10.0.0.1:1234", []string{"198.51.100.1, 10.0.0.2"}, "198.51.100.1"},
+		{"several headers", true, "10.0.0.1:1234", []string{"203.0.113.7", "198.51.100.1"}, "198.51.100.1"},
+		{"IPv6", true, "10.0.0.1:1234", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"proxy without header", true, "10.0.0.1:1234", nil, "10.0.0.1"},
+		{"malformed header", true, "10.0.0.1:1234", []string{"unknown"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewClientIPMiddleware(nil)
+			if tt.trusted {
+				m = NewClientIPMiddleware(trusted)
+			}
+
+			var got string
+			handler := m.Resolve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = r.Context().Value(ClientIPKey("ClientIP")).(string)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add(ForwardedForHeader, value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
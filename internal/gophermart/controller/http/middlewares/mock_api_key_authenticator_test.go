@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: apikey_test.go
+
+// Package middlewares is a generated GoMock package.
+package middlewares
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockapiKeyAuthenticator is a mock of apiKeyAuthenticator interface.
+type MockapiKeyAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockapiKeyAuthenticatorMockRecorder
+}
+
+// MockapiKeyAuthenticatorMockRecorder is the mock recorder for MockapiKeyAuthenticator.
+type MockapiKeyAuthenticatorMockRecorder struct {
+	mock *MockapiKeyAuthenticator
+}
+
+// NewMockapiKeyAuthenticator creates a new mock instance.
+func NewMockapiKeyAuthenticator(ctrl *gomock.Controller) *MockapiKeyAuthenticator {
+	mock := &MockapiKeyAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockapiKeyAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockapiKeyAuthenticator) EXPECT() *MockapiKeyAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockapiKeyAuthenticator) Authenticate(ctx context.Context, token entity.Token) (entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockapiKeyAuthenticatorMockRecorder) Authenticate(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockapiKeyAuthenticator)(nil).Authenticate), ctx, token)
+}
@@ -35,10 +35,10 @@ func (m *MocktokenValidator) EXPECT() *MocktokenValidatorMockRecorder {
 }
 
 // Parse mocks base method.
-func (m *MocktokenValidator) Parse(token entity.Token) (int64, error) {
+func (m *MocktokenValidator) Parse(token entity.Token) (entity.AccessClaims, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Parse", token)
-	ret0, _ := ret[0].(int64)
+	ret0, _ := ret[0].(entity.AccessClaims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -48,3 +48,40 @@ func (mr *MocktokenValidatorMockRecorder) Parse(token interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MocktokenValidator)(nil).Parse), token)
 }
+
+// MockrevocationChecker is a mock of revocationChecker interface.
+type MockrevocationChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockrevocationCheckerMockRecorder
+}
+
+// MockrevocationCheckerMockRecorder is the mock recorder for MockrevocationChecker.
+type MockrevocationCheckerMockRecorder struct {
+	mock *MockrevocationChecker
+}
+
+// NewMockrevocationChecker creates a new mock instance.
+func NewMockrevocationChecker(ctrl *gomock.Controller) *MockrevocationChecker {
+	mock := &MockrevocationChecker{ctrl: ctrl}
+	mock.recorder = &MockrevocationCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrevocationChecker) EXPECT() *MockrevocationCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockrevocationChecker) IsRevoked(claims entity.AccessClaims) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", claims)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockrevocationCheckerMockRecorder) IsRevoked(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockrevocationChecker)(nil).IsRevoked), claims)
+}
@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+)
+
+// RequireRole lets through only requests authorized with one of roles. It
+// reads the claims put into the context by AuhtorizationMiddleware, so it has
+// to run after it.
+func RequireRole(roles ...entity.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(ClaimsKey("Claims")).(entity.AccessClaims)
+			if !ok {
+				http.Error(w, common.ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+				return
+			}
+			if !slices.Contains(roles, claims.Role) {
+				http.Error(w, common.ErrForbidden.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	middleware := RequireRole(entity.RoleSupport, entity.RoleAdmin)
+
+	tests := []struct {
+		name         string
+		claims       *entity.AccessClaims
+		expectedCode int
+		expectedBody string
+	}{
+		{"admin", &entity.AccessClaims{UserID: 1, Role: entity.RoleAdmin}, http.StatusOK, ""},
+		{"support", &entity.AccessClaims{UserID: 1, Role: entity.RoleSupport}, http.StatusOK, ""},
+		{"user", &entity.AccessClaims{UserID: 1, Role: entity.RoleUser}, http.StatusForbidden, common.ErrForbidden.Error()},
+		{"not authorized", nil, http.StatusUnauthorized, common.ErrInvalidCredentials.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), ClaimsKey("Claims"), *tt.claims))
+			}
+			rr := httptest.NewRecorder()
+
+			middleware(nextHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
+	}
+}
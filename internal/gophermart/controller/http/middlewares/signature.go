@@ -0,0 +1,95 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/pkg/signature"
+)
+
+// maxSignedBodySize limits the bodies read to verify a signature.
+const maxSignedBodySize = 1 << 20
+
+// nonceStore remembers the nonces of accepted signatures. It has to be
+// shared by all replicas, otherwise a request can be replayed on each one.
+type nonceStore interface {
+	Remember(ctx context.Context, keyID int64, nonce string) (bool, error)
+}
+
+// SignatureMiddleware verifies the HMAC signature of partner requests, see
+// package signature. It reads the key put into the context by
+// APIKeyMiddleware, so it has to run after it.
+type SignatureMiddleware struct {
+	nonces  nonceStore
+	maxSkew time.Duration
+	now     func() time.Time
+}
+
+func NewSignatureMiddleware(nonces nonceStore, maxSkew time.Duration) *SignatureMiddleware {
+	return &SignatureMiddleware{
+		nonces:  nonces,
+		maxSkew: maxSkew,
+		now:     time.Now,
+	}
+}
+
+func (m *SignatureMiddleware) Verify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := r.Context().Value(APIKeyKey("APIKey")).(entity.APIKey)
+		if !ok {
+			http.Error(w, common.ErrInvalidAPIKey.Error(), http.StatusUnauthorized)
+			return
+		}
+		if key.SigningSecret == "" {
+			http.Error(w, "request signing is not set up for the api key", http.StatusUnauthorized)
+			return
+		}
+
+		headers, err := signature.ParseHeaders(r.Header)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err := signature.CheckTimestamp(headers.Timestamp, m.now(), m.maxSkew); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		err = signature.Verify([]byte(key.SigningSecret), r.Method, r.URL.RequestURI(), body, headers)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		// Only nonces of valid signatures are remembered, so that forged
+		// requests can not fill the store.
+		fresh, err := m.nonces.Remember(r.Context(), key.ID, headers.Nonce)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !fresh {
+			http.Error(w, signature.ErrReplayed.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
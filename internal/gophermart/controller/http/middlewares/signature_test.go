@@ -0,0 +1,150 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/pkg/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryNonces stands in for the shared nonce store.
+type memoryNonces struct {
+	nonces map[string]bool
+	err    error
+}
+
+func (n *memoryNonces) Remember(_ context.Context, keyID int64, nonce string) (bool, error) {
+	if n.err != nil {
+		return false, n.err
+	}
+	key := fmt.Sprintf("%d:%s", keyID, nonce)
+	if n.nonces[key] {
+		return false, nil
+	}
+	n.nonces[key] = true
+	return true, nil
+}
+
+func TestSignatureMiddleware_Verify(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	key := entity.APIKey{ID: 3, SigningSecret: "gms_secret"}
+	body := `{"number":"12345678903","login":"testuser"}`
+
+	nonces := &memoryNonces{nonces: make(map[string]bool)}
+	middleware := NewSignatureMiddleware(nonces, 5*time.Minute)
+	middleware.now = func() time.Time { return now }
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Тело запроса доступно обработчику после проверки подписи
+		got, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, string(got))
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	newRequest := func(key entity.APIKey, nonce string, signedAt time.Time) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/partner/orders", strings.NewReader(body))
+		err := signature.SignRequest(req, []byte(key.SigningSecret), nonce, signedAt)
+		assert.NoError(t, err)
+		return req.WithContext(context.WithValue(req.Context(), APIKeyKey("APIKey"), key))
+	}
+
+	tests := []struct {
+		name         string
+		request      func() *http.Request
+		expectedCode int
+	}{
+		{
+			name: "valid signature",
+			request: func() *http.Request {
+				return newRequest(key, "nonce-000000000001", now)
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "replayed nonce",
+			request: func() *http.Request {
+				return newRequest(key, "nonce-000000000001", now)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "same nonce of another key",
+			request: func() *http.Request {
+				return newRequest(entity.APIKey{ID: 4, SigningSecret: "gms_other"}, "nonce-000000000001", now)
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "stale timestamp",
+			request: func() *http.Request {
+				return newRequest(key, "nonce-000000000002", now.Add(-6*time.Minute))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "tampered body",
+			request: func() *http.Request {
+				req := newRequest(key, "nonce-000000000003", now)
+				req.Body = io.NopCloser(strings.NewReader(strings.Replace(body, "testuser", "attacker", 1)))
+				return req
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong secret",
+			request: func() *http.Request {
+				req := newRequest(entity.APIKey{ID: 3, SigningSecret: "gms_guess"}, "nonce-000000000004", now)
+				return req.WithContext(context.WithValue(req.Context(), APIKeyKey("APIKey"), key))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "not signed",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/api/partner/orders", strings.NewReader(body))
+				return req.WithContext(context.WithValue(req.Context(), APIKeyKey("APIKey"), key))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "key without signing secret",
+			request: func() *http.Request {
+				req := newRequest(key, "nonce-000000000005", now)
+				return req.WithContext(context.WithValue(req.Context(), APIKeyKey("APIKey"), entity.APIKey{ID: 5}))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	// Случаи выполняются по порядку: повтор нонса проверяется после первого запроса
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			middleware.Verify(nextHandler).ServeHTTP(rr, tt.request())
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+
+	t.Run("nonce store error", func(t *testing.T) {
+		nonces.err = errors.New("db error")
+		defer func() { nonces.err = nil }()
+
+		rr := httptest.NewRecorder()
+
+		middleware.Verify(nextHandler).ServeHTTP(rr, newRequest(key, "nonce-000000000006", now))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+)
+
+// ClientIP returns the client address resolved by the ClientIPMiddleware or
+// an empty string if it is unknown.
+func ClientIP(r *http.Request) string {
+	ip, _ := r.Context().Value(middlewares.ClientIPKey("ClientIP")).(string)
+	return ip
+}
+
+// WriteTooManyRequests answers with 429 Too Many Requests if err is a
+// *common.TooManyRequestsError and reports whether it did.
+func WriteTooManyRequests(w http.ResponseWriter, err error) bool {
+	var tooManyRequests *common.TooManyRequestsError
+	if !errors.As(err, &tooManyRequests) {
+		return false
+	}
+	retryAfter := int(math.Ceil(tooManyRequests.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	http.Error(w, common.ErrTooManyRequests.Error(), http.StatusTooManyRequests)
+	return true
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	t.Run("resolved", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middlewares.ClientIPKey("ClientIP"), "198.51.100.1"))
+		assert.Equal(t, "198.51.100.1", ClientIP(req))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		// Адрес соединения может принадлежать балансировщику
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		assert.Empty(t, ClientIP(req))
+	})
+}
+
+func TestWriteTooManyRequests(t *testing.T) {
+	rr := httptest.NewRecorder()
+	assert.True(t, WriteTooManyRequests(rr, &common.TooManyRequestsError{RetryAfter: 1500 * time.Millisecond}))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+
+	assert.False(t, WriteTooManyRequests(httptest.NewRecorder(), common.ErrInvalidCredentials))
+}
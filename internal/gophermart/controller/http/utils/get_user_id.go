@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 )
 
 func GetUserID(ctx context.Context) (int64, error) {
@@ -14,3 +15,13 @@ func GetUserID(ctx context.Context) (int64, error) {
 	}
 	return userID, nil
 }
+
+// GetClaims returns the claims of the access token the request is
+// authorized with.
+func GetClaims(ctx context.Context) (entity.AccessClaims, error) {
+	claims, ok := ctx.Value(middlewares.ClaimsKey("Claims")).(entity.AccessClaims)
+	if !ok {
+		return entity.AccessClaims{}, errors.New("unknown token claims")
+	}
+	return claims, nil
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"github.com/MxTrap/gophermart/internal/gophermart/controller/http/middlewares"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -43,3 +44,20 @@ func TestGetUserID(t *testing.T) {
 		assert.Equal(t, "unknown user id", err.Error())
 	})
 }
+
+func TestGetClaims(t *testing.T) {
+	claims := entity.AccessClaims{ID: "jti", UserID: 123}
+
+	t.Run("successful claims extraction", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), middlewares.ClaimsKey("Claims"), claims)
+		result, err := GetClaims(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, claims, result)
+	})
+
+	t.Run("missing claims in context", func(t *testing.T) {
+		result, err := GetClaims(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, entity.AccessClaims{}, result)
+	})
+}
@@ -0,0 +1,180 @@
+// Package validation decodes and validates JSON request bodies. A body that
+// cannot be decoded is answered with 400 Bad Request, a decoded body that
+// breaks the endpoint's rules with 422 Unprocessable Entity. Both carry the
+// list of problems in the same JSON shape:
+//
+//	{"errors": [{"field": "sum", "message": "must be positive"}]}
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/utils"
+	"github.com/go-chi/render"
+)
+
+const maxBodySize = 1 << 20
+
+var ErrMalformedRequest = errors.New("malformed request")
+
+// Validator is implemented by request DTOs. Validate checks the decoded
+// request and returns the problems found, if any.
+type Validator interface {
+	Validate() Errors
+}
+
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors is a list of rules broken by a request.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		messages = append(messages, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *Errors) Add(field, message string) {
+	*e = append(*e, FieldError{Field: field, Message: message})
+}
+
+// DecodeJSON decodes the request body into dst and validates it. Unknown
+// fields, trailing data and values of a wrong type make the request
+// malformed.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst Validator) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return malformed(err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: body must contain a single JSON object", ErrMalformedRequest)
+	}
+
+	if errs := dst.Validate(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func malformed(err error) error {
+	var (
+		syntaxErr    *json.SyntaxError
+		typeErr      *json.UnmarshalTypeError
+		maxBytesErr  *http.MaxBytesError
+		unknownField = "json: unknown field "
+	)
+	switch {
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("%w: body is empty", ErrMalformedRequest)
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: body is not valid JSON", ErrMalformedRequest)
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("%w: field %q has a wrong type", ErrMalformedRequest, typeErr.Field)
+	case errors.As(err, &maxBytesErr):
+		return fmt.Errorf("%w: body is larger than %d bytes", ErrMalformedRequest, maxBytesErr.Limit)
+	case strings.HasPrefix(err.Error(), unknownField):
+		return fmt.Errorf("%w: unknown field %s", ErrMalformedRequest, strings.TrimPrefix(err.Error(), unknownField))
+	default:
+		return fmt.Errorf("%w: %s", ErrMalformedRequest, err)
+	}
+}
+
+type errorsDTO struct {
+	Errors Errors `json:"errors"`
+}
+
+// WriteError answers a request rejected by DecodeJSON.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var errs Errors
+	if errors.As(err, &errs) {
+		render.Status(r, http.StatusUnprocessableEntity)
+		render.JSON(w, r, errorsDTO{Errors: errs})
+		return
+	}
+	render.Status(r, http.StatusBadRequest)
+	render.JSON(w, r, errorsDTO{Errors: Errors{{Message: err.Error()}}})
+}
+
+// Required checks that a string field is present and not blank.
+func (e *Errors) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		e.Add(field, "is required")
+		return false
+	}
+	return true
+}
+
+// Length checks the length of a string field in characters.
+func (e *Errors) Length(field, value string, min, max int) bool {
+	if n := utf8.RuneCountInString(value); n < min || n > max {
+		e.Add(field, fmt.Sprintf("must be from %d to %d characters long", min, max))
+		return false
+	}
+	return true
+}
+
+// NewPassword checks a password being set. The 72 bytes limit of bcrypt is
+// kept for Argon2id, so that the rules do not depend on the hash in use.
+func (e *Errors) NewPassword(field, value string) bool {
+	if !e.Required(field, value) || !e.Length(field, value, 8, 72) {
+		return false
+	}
+	if len(value) > 72 {
+		e.Add(field, "must not be longer than 72 bytes")
+		return false
+	}
+	return true
+}
+
+var digits = regexp.MustCompile(`^[0-9]+$`)
+
+// OrderNumber checks that a field is an order number passing the Luhn check.
+func (e *Errors) OrderNumber(field, value string) bool {
+	if !e.Required(field, value) {
+		return false
+	}
+	if !digits.MatchString(value) || !utils.IsOrderNumberValid(value) {
+		e.Add(field, "is not a valid order number")
+		return false
+	}
+	return true
+}
+
+// Amount parses a money field with at most two fractional digits.
+func (e *Errors) Amount(field string, value json.Number) (entity.Money, bool) {
+	if value == "" {
+		e.Add(field, "is required")
+		return 0, false
+	}
+	amount, err := entity.ParseMoney(value.String())
+	if err != nil {
+		e.Add(field, "must be an amount with at most two fractional digits")
+		return 0, false
+	}
+	return amount, true
+}
+
+// PositiveAmount parses a money field which must be greater than zero.
+func (e *Errors) PositiveAmount(field string, value json.Number) (entity.Money, bool) {
+	amount, ok := e.Amount(field, value)
+	if ok && amount <= 0 {
+		e.Add(field, "must be positive")
+		return 0, false
+	}
+	return amount, ok
+}
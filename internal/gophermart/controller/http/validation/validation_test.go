@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequest struct {
+	Name  string      `json:"name"`
+	Price json.Number `json:"price"`
+
+	price entity.Money
+}
+
+func (req *testRequest) Validate() Errors {
+	var errs Errors
+	errs.Required("name", req.Name)
+	req.price, _ = errs.PositiveAmount("price", req.Price)
+	return errs
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		expectedPrice entity.Money
+		malformed     bool
+		invalidFields []string
+	}{
+		{name: "valid", body: `{"name": "a", "price": 729.98}`, expectedPrice: 72998},
+		{name: "trailing whitespace", body: "{\"name\": \"a\", \"price\": 1}\n", expectedPrice: 100},
+		{name: "empty body", body: ``, malformed: true},
+		{name: "not JSON", body: `name=a`, malformed: true},
+		{name: "truncated", body: `{"name": "a"`, malformed: true},
+		{name: "unknown field", body: `{"name": "a", "price": 1, "admin": true}`, malformed: true},
+		{name: "wrong type", body: `{"name": 1, "price": 1}`, malformed: true},
+		{name: "trailing data", body: `{"name": "a", "price": 1} []`, malformed: true},
+		{name: "too large", body: `{"name": "` + strings.Repeat("a", maxBodySize) + `", "price": 1}`, malformed: true},
+		{name: "missing fields", body: `{}`, invalidFields: []string{"name", "price"}},
+		{name: "negative price", body: `{"name": "a", "price": -1}`, invalidFields: []string{"price"}},
+		{name: "too precise price", body: `{"name": "a", "price": 1.001}`, invalidFields: []string{"price"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			var req testRequest
+
+			err := DecodeJSON(httptest.NewRecorder(), r, &req)
+
+			switch {
+			case tt.malformed:
+				assert.ErrorIs(t, err, ErrMalformedRequest)
+			case tt.invalidFields != nil:
+				var errs Errors
+				assert.ErrorAs(t, err, &errs)
+				var fields []string
+				for _, fe := range errs {
+					fields = append(fields, fe.Field)
+				}
+				assert.Equal(t, tt.invalidFields, fields)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPrice, req.price)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	t.Run("malformed request", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{`))
+		w := httptest.NewRecorder()
+
+		WriteError(w, r, DecodeJSON(w, r, &testRequest{}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"errors": [{"message": "malformed request: body is not valid JSON"}]}`, w.Body.String())
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "a", "price": 0}`))
+		w := httptest.NewRecorder()
+
+		WriteError(w, r, DecodeJSON(w, r, &testRequest{}))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"errors": [{"field": "price", "message": "must be positive"}]}`, w.Body.String())
+	})
+}
+
+func TestErrors_OrderNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		valid  bool
+	}{
+		{"12345678903", true},
+		{"2377225624", true},
+		{"12345678904", false},
+		{"1234567890a", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			var errs Errors
+			assert.Equal(t, tt.valid, errs.OrderNumber("order", tt.number))
+			assert.Equal(t, tt.valid, len(errs) == 0)
+		})
+	}
+}
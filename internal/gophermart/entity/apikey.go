@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+)
+
+// APIKeyScope is a permission granted to an API key.
+type APIKeyScope string
+
+const (
+	// ScopeOrdersWrite allows to register orders on behalf of users.
+	ScopeOrdersWrite APIKeyScope = "orders:write"
+	// ScopeAccrualWrite allows to register reward rules and orders with the
+	// built-in accrual engine.
+	ScopeAccrualWrite APIKeyScope = "accrual:write"
+	// ScopeAccrualCallback allows the accrual system to report order
+	// statuses with callbacks.
+	ScopeAccrualCallback APIKeyScope = "accrual:callback"
+)
+
+func ParseAPIKeyScope(s string) (APIKeyScope, error) {
+	switch scope := APIKeyScope(s); scope {
+	case ScopeOrdersWrite, ScopeAccrualWrite, ScopeAccrualCallback:
+		return scope, nil
+	default:
+		return "", common.ErrUnknownScope
+	}
+}
+
+// APIKey authorizes a partner system. The key itself is shown once, when it
+// is created: only its hash is kept, and its prefix to tell keys apart.
+// SigningSecret signs the partner's requests, see package signature.
+type APIKey struct {
+	ID            int64
+	Name          string
+	Prefix        string
+	Hash          string
+	Scopes        []APIKeyScope
+	SigningSecret string
+	CreatedBy     int64
+	CreatedAt     time.Time
+	RevokedAt     *time.Time
+}
+
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// PartnerCustomer identifies a user for a partner system: either by login or
+// by the id in the partner's system, see User.ExternalID.
+type PartnerCustomer struct {
+	Login      string
+	ExternalID string
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditOrderRequeued    AuditAction = "ORDER_REQUEUED"
+	AuditOrderInvalidated AuditAction = "ORDER_INVALIDATED"
+	AuditBalanceAdjusted  AuditAction = "BALANCE_ADJUSTED"
+	AuditLoginUnlocked    AuditAction = "LOGIN_UNLOCKED"
+	AuditExternalIDSet    AuditAction = "EXTERNAL_ID_SET"
+	AuditAPIKeyCreated    AuditAction = "API_KEY_CREATED"
+	AuditAPIKeyRevoked    AuditAction = "API_KEY_REVOKED"
+)
+
+// AuditEntry records an operation of support staff. Target is what the
+// action refers to: the order number, the id of the changed user, the
+// unlocked login or the id of the API key.
+type AuditEntry struct {
+	ID        int64
+	ActorID   int64
+	Action    AuditAction
+	Target    string
+	Amount    *Money
+	Reason    string
+	CreatedAt time.Time
+}
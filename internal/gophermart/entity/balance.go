@@ -3,12 +3,12 @@ package entity
 import "time"
 
 type Balance struct {
-	Balance   float32
-	Withdrawn float32
+	Balance   Money
+	Withdrawn Money
 }
 
 type Withdrawal struct {
 	Order       string
-	Sum         float32
+	Sum         Money
 	ProcessedAt time.Time
 }
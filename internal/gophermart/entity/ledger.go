@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+type LedgerEntryType string
+
+const (
+	LedgerAccrual    LedgerEntryType = "ACCRUAL"
+	LedgerWithdrawal LedgerEntryType = "WITHDRAWAL"
+	LedgerAdjustment LedgerEntryType = "ADJUSTMENT"
+)
+
+// LedgerEntry is a single change of a user's balance. Amount is positive for
+// credits and negative for debits. An accrual refers to the credited order,
+// a withdrawal to the withdrawal record, an adjustment carries its reason.
+type LedgerEntry struct {
+	ID           int64
+	UserID       int64
+	Type         LedgerEntryType
+	Amount       Money
+	OrderNumber  string
+	WithdrawalID int64
+	Reason       string
+	CreatedAt    time.Time
+}
+
+// BalanceMismatch is a user whose stored balance differs from the sum of the
+// ledger entries.
+type BalanceMismatch struct {
+	UserID          int64
+	Balance         Money
+	Withdrawn       Money
+	LedgerBalance   Money
+	LedgerWithdrawn Money
+}
+
+// BalanceHistoryEntry is a ledger entry as shown to the user: Balance is the
+// user's balance right after the entry, Reference is the number of the
+// credited order or of the order paid by the withdrawal.
+type BalanceHistoryEntry struct {
+	Type      LedgerEntryType
+	Amount    Money
+	Balance   Money
+	Reference string
+	CreatedAt time.Time
+}
+
+// HistoryFilter selects a page of the balance history. Zero From and To do
+// not limit the period; To is exclusive.
+type HistoryFilter struct {
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
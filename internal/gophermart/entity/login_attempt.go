@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// LoginAttemptScope is what login attempts are counted by.
+type LoginAttemptScope string
+
+const (
+	LoginAttemptByLogin LoginAttemptScope = "LOGIN"
+	LoginAttemptByIP    LoginAttemptScope = "IP"
+	// Password reset requests are counted apart from login attempts.
+	PasswordResetByLogin LoginAttemptScope = "RESET_LOGIN"
+	PasswordResetByIP    LoginAttemptScope = "RESET_IP"
+)
+
+// LoginAttemptKey is a key login attempts are counted by, with its lockout
+// policy: once FreeAttempts have been made, every further attempt locks the
+// key for BaseLockout, doubled with each attempt up to MaxLockout.
+type LoginAttemptKey struct {
+	Scope        LoginAttemptScope
+	Key          string
+	FreeAttempts int
+	BaseLockout  time.Duration
+	MaxLockout   time.Duration
+}
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// moneyScale is the number of minor units (kopecks) in a point. It matches
+// the NUMERIC(20, 2) columns money is stored in.
+const moneyScale = 100
+
+// Money is an exact amount of loyalty points kept in minor units, so that
+// sums like 729.98 are stored, added and compared without rounding.
+// It is encoded in JSON as a plain number, e.g. 729.98, and in Postgres as
+// NUMERIC.
+type Money int64
+
+// ParseMoney parses a decimal amount with at most two fractional digits.
+func ParseMoney(s string) (Money, error) {
+	amount, ok := new(big.Rat).SetString(s)
+	if !ok || strings.ContainsAny(s, "/") {
+		return 0, fmt.Errorf("%w: %q", common.ErrInvalidAmount, s)
+	}
+	amount.Mul(amount, big.NewRat(moneyScale, 1))
+	if !amount.IsInt() {
+		return 0, fmt.Errorf("%w: %q has more than two fractional digits", common.ErrInvalidAmount, s)
+	}
+	if !amount.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %q is out of range", common.ErrInvalidAmount, s)
+	}
+	return Money(amount.Num().Int64()), nil
+}
+
+func (m Money) String() string {
+	sign, minor := "", uint64(m)
+	if m < 0 {
+		sign, minor = "-", uint64(-m)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/moneyScale, minor%moneyScale)
+}
+
+// MarshalJSON encodes m as a number without trailing fractional zeros, the
+// way float amounts were encoded before: 100, 100.5, 729.98.
+func (m Money) MarshalJSON() ([]byte, error) {
+	s := strings.TrimRight(strings.TrimRight(m.String(), "0"), ".")
+	return []byte(s), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("%w: %s", common.ErrInvalidAmount, data)
+	}
+	parsed, err := ParseMoney(number.String())
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer.
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(m)), Exp: -2, Valid: true}, nil
+}
+
+// ScanNumeric implements pgtype.NumericScanner.
+func (m *Money) ScanNumeric(n pgtype.Numeric) error {
+	if !n.Valid {
+		return fmt.Errorf("cannot scan NULL into %T", m)
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("%w: cannot scan NaN or infinity into %T", common.ErrInvalidAmount, m)
+	}
+
+	minor := new(big.Int).Set(n.Int)
+	shift := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(n.Exp+2))), nil)
+	if n.Exp+2 >= 0 {
+		minor.Mul(minor, shift)
+	} else if _, rest := minor.QuoRem(minor, shift, new(big.Int)); rest.Sign() != 0 {
+		return fmt.Errorf("%w: %se%d has more than two fractional digits", common.ErrInvalidAmount, n.Int, n.Exp)
+	}
+	if !minor.IsInt64() {
+		return fmt.Errorf("%w: %se%d is out of range", common.ErrInvalidAmount, n.Int, n.Exp)
+	}
+	*m = Money(minor.Int64())
+	return nil
+}
+
+func abs(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{"729.98", 72998, false},
+		{"0.1", 10, false},
+		{"100", 10000, false},
+		{"100.50", 10050, false},
+		{"-5.25", -525, false},
+		{"1e3", 100000, false},
+		{"92233720368547758.07", math.MaxInt64, false},
+		{"92233720368547758.08", 0, true},
+		{"0.001", 0, true},
+		{"1/3", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMoney(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, common.ErrInvalidAmount)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "729.98", Money(72998).String())
+	assert.Equal(t, "0.05", Money(5).String())
+	assert.Equal(t, "-1.50", Money(-150).String())
+	assert.Equal(t, "-92233720368547758.08", Money(math.MinInt64).String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	tests := []struct {
+		money Money
+		json  string
+	}{
+		{72998, "729.98"},
+		{10050, "100.5"},
+		{10000, "100"},
+		{0, "0"},
+		{-525, "-5.25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			data, err := json.Marshal(tt.money)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.json, string(data))
+
+			var got Money
+			assert.NoError(t, json.Unmarshal(data, &got))
+			assert.Equal(t, tt.money, got)
+		})
+	}
+
+	t.Run("object", func(t *testing.T) {
+		var w Withdrawal
+		err := json.Unmarshal([]byte(`{"order": "2377225624", "sum": 751.1}`), &w)
+		assert.NoError(t, err)
+		assert.Equal(t, Money(75110), w.Sum)
+	})
+
+	t.Run("too precise", func(t *testing.T) {
+		var m Money
+		err := json.Unmarshal([]byte(`0.005`), &m)
+		assert.ErrorIs(t, err, common.ErrInvalidAmount)
+	})
+
+	t.Run("not a number", func(t *testing.T) {
+		var m Money
+		err := json.Unmarshal([]byte(`true`), &m)
+		assert.ErrorIs(t, err, common.ErrInvalidAmount)
+	})
+}
+
+func TestMoney_Postgres(t *testing.T) {
+	m := pgtype.NewMap()
+
+	t.Run("encode", func(t *testing.T) {
+		buf, err := m.Encode(pgtype.NumericOID, pgtype.TextFormatCode, Money(72998), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "729.98", string(buf))
+	})
+
+	t.Run("binary round trip", func(t *testing.T) {
+		buf, err := m.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, Money(-12345678901), nil)
+		assert.NoError(t, err)
+
+		var got Money
+		assert.NoError(t, m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, buf, &got))
+		assert.Equal(t, Money(-12345678901), got)
+	})
+
+	tests := []struct {
+		value   string
+		want    Money
+		wantErr bool
+	}{
+		{"729.98", 72998, false},
+		{"100", 10000, false},
+		{"0.50", 50, false},
+		{"1.005", 0, true},
+		{"NaN", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run("scan "+tt.value, func(t *testing.T) {
+			var got Money
+			err := m.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte(tt.value), &got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("scan null into pointer", func(t *testing.T) {
+		got := new(Money)
+		err := m.Scan(pgtype.NumericOID, pgtype.TextFormatCode, nil, &got)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
@@ -1,18 +1,90 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+)
+
+type OrderStatus string
 
 const (
-	OrderNew        = "NEW"
-	OrderProcessing = "PROCESSING"
-	OrderInvalid    = "INVALID"
-	OrderProcessed  = "PROCESSED"
+	OrderNew        OrderStatus = "NEW"
+	OrderProcessing OrderStatus = "PROCESSING"
+	OrderInvalid    OrderStatus = "INVALID"
+	OrderProcessed  OrderStatus = "PROCESSED"
 )
 
+// orderTransitions lists the statuses an order can move to from each status.
+// Final statuses have no outgoing transitions.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderNew:        {OrderProcessing, OrderInvalid, OrderProcessed},
+	OrderProcessing: {OrderInvalid, OrderProcessed},
+	OrderInvalid:    {},
+	OrderProcessed:  {},
+}
+
+// accrualStatuses maps statuses of the accrual system to order statuses.
+var accrualStatuses = map[string]OrderStatus{
+	"REGISTERED": OrderNew,
+	"PROCESSING": OrderProcessing,
+	"INVALID":    OrderInvalid,
+	"PROCESSED":  OrderProcessed,
+}
+
+// ParseAccrualStatus maps a status reported by the accrual system to the
+// order status.
+func ParseAccrualStatus(status string) (OrderStatus, error) {
+	orderStatus, ok := accrualStatuses[status]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", common.ErrUnknownOrderStatus, status)
+	}
+	return orderStatus, nil
+}
+
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+func (s OrderStatus) IsFinal() bool {
+	return s.IsValid() && len(orderTransitions[s]) == 0
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, status := range orderTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Predecessors returns the statuses from which an order can move to s.
+func (s OrderStatus) Predecessors() []OrderStatus {
+	var predecessors []OrderStatus
+	for _, from := range []OrderStatus{OrderNew, OrderProcessing, OrderInvalid, OrderProcessed} {
+		if from.CanTransitionTo(s) {
+			predecessors = append(predecessors, from)
+		}
+	}
+	return predecessors
+}
+
 type Order struct {
 	UserID     int64
 	Number     string
-	Status     string
-	Accrual    *float32
+	Status     OrderStatus
+	Accrual    *Money
 	UploadedAt time.Time
+	Attempts   int
+}
+
+// DeadLetter is an order removed from the processing queue after too many
+// failed attempts to get its accrual.
+type DeadLetter struct {
+	Order
+	LastError      string
+	DeadLetteredAt time.Time
 }
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from OrderStatus
+		to   OrderStatus
+		want bool
+	}{
+		{OrderNew, OrderProcessing, true},
+		{OrderNew, OrderInvalid, true},
+		{OrderNew, OrderProcessed, true},
+		{OrderNew, OrderNew, false},
+		{OrderProcessing, OrderInvalid, true},
+		{OrderProcessing, OrderProcessed, true},
+		{OrderProcessing, OrderNew, false},
+		{OrderProcessing, OrderProcessing, false},
+		{OrderProcessed, OrderProcessing, false},
+		{OrderProcessed, OrderProcessed, false},
+		{OrderInvalid, OrderProcessed, false},
+		{OrderNew, OrderStatus("REGISTERED"), false},
+		{OrderStatus("UNKNOWN"), OrderProcessed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestOrderStatus_IsFinal(t *testing.T) {
+	tests := []struct {
+		status OrderStatus
+		want   bool
+	}{
+		{OrderNew, false},
+		{OrderProcessing, false},
+		{OrderInvalid, true},
+		{OrderProcessed, true},
+		{OrderStatus("UNKNOWN"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.status.IsFinal())
+		})
+	}
+}
+
+func TestOrderStatus_IsValid(t *testing.T) {
+	assert.True(t, OrderNew.IsValid())
+	assert.True(t, OrderProcessed.IsValid())
+	assert.False(t, OrderStatus("REGISTERED").IsValid())
+	assert.False(t, OrderStatus("").IsValid())
+}
+
+func TestOrderStatus_Predecessors(t *testing.T) {
+	assert.Equal(t, []OrderStatus{OrderNew, OrderProcessing}, OrderProcessed.Predecessors())
+	assert.Equal(t, []OrderStatus{OrderNew}, OrderProcessing.Predecessors())
+	assert.Empty(t, OrderNew.Predecessors())
+}
+
+func TestParseAccrualStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		want    OrderStatus
+		wantErr error
+	}{
+		{"REGISTERED", OrderNew, nil},
+		{"PROCESSING", OrderProcessing, nil},
+		{"INVALID", OrderInvalid, nil},
+		{"PROCESSED", OrderProcessed, nil},
+		{"NEW", "", common.ErrUnknownOrderStatus},
+		{"", "", common.ErrUnknownOrderStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			got, err := ParseAccrualStatus(tt.status)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
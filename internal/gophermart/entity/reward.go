@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+)
+
+type RewardType string
+
+const (
+	// RewardPercent rewards a percent of the price of a good.
+	RewardPercent RewardType = "%"
+	// RewardPoints rewards a fixed amount of points per good.
+	RewardPoints RewardType = "pt"
+)
+
+const (
+	// MaxPercentReward is the largest percent reward, the whole price.
+	MaxPercentReward Money = 100 * moneyScale
+	// MaxPrice is the largest price of a good accepted for calculation.
+	MaxPrice Money = 10_000_000_000 * moneyScale
+)
+
+// RewardRule rewards goods whose description contains Match. For
+// RewardPercent Reward is a percent, e.g. 7.5, kept in hundredths like money.
+type RewardRule struct {
+	Match      string     `json:"match"`
+	Reward     Money      `json:"reward"`
+	RewardType RewardType `json:"reward_type"`
+}
+
+func (r RewardRule) Validate() error {
+	if r.Match == "" || r.Reward <= 0 {
+		return fmt.Errorf("%w: match and a positive reward are required", common.ErrInvalidRewardRule)
+	}
+	if r.RewardType != RewardPercent && r.RewardType != RewardPoints {
+		return fmt.Errorf("%w: unknown reward type %q", common.ErrInvalidRewardRule, r.RewardType)
+	}
+	if r.RewardType == RewardPercent && r.Reward > MaxPercentReward {
+		return fmt.Errorf("%w: percent reward above %s", common.ErrInvalidRewardRule, MaxPercentReward)
+	}
+	return nil
+}
+
+type Good struct {
+	Description string `json:"description"`
+	Price       Money  `json:"price"`
+}
+
+func (g Good) Validate() error {
+	if g.Price < 0 || g.Price > MaxPrice {
+		return fmt.Errorf("%w: price of %q must be from 0 to %s", common.ErrInvalidAmount, g.Description, MaxPrice)
+	}
+	return nil
+}
+
+// CalculateAccrual sums the rewards for goods. A good is rewarded by the
+// first rule it matches, percent rewards are rounded down to a kopeck. The
+// sum is calculated exactly and an accrual which does not fit into Money is
+// an error.
+func CalculateAccrual(goods []Good, rules []RewardRule) (Money, error) {
+	total := new(big.Int)
+	reward := new(big.Int)
+	for _, good := range goods {
+		for _, rule := range rules {
+			if !strings.Contains(good.Description, rule.Match) {
+				continue
+			}
+			if rule.RewardType == RewardPercent {
+				reward.Mul(big.NewInt(int64(good.Price)), big.NewInt(int64(rule.Reward)))
+				reward.Quo(reward, big.NewInt(100*moneyScale))
+			} else {
+				reward.SetInt64(int64(rule.Reward))
+			}
+			total.Add(total, reward)
+			break
+		}
+	}
+	if !total.IsInt64() {
+		return 0, fmt.Errorf("%w: accrual %s is out of range", common.ErrInvalidAmount, total)
+	}
+	return Money(total.Int64()), nil
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewardRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    RewardRule
+		wantErr error
+	}{
+		{name: "percent", rule: RewardRule{Match: "Bork", Reward: 1000, RewardType: RewardPercent}},
+		{name: "points", rule: RewardRule{Match: "Bork", Reward: 1000, RewardType: RewardPoints}},
+		{name: "empty match", rule: RewardRule{Reward: 1000, RewardType: RewardPoints}, wantErr: common.ErrInvalidRewardRule},
+		{name: "zero reward", rule: RewardRule{Match: "Bork", RewardType: RewardPoints}, wantErr: common.ErrInvalidRewardRule},
+		{name: "unknown type", rule: RewardRule{Match: "Bork", Reward: 1000, RewardType: "x"}, wantErr: common.ErrInvalidRewardRule},
+		{name: "whole price", rule: RewardRule{Match: "Bork", Reward: MaxPercentReward, RewardType: RewardPercent}},
+		{name: "percent above price", rule: RewardRule{Match: "Bork", Reward: MaxPercentReward + 1, RewardType: RewardPercent}, wantErr: common.ErrInvalidRewardRule},
+		{name: "large points", rule: RewardRule{Match: "Bork", Reward: MaxPercentReward + 1, RewardType: RewardPoints}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.rule.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestCalculateAccrual(t *testing.T) {
+	rules := []RewardRule{
+		{Match: "Bork", Reward: 1000, RewardType: RewardPercent},
+		{Match: "Miele", Reward: 5000, RewardType: RewardPoints},
+		{Match: "Чайник", Reward: 100000, RewardType: RewardPoints},
+	}
+
+	tests := []struct {
+		name  string
+		goods []Good
+		want  Money
+	}{
+		{name: "no goods", want: 0},
+		{
+			name:  "percent of price",
+			goods: []Good{{Description: "Пылесос Bork", Price: 700000}},
+			want:  70000,
+		},
+		{
+			name:  "percent rounded down",
+			goods: []Good{{Description: "Пылесос Bork", Price: 999}},
+			want:  99,
+		},
+		{
+			name: "points per good",
+			goods: []Good{
+				{Description: "Стиральная машинка Miele", Price: 4500050},
+				{Description: "Сушильная машина Miele", Price: 3000000},
+			},
+			want: 10000,
+		},
+		{
+			name:  "first matching rule wins",
+			goods: []Good{{Description: "Чайник Bork", Price: 700000}},
+			want:  70000,
+		},
+		{
+			name:  "no matching rule",
+			goods: []Good{{Description: "Утюг", Price: 300000}},
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accrual, err := CalculateAccrual(tt.goods, rules)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, accrual)
+		})
+	}
+}
+
+func TestGood_Validate(t *testing.T) {
+	assert.NoError(t, Good{Description: "Утюг", Price: 0}.Validate())
+	assert.NoError(t, Good{Description: "Утюг", Price: MaxPrice}.Validate())
+	assert.ErrorIs(t, Good{Description: "Утюг", Price: -1}.Validate(), common.ErrInvalidAmount)
+	assert.ErrorIs(t, Good{Description: "Утюг", Price: MaxPrice + 1}.Validate(), common.ErrInvalidAmount)
+}
+
+func TestCalculateAccrual_overflow(t *testing.T) {
+	rules := []RewardRule{
+		{Match: "Bork", Reward: 20000, RewardType: RewardPercent},
+		{Match: "Miele", Reward: math.MaxInt64 / 2, RewardType: RewardPoints},
+	}
+
+	// Произведение цены на процент не помещается в int64, а сама
+	// начисленная сумма помещается.
+	t.Run("large price", func(t *testing.T) {
+		accrual, err := CalculateAccrual([]Good{{Description: "Bork", Price: math.MaxInt64 / 4}}, rules)
+		assert.NoError(t, err)
+		assert.Equal(t, Money(math.MaxInt64/4*2), accrual)
+	})
+
+	t.Run("percent out of range", func(t *testing.T) {
+		_, err := CalculateAccrual([]Good{{Description: "Bork", Price: math.MaxInt64}}, rules)
+		assert.ErrorIs(t, err, common.ErrInvalidAmount)
+	})
+
+	t.Run("sum out of range", func(t *testing.T) {
+		goods := []Good{{Description: "Miele"}, {Description: "Miele"}, {Description: "Miele"}}
+		_, err := CalculateAccrual(goods, rules)
+		assert.ErrorIs(t, err, common.ErrInvalidAmount)
+	})
+}
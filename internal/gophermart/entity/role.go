@@ -0,0 +1,21 @@
+package entity
+
+import "github.com/MxTrap/gophermart/internal/gophermart/common"
+
+// Role grants a user access to the API. Every user has exactly one role.
+type Role string
+
+const (
+	RoleUser    Role = "user"
+	RoleSupport Role = "support"
+	RoleAdmin   Role = "admin"
+)
+
+func ParseRole(s string) (Role, error) {
+	switch role := Role(s); role {
+	case RoleUser, RoleSupport, RoleAdmin:
+		return role, nil
+	default:
+		return "", common.ErrUnknownRole
+	}
+}
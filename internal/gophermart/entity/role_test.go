@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Role
+		wantErr error
+	}{
+		{"user", RoleUser, nil},
+		{"support", RoleSupport, nil},
+		{"admin", RoleAdmin, nil},
+		{"Admin", "", common.ErrUnknownRole},
+		{"", "", common.ErrUnknownRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			role, err := ParseRole(tt.input)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, role)
+		})
+	}
+}
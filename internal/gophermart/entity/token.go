@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+// TokenPair is issued on login and on refresh: a short-lived access token
+// and an opaque refresh token to obtain the next pair.
+type TokenPair struct {
+	AccessToken  Token
+	RefreshToken Token
+}
+
+// RefreshToken is a stored refresh token. Tokens issued by rotating one
+// another share a family, so that a replayed token revokes all of them.
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	FamilyID  string
+	Hash      string
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+// Used reports whether the token has already been exchanged or revoked.
+func (t RefreshToken) Used() bool {
+	return t.RotatedAt != nil || t.RevokedAt != nil
+}
+
+// AccessClaims are the claims of a verified access token.
+type AccessClaims struct {
+	ID        string
+	UserID    int64
+	Role      Role
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// RevokedToken is an access token revoked before it expires.
+type RevokedToken struct {
+	ID        string
+	UserID    int64
+	ExpiresAt time.Time
+}
+
+// UserRevocation revokes all access tokens of a user issued before
+// RevokedBefore. It is kept until the last of them expires.
+type UserRevocation struct {
+	UserID        int64
+	RevokedBefore time.Time
+	ExpiresAt     time.Time
+}
+
+// PasswordReset is a stored password reset token.
+type PasswordReset struct {
+	UserID    int64
+	Hash      string
+	ExpiresAt time.Time
+}
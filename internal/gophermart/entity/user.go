@@ -6,6 +6,9 @@ type User struct {
 	ID        int64
 	Login     string
 	Password  string
-	Balance   float32
-	Withdrawn float32
+	Balance   Money
+	Withdrawn Money
+	Role      Role
+	// ExternalID identifies the user in partner systems.
+	ExternalID *string
 }
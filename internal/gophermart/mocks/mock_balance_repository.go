@@ -10,7 +10,6 @@ import (
 
 	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
 	gomock "github.com/golang/mock/gomock"
-	pgx "github.com/jackc/pgx/v5"
 )
 
 // MockBalanceRepository is a mock of BalanceRepository interface.
@@ -51,30 +50,17 @@ func (mr *MockBalanceRepositoryMockRecorder) Get(ctx, userID interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBalanceRepository)(nil).Get), ctx, userID)
 }
 
-// Increase mocks base method.
-func (m *MockBalanceRepository) Increase(ctx context.Context, tx pgx.Tx, userID int64, sum float32) error {
+// History mocks base method.
+func (m *MockBalanceRepository) History(ctx context.Context, userID int64, filter entity.HistoryFilter) ([]entity.BalanceHistoryEntry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Increase", ctx, tx, userID, sum)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Increase indicates an expected call of Increase.
-func (mr *MockBalanceRepositoryMockRecorder) Increase(ctx, tx, userID, sum interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increase", reflect.TypeOf((*MockBalanceRepository)(nil).Increase), ctx, tx, userID, sum)
-}
-
-// Withdraw mocks base method.
-func (m *MockBalanceRepository) Withdraw(ctx context.Context, tx pgx.Tx, userID int64, sum float32) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Withdraw", ctx, tx, userID, sum)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "History", ctx, userID, filter)
+	ret0, _ := ret[0].([]entity.BalanceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Withdraw indicates an expected call of Withdraw.
-func (mr *MockBalanceRepositoryMockRecorder) Withdraw(ctx, tx, userID, sum interface{}) *gomock.Call {
+// History indicates an expected call of History.
+func (mr *MockBalanceRepositoryMockRecorder) History(ctx, userID, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockBalanceRepository)(nil).Withdraw), ctx, tx, userID, sum)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockBalanceRepository)(nil).History), ctx, userID, filter)
 }
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/gophermart/repository/tmp.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
+	gomock "github.com/golang/mock/gomock"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// MockLedgerRepository is a mock of LedgerRepository interface.
+type MockLedgerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerRepositoryMockRecorder
+}
+
+// MockLedgerRepositoryMockRecorder is the mock recorder for MockLedgerRepository.
+type MockLedgerRepositoryMockRecorder struct {
+	mock *MockLedgerRepository
+}
+
+// NewMockLedgerRepository creates a new mock instance.
+func NewMockLedgerRepository(ctrl *gomock.Controller) *MockLedgerRepository {
+	mock := &MockLedgerRepository{ctrl: ctrl}
+	mock.recorder = &MockLedgerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedgerRepository) EXPECT() *MockLedgerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockLedgerRepository) Append(ctx context.Context, tx pgx.Tx, entry entity.LedgerEntry) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, tx, entry)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockLedgerRepositoryMockRecorder) Append(ctx, tx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockLedgerRepository)(nil).Append), ctx, tx, entry)
+}
+
+// Mismatches mocks base method.
+func (m *MockLedgerRepository) Mismatches(ctx context.Context) ([]entity.BalanceMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mismatches", ctx)
+	ret0, _ := ret[0].([]entity.BalanceMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Mismatches indicates an expected call of Mismatches.
+func (mr *MockLedgerRepositoryMockRecorder) Mismatches(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mismatches", reflect.TypeOf((*MockLedgerRepository)(nil).Mismatches), ctx)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/gophermart/repository/tmp.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoginAttemptRepository is a mock of LoginAttemptRepository interface.
+type MockLoginAttemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptRepositoryMockRecorder
+}
+
+// MockLoginAttemptRepositoryMockRecorder is the mock recorder for MockLoginAttemptRepository.
+type MockLoginAttemptRepositoryMockRecorder struct {
+	mock *MockLoginAttemptRepository
+}
+
+// NewMockLoginAttemptRepository creates a new mock instance.
+func NewMockLoginAttemptRepository(ctrl *gomock.Controller) *MockLoginAttemptRepository {
+	mock := &MockLoginAttemptRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptRepository) EXPECT() *MockLoginAttemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteStale mocks base method.
+func (m *MockLoginAttemptRepository) DeleteStale(ctx context.Context, before, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, before, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockLoginAttemptRepositoryMockRecorder) DeleteStale(ctx, before, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockLoginAttemptRepository)(nil).DeleteStale), ctx, before, now)
+}
+
+// LockedUntil mocks base method.
+func (m *MockLoginAttemptRepository) LockedUntil(ctx context.Context, keys []entity.LoginAttemptKey) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockedUntil", ctx, keys)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockedUntil indicates an expected call of LockedUntil.
+func (mr *MockLoginAttemptRepositoryMockRecorder) LockedUntil(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockedUntil", reflect.TypeOf((*MockLoginAttemptRepository)(nil).LockedUntil), ctx, keys)
+}
+
+// Release mocks base method.
+func (m *MockLoginAttemptRepository) Release(ctx context.Context, key entity.LoginAttemptKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Release(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Release), ctx, key)
+}
+
+// Reserve mocks base method.
+func (m *MockLoginAttemptRepository) Reserve(ctx context.Context, keys []entity.LoginAttemptKey, now, windowStart time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, keys, now, windowStart)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Reserve(ctx, keys, now, windowStart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Reserve), ctx, keys, now, windowStart)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttemptRepository) Reset(ctx context.Context, scope entity.LoginAttemptScope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Reset(ctx, scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Reset), ctx, scope, key)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/gophermart/repository/tmp.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockNonceRepository is a mock of NonceRepository interface.
+type MockNonceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNonceRepositoryMockRecorder
+}
+
+// MockNonceRepositoryMockRecorder is the mock recorder for MockNonceRepository.
+type MockNonceRepositoryMockRecorder struct {
+	mock *MockNonceRepository
+}
+
+// NewMockNonceRepository creates a new mock instance.
+func NewMockNonceRepository(ctrl *gomock.Controller) *MockNonceRepository {
+	mock := &MockNonceRepository{ctrl: ctrl}
+	mock.recorder = &MockNonceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNonceRepository) EXPECT() *MockNonceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockNonceRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockNonceRepositoryMockRecorder) DeleteExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockNonceRepository)(nil).DeleteExpired), ctx, now)
+}
+
+// Remember mocks base method.
+func (m *MockNonceRepository) Remember(ctx context.Context, keyID int64, nonce string, now, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remember", ctx, keyID, nonce, now, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Remember indicates an expected call of Remember.
+func (mr *MockNonceRepositoryMockRecorder) Remember(ctx, keyID, nonce, now, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remember", reflect.TypeOf((*MockNonceRepository)(nil).Remember), ctx, keyID, nonce, now, expiresAt)
+}
@@ -81,11 +81,13 @@ func (mr *MockOrderRepositoryMockRecorder) Save(ctx, order interface{}) *gomock.
 }
 
 // Update mocks base method.
-func (m *MockOrderRepository) Update(ctx context.Context, tx pgx.Tx, order entity.Order) error {
+func (m *MockOrderRepository) Update(ctx context.Context, tx pgx.Tx, order entity.Order) (entity.OrderStatus, bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Update", ctx, tx, order)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(entity.OrderStatus)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Update indicates an expected call of Update.
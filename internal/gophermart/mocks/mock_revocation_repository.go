@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/gophermart/repository/tmp.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevocationRepository is a mock of RevocationRepository interface.
+type MockRevocationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationRepositoryMockRecorder
+}
+
+// MockRevocationRepositoryMockRecorder is the mock recorder for MockRevocationRepository.
+type MockRevocationRepositoryMockRecorder struct {
+	mock *MockRevocationRepository
+}
+
+// NewMockRevocationRepository creates a new mock instance.
+func NewMockRevocationRepository(ctrl *gomock.Controller) *MockRevocationRepository {
+	mock := &MockRevocationRepository{ctrl: ctrl}
+	mock.recorder = &MockRevocationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationRepository) EXPECT() *MockRevocationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Active mocks base method.
+func (m *MockRevocationRepository) Active(ctx context.Context) ([]entity.RevokedToken, []entity.UserRevocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Active", ctx)
+	ret0, _ := ret[0].([]entity.RevokedToken)
+	ret1, _ := ret[1].([]entity.UserRevocation)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Active indicates an expected call of Active.
+func (mr *MockRevocationRepositoryMockRecorder) Active(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Active", reflect.TypeOf((*MockRevocationRepository)(nil).Active), ctx)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockRevocationRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockRevocationRepositoryMockRecorder) DeleteExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockRevocationRepository)(nil).DeleteExpired), ctx)
+}
+
+// RevokeToken mocks base method.
+func (m *MockRevocationRepository) RevokeToken(ctx context.Context, token entity.RevokedToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockRevocationRepositoryMockRecorder) RevokeToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockRevocationRepository)(nil).RevokeToken), ctx, token)
+}
+
+// RevokeUser mocks base method.
+func (m *MockRevocationRepository) RevokeUser(ctx context.Context, revocation entity.UserRevocation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", ctx, revocation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser.
+func (mr *MockRevocationRepositoryMockRecorder) RevokeUser(ctx, revocation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*MockRevocationRepository)(nil).RevokeUser), ctx, revocation)
+}
@@ -52,11 +52,12 @@ func (mr *MockWithdrawalRepositoryMockRecorder) GetAll(ctx, userID interface{})
 }
 
 // Save mocks base method.
-func (m *MockWithdrawalRepository) Save(ctx context.Context, tx *pgx.Tx, userID int64, withdrawn entity.Withdrawal) error {
+func (m *MockWithdrawalRepository) Save(ctx context.Context, tx pgx.Tx, userID int64, withdrawn entity.Withdrawal) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Save", ctx, tx, userID, withdrawn)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Save indicates an expected call of Save.
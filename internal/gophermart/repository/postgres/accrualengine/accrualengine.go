@@ -0,0 +1,78 @@
+package accrualengine
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AccrualEngineRepository struct {
+	db *pgxpool.Pool
+}
+
+const repoName = "postgres.AccrualEngineRepo."
+
+func NewAccrualEngineRepository(db *pgxpool.Pool) *AccrualEngineRepository {
+	return &AccrualEngineRepository{
+		db: db,
+	}
+}
+
+func (r *AccrualEngineRepository) AddRule(ctx context.Context, rule entity.RewardRule) error {
+	const op = repoName + "AddRule"
+	tag, err := r.db.Exec(ctx, insertRuleStmt, rule.Match, rule.Reward, rule.RewardType)
+	if err != nil {
+		return storage.NewRepositoryError(op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.NewRepositoryError(op, common.ErrRewardRuleExists)
+	}
+	return nil
+}
+
+// ListRules returns the rules in the order they were added.
+func (r *AccrualEngineRepository) ListRules(ctx context.Context) ([]entity.RewardRule, error) {
+	const op = repoName + "ListRules"
+	rows, err := r.db.Query(ctx, listRulesStmt)
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+
+	rules, err := pgx.CollectRows(rows, pgx.RowToStructByPos[entity.RewardRule])
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	return rules, nil
+}
+
+// SaveOrder stores a registered order with its calculated accrual.
+func (r *AccrualEngineRepository) SaveOrder(ctx context.Context, number string, goods []entity.Good, accrual entity.Money) error {
+	const op = repoName + "SaveOrder"
+	tag, err := r.db.Exec(ctx, insertOrderStmt, number, goods, accrual)
+	if err != nil {
+		return storage.NewRepositoryError(op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.NewRepositoryError(op, common.ErrAccrualOrderExists)
+	}
+	return nil
+}
+
+func (r *AccrualEngineRepository) FindOrderAccrual(ctx context.Context, number string) (entity.Money, error) {
+	const op = repoName + "FindOrderAccrual"
+	var accrual entity.Money
+	err := r.db.QueryRow(ctx, findOrderAccrualStmt, number).Scan(&accrual)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, storage.NewRepositoryError(op, common.ErrNonExistentOrder)
+		}
+		return 0, storage.NewRepositoryError(op, err)
+	}
+	return accrual, nil
+}
@@ -0,0 +1,15 @@
+package accrualengine
+
+const insertRuleStmt = `
+INSERT INTO accrual_rules (match, reward, reward_type)
+VALUES ($1, $2, $3)
+ON CONFLICT (match) DO NOTHING;`
+
+const listRulesStmt = "SELECT match, reward, reward_type FROM accrual_rules ORDER BY id;"
+
+const insertOrderStmt = `
+INSERT INTO accrual_orders (number, goods, accrual)
+VALUES ($1, $2, $3)
+ON CONFLICT (number) DO NOTHING;`
+
+const findOrderAccrualStmt = "SELECT accrual FROM accrual_orders WHERE number = $1;"
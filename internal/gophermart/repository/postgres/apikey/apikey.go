@@ -0,0 +1,100 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APIKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+const repoName = "postgres.APIKeyRepo."
+
+func NewAPIKeyRepository(db *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+// Save stores key and returns it with the id and the creation time set.
+func (r *APIKeyRepository) Save(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	err := r.db.QueryRow(ctx, insertStmt, key.Name, key.Prefix, key.Hash, scopes, key.SigningSecret, key.CreatedBy).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return key, storage.NewRepositoryError(repoName+"Save", err)
+	}
+	return key, nil
+}
+
+// FindByHash returns the key with the hash, revoked or not.
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	const op = repoName + "FindByHash"
+	rows, err := r.db.Query(ctx, findByHashStmt, hash)
+	if err != nil {
+		return entity.APIKey{}, storage.NewRepositoryError(op, err)
+	}
+
+	key, err := pgx.CollectOneRow(rows, scanKey)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return key, storage.NewRepositoryError(op, common.ErrInvalidAPIKey)
+		}
+		return key, storage.NewRepositoryError(op, err)
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]entity.APIKey, error) {
+	const op = repoName + "List"
+	rows, err := r.db.Query(ctx, listStmt)
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+
+	keys, err := pgx.CollectRows(rows, scanKey)
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	return keys, nil
+}
+
+// Revoke revokes an active key.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int64) error {
+	const op = repoName + "Revoke"
+	tag, err := r.db.Exec(ctx, revokeStmt, id)
+	if err != nil {
+		return storage.NewRepositoryError(op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.NewRepositoryError(op, common.ErrAPIKeyNotFound)
+	}
+	return nil
+}
+
+func scanKey(row pgx.CollectableRow) (entity.APIKey, error) {
+	var (
+		key    entity.APIKey
+		scopes []string
+	)
+	err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.SigningSecret,
+		&key.CreatedBy, &key.CreatedAt, &key.RevokedAt,
+	)
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, entity.APIKeyScope(scope))
+	}
+	return key, err
+}
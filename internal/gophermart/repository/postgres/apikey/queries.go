@@ -0,0 +1,16 @@
+package apikey
+
+const insertStmt = `
+INSERT INTO api_keys (name, prefix, key_hash, scopes, signing_secret, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at;`
+
+const selectColumns = `
+SELECT id, name, prefix, key_hash, scopes, COALESCE(signing_secret, ''), created_by, created_at, revoked_at
+FROM api_keys`
+
+const findByHashStmt = selectColumns + " WHERE key_hash = $1;"
+
+const listStmt = selectColumns + " ORDER BY id;"
+
+const revokeStmt = "UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL;"
@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+type AuditRepository struct {
+	db storage.DB
+}
+
+const repoName = "postgres.AuditRepo."
+
+func NewAuditRepository(db storage.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+// Append records entry within tx, together with the operation it audits.
+func (*AuditRepository) Append(ctx context.Context, tx pgx.Tx, entry entity.AuditEntry) error {
+	return insert(ctx, tx, repoName+"Append", entry)
+}
+
+// Record records a standalone entry.
+func (r *AuditRepository) Record(ctx context.Context, entry entity.AuditEntry) error {
+	return insert(ctx, r.db, repoName+"Record", entry)
+}
+
+func insert(ctx context.Context, db execer, op string, entry entity.AuditEntry) error {
+	_, err := db.Exec(ctx, insertStmt, entry.ActorID, entry.Action, entry.Target, entry.Amount, entry.Reason)
+	if err != nil {
+		return storage.NewRepositoryError(op, err)
+	}
+	return nil
+}
+
+// List returns a page of the audit log, the latest entries first.
+func (r *AuditRepository) List(ctx context.Context, limit int, offset int) ([]entity.AuditEntry, error) {
+	const op = repoName + "List"
+	rows, err := r.db.Query(ctx, selectStmt, limit, offset)
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	defer rows.Close()
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByPos[entity.AuditEntry])
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	return entries, nil
+}
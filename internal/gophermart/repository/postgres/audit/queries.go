@@ -0,0 +1,11 @@
+package audit
+
+const insertStmt = `
+INSERT INTO audit_log (actor_id, action, target, amount, reason)
+VALUES ($1, $2, $3, $4, $5);`
+
+const selectStmt = `
+SELECT id, actor_id, action, target, amount, reason, created_at
+FROM audit_log
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2;`
@@ -2,6 +2,8 @@ package balance
 
 import (
 	"context"
+	"time"
+
 	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
@@ -24,22 +26,6 @@ func NewBalanceRepository(pool db) *BalanceRepository {
 	}
 }
 
-func (*BalanceRepository) Increase(ctx context.Context, tx pgx.Tx, userID int64, sum float32) error {
-	_, err := tx.Exec(ctx, increaseBalanceStmt, sum, userID)
-	if err != nil {
-		return storage.NewRepositoryError(repoName+"Increase", err)
-	}
-	return nil
-}
-
-func (*BalanceRepository) Withdraw(ctx context.Context, tx pgx.Tx, userID int64, sum float32) error {
-	_, err := tx.Exec(ctx, withdrawalStmt, sum, userID)
-	if err != nil {
-		return storage.NewRepositoryError(repoName+"Withdraw", err)
-	}
-	return nil
-}
-
 func (r *BalanceRepository) Get(ctx context.Context, userID int64) (entity.Balance, error) {
 	const op = repoName + "Get"
 	var balance entity.Balance
@@ -56,3 +42,32 @@ func (r *BalanceRepository) Get(ctx context.Context, userID int64) (entity.Balan
 
 	return balance, nil
 }
+
+// History returns a page of the user's ledger entries, newest first.
+func (r *BalanceRepository) History(
+	ctx context.Context,
+	userID int64,
+	filter entity.HistoryFilter,
+) ([]entity.BalanceHistoryEntry, error) {
+	const op = repoName + "History"
+	rows, err := r.db.Query(ctx, selectHistoryStmt,
+		userID, nullTime(filter.From), nullTime(filter.To), filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	defer rows.Close()
+
+	history, err := pgx.CollectRows(rows, pgx.RowToStructByName[entity.BalanceHistoryEntry])
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+
+	return history, nil
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -1,7 +1,18 @@
 package balance
 
-const increaseBalanceStmt = `UPDATE users SET balance = balance + $1 WHERE id = $2;`
-
-const withdrawalStmt = `UPDATE users SET balance = balance - $1, withdrawn=withdrawn + $1 WHERE id = $2;`
-
 const selectStmt = `SELECT balance, withdrawn FROM users WHERE id = $1`
+
+// selectHistoryStmt computes the running balance over all entries of the user
+// before the period and page are selected, so it is the same on every page.
+const selectHistoryStmt = `SELECT type, amount, balance, reference, created_at FROM (
+    SELECT l.id, l.type, l.amount, l.created_at,
+    SUM(l.amount) OVER (ORDER BY l.created_at, l.id) AS balance,
+    COALESCE(o.number, w.number, '') AS reference
+    FROM ledger_entries AS l
+    LEFT JOIN orders AS o ON o.id = l.order_id
+    LEFT JOIN withdrawals AS w ON w.id = l.withdrawal_id
+    WHERE l.user_id = $1
+) AS history
+WHERE ($2::timestamptz IS NULL OR created_at >= $2) AND ($3::timestamptz IS NULL OR created_at < $3)
+ORDER BY created_at DESC, id DESC
+LIMIT $4 OFFSET $5;`
@@ -0,0 +1,72 @@
+package combined
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/jackc/pgx/v5"
+)
+
+type auditRepo interface {
+	Append(ctx context.Context, tx pgx.Tx, entry entity.AuditEntry) error
+}
+
+// AuditedRepo changes orders and balances on behalf of support staff. Every
+// change is recorded in the audit log in the same transaction, so that there
+// is no change without an audit entry.
+type AuditedRepo struct {
+	db         db
+	orderRepo  orderRepo
+	ledgerRepo ledgerRepo
+	auditRepo  auditRepo
+}
+
+func NewAuditedRepo(pool db, order orderRepo, ledger ledgerRepo, audit auditRepo) *AuditedRepo {
+	return &AuditedRepo{
+		db:         pool,
+		orderRepo:  order,
+		ledgerRepo: ledger,
+		auditRepo:  audit,
+	}
+}
+
+// Adjust posts a manual balance adjustment.
+func (r *AuditedRepo) Adjust(ctx context.Context, entry entity.LedgerEntry, audit entity.AuditEntry) error {
+	return r.inTx(ctx, audit, func(tx pgx.Tx) error {
+		_, err := r.ledgerRepo.Append(ctx, tx, entry)
+		return err
+	})
+}
+
+// InvalidateOrder moves a pending order to INVALID, so that it is neither
+// polled nor credited any more.
+func (r *AuditedRepo) InvalidateOrder(ctx context.Context, number string, audit entity.AuditEntry) error {
+	return r.inTx(ctx, audit, func(tx pgx.Tx) error {
+		previous, updated, err := r.orderRepo.Update(ctx, tx, entity.Order{Number: number, Status: entity.OrderInvalid})
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return fmt.Errorf("%w: %s -> %s", common.ErrIllegalStatusTransition, previous, entity.OrderInvalid)
+		}
+		return nil
+	})
+}
+
+func (r *AuditedRepo) inTx(ctx context.Context, audit entity.AuditEntry, change func(tx pgx.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	if err := change(tx); err != nil {
+		return rollback(ctx, tx, err)
+	}
+	if err := r.auditRepo.Append(ctx, tx, audit); err != nil {
+		return rollback(ctx, tx, err)
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,155 @@
+package combined
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditedMocks struct {
+	db         *mocks.MockDBPool
+	orderRepo  *mocks.MockOrderRepository
+	ledgerRepo *mocks.MockLedgerRepository
+	auditRepo  *mocks.MockAuditRepository
+}
+
+func TestAuditedRepo_Adjust(t *testing.T) {
+	ctx := context.Background()
+	amount := entity.Money(-1050)
+	entry := entity.LedgerEntry{UserID: 1, Type: entity.LedgerAdjustment, Amount: amount, Reason: "duplicate accrual"}
+	audit := entity.AuditEntry{ActorID: 7, Action: entity.AuditBalanceAdjusted, Target: "1", Amount: &amount, Reason: "duplicate accrual"}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(m auditedMocks)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(m auditedMocks) {
+				tx := &mocks.MockTx{}
+				m.db.EXPECT().BeginTx(ctx, pgx.TxOptions{}).Return(tx, nil)
+				m.ledgerRepo.EXPECT().Append(ctx, tx, entry).Return(true, nil)
+				m.auditRepo.EXPECT().Append(ctx, tx, audit).Return(nil)
+				tx.CommitFn = func(ctx context.Context) error { return nil }
+			},
+		},
+		{
+			name: "Insufficient balance",
+			setupMocks: func(m auditedMocks) {
+				tx := &mocks.MockTx{}
+				m.db.EXPECT().BeginTx(ctx, pgx.TxOptions{}).Return(tx, nil)
+				m.ledgerRepo.EXPECT().Append(ctx, tx, entry).Return(false, common.ErrInsufficientBalance)
+				tx.RollbackFn = func(ctx context.Context) error { return nil }
+			},
+			expectedErr: common.ErrInsufficientBalance,
+		},
+		{
+			// Без записи в журнале изменение баланса откатывается
+			name: "Audit error",
+			setupMocks: func(m auditedMocks) {
+				tx := &mocks.MockTx{}
+				m.db.EXPECT().BeginTx(ctx, pgx.TxOptions{}).Return(tx, nil)
+				m.ledgerRepo.EXPECT().Append(ctx, tx, entry).Return(true, nil)
+				m.auditRepo.EXPECT().Append(ctx, tx, audit).Return(errors.New("audit error"))
+				tx.RollbackFn = func(ctx context.Context) error { return nil }
+			},
+			expectedErr: errors.New("audit error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := auditedMocks{
+				db:         mocks.NewMockDBPool(ctrl),
+				orderRepo:  mocks.NewMockOrderRepository(ctrl),
+				ledgerRepo: mocks.NewMockLedgerRepository(ctrl),
+				auditRepo:  mocks.NewMockAuditRepository(ctrl),
+			}
+			tt.setupMocks(m)
+
+			r := NewAuditedRepo(m.db, m.orderRepo, m.ledgerRepo, m.auditRepo)
+
+			err := r.Adjust(ctx, entry, audit)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuditedRepo_InvalidateOrder(t *testing.T) {
+	ctx := context.Background()
+	number := "12345678903"
+	invalid := entity.Order{Number: number, Status: entity.OrderInvalid}
+	audit := entity.AuditEntry{ActorID: 7, Action: entity.AuditOrderInvalidated, Target: number, Reason: "fraud"}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(m auditedMocks)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(m auditedMocks) {
+				tx := &mocks.MockTx{}
+				m.db.EXPECT().BeginTx(ctx, pgx.TxOptions{}).Return(tx, nil)
+				m.orderRepo.EXPECT().Update(ctx, tx, invalid).Return(entity.OrderProcessing, true, nil)
+				m.auditRepo.EXPECT().Append(ctx, tx, audit).Return(nil)
+				tx.CommitFn = func(ctx context.Context) error { return nil }
+			},
+		},
+		{
+			name: "Order is final",
+			setupMocks: func(m auditedMocks) {
+				tx := &mocks.MockTx{}
+				m.db.EXPECT().BeginTx(ctx, pgx.TxOptions{}).Return(tx, nil)
+				m.orderRepo.EXPECT().Update(ctx, tx, invalid).Return(entity.OrderProcessed, false, nil)
+				tx.RollbackFn = func(ctx context.Context) error { return nil }
+			},
+			expectedErr: common.ErrIllegalStatusTransition,
+		},
+		{
+			name: "Unknown order",
+			setupMocks: func(m auditedMocks) {
+				tx := &mocks.MockTx{}
+				m.db.EXPECT().BeginTx(ctx, pgx.TxOptions{}).Return(tx, nil)
+				m.orderRepo.EXPECT().Update(ctx, tx, invalid).Return(entity.OrderStatus(""), false, common.ErrNonExistentOrder)
+				tx.RollbackFn = func(ctx context.Context) error { return nil }
+			},
+			expectedErr: common.ErrNonExistentOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := auditedMocks{
+				db:         mocks.NewMockDBPool(ctrl),
+				orderRepo:  mocks.NewMockOrderRepository(ctrl),
+				ledgerRepo: mocks.NewMockLedgerRepository(ctrl),
+				auditRepo:  mocks.NewMockAuditRepository(ctrl),
+			}
+			tt.setupMocks(m)
+
+			r := NewAuditedRepo(m.db, m.orderRepo, m.ledgerRepo, m.auditRepo)
+
+			err := r.InvalidateOrder(ctx, number, audit)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -3,16 +3,18 @@ package combined
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/jackc/pgx/v5"
 )
 
 type orderRepo interface {
-	Update(ctx context.Context, tx pgx.Tx, order entity.Order) error
+	Update(ctx context.Context, tx pgx.Tx, order entity.Order) (entity.OrderStatus, bool, error)
 }
 
-type balanceRepo interface {
-	Increase(ctx context.Context, tx pgx.Tx, userID int64, accrual float32) error
+type ledgerRepo interface {
+	Append(ctx context.Context, tx pgx.Tx, entry entity.LedgerEntry) (bool, error)
 }
 
 type db interface {
@@ -22,14 +24,14 @@ type db interface {
 type OrderBalanceRepo struct {
 	db
 	orderRepo
-	balanceRepo
+	ledgerRepo
 }
 
-func NewOrderBalanceRepo(pool db, order orderRepo, balance balanceRepo) *OrderBalanceRepo {
+func NewOrderBalanceRepo(pool db, order orderRepo, ledger ledgerRepo) *OrderBalanceRepo {
 	return &OrderBalanceRepo{
-		db:          pool,
-		orderRepo:   order,
-		balanceRepo: balance,
+		db:         pool,
+		orderRepo:  order,
+		ledgerRepo: ledger,
 	}
 }
 
@@ -39,7 +41,7 @@ func (r *OrderBalanceRepo) UpdateOrderBalance(ctx context.Context, order entity.
 		return err
 	}
 
-	err = r.orderRepo.Update(ctx, tx, order)
+	previous, updated, err := r.orderRepo.Update(ctx, tx, order)
 	if err != nil {
 		rErr := tx.Rollback(ctx)
 		if rErr != nil {
@@ -48,8 +50,27 @@ func (r *OrderBalanceRepo) UpdateOrderBalance(ctx context.Context, order entity.
 		return err
 	}
 
-	if order.Accrual != nil {
-		err = r.balanceRepo.Increase(ctx, tx, order.UserID, *order.Accrual)
+	// The order has already reached this status, e.g. it was processed by
+	// another worker or by a previous attempt whose commit timed out. The
+	// accrual has been credited then and must not be credited twice.
+	// Any other rejected update is an illegal transition.
+	if !updated {
+		if rErr := tx.Rollback(ctx); rErr != nil {
+			return rErr
+		}
+		if previous == order.Status {
+			return nil
+		}
+		return fmt.Errorf("%w: %s -> %s", common.ErrIllegalStatusTransition, previous, order.Status)
+	}
+
+	if order.Accrual != nil && *order.Accrual > 0 {
+		_, err = r.ledgerRepo.Append(ctx, tx, entity.LedgerEntry{
+			UserID:      order.UserID,
+			Type:        entity.LedgerAccrual,
+			Amount:      *order.Accrual,
+			OrderNumber: order.Number,
+		})
 		if err != nil {
 			rErr := tx.Rollback(ctx)
 			if rErr != nil {
@@ -0,0 +1,192 @@
+package combined
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	ledgerrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/ledger"
+	orderrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/order"
+	"github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/pgtest"
+	userrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/user"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+// integrationDeps are the real repositories on a migrated database with a
+// user and its order in the PROCESSING status.
+type integrationDeps struct {
+	pool   *pgxpool.Pool
+	repo   *OrderBalanceRepo
+	orders *orderrepo.OrderRepository
+	order  entity.Order
+}
+
+func newIntegrationDeps(t *testing.T) integrationDeps {
+	ctx := context.Background()
+	pool := pgtest.NewPool(t)
+
+	users := userrepo.NewUserRepository(pool)
+	orders := orderrepo.NewOrderRepository(pool, 0)
+	repo := NewOrderBalanceRepo(pool, orders, ledgerrepo.NewLedgerRepository(pool))
+
+	suffix := time.Now().UnixNano()
+	userID, err := users.SaveUser(ctx, entity.User{
+		Login:    fmt.Sprintf("concurrent-%d", suffix),
+		Password: "hash",
+		Role:     entity.RoleUser,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	order := entity.Order{UserID: userID, Number: fmt.Sprint(suffix), Status: entity.OrderNew}
+	if !assert.NoError(t, orders.Save(ctx, order)) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "DELETE FROM ledger_entries WHERE user_id = $1", userID)
+		_, _ = pool.Exec(ctx, "DELETE FROM orders WHERE user_id = $1", userID)
+		_, _ = pool.Exec(ctx, "DELETE FROM users WHERE id = $1", userID)
+	})
+
+	order.Status = entity.OrderProcessing
+	if !assert.NoError(t, repo.UpdateOrderBalance(ctx, order)) {
+		t.FailNow()
+	}
+
+	return integrationDeps{pool: pool, repo: repo, orders: orders, order: order}
+}
+
+// credited returns the number and the sum of the accrual entries of the user.
+func (d integrationDeps) credited(t *testing.T) (int, entity.Money) {
+	var count int
+	var sum entity.Money
+	err := d.pool.QueryRow(context.Background(), `
+SELECT COUNT(*), COALESCE(SUM(amount), 0)
+FROM ledger_entries
+WHERE user_id = $1 AND type = 'ACCRUAL';`, d.order.UserID).Scan(&count, &sum)
+	assert.NoError(t, err)
+	return count, sum
+}
+
+func (d integrationDeps) status(t *testing.T) entity.OrderStatus {
+	order, err := d.orders.Find(context.Background(), d.order.Number)
+	assert.NoError(t, err)
+	return order.Status
+}
+
+// updateConcurrently applies the updates at once and returns their errors.
+func (d integrationDeps) updateConcurrently(updates []entity.Order) []error {
+	errs := make([]error, len(updates))
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i, update := range updates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = d.repo.UpdateOrderBalance(context.Background(), update)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+func TestOrderBalanceRepo_UpdateOrderBalance_concurrentDuplicates(t *testing.T) {
+	d := newIntegrationDeps(t)
+
+	processed := d.order
+	processed.Status = entity.OrderProcessed
+	processed.Accrual = moneyPtr(10000)
+
+	updates := make([]entity.Order, 20)
+	for i := range updates {
+		updates[i] = processed
+	}
+	for _, err := range d.updateConcurrently(updates) {
+		assert.NoError(t, err)
+	}
+
+	count, sum := d.credited(t)
+	assert.Equal(t, 1, count, "accrual must be credited exactly once")
+	assert.Equal(t, entity.Money(10000), sum)
+	assert.Equal(t, entity.OrderProcessed, d.status(t))
+
+	// Повторная обработка после успешного коммита тоже не начисляет баллы
+	assert.NoError(t, d.repo.UpdateOrderBalance(context.Background(), processed))
+	count, _ = d.credited(t)
+	assert.Equal(t, 1, count)
+}
+
+func TestOrderBalanceRepo_UpdateOrderBalance_concurrentConflictingStatuses(t *testing.T) {
+	d := newIntegrationDeps(t)
+
+	processed := d.order
+	processed.Status = entity.OrderProcessed
+	processed.Accrual = moneyPtr(10000)
+	invalid := d.order
+	invalid.Status = entity.OrderInvalid
+
+	updates := make([]entity.Order, 20)
+	for i := range updates {
+		updates[i] = processed
+		if i%2 == 1 {
+			updates[i] = invalid
+		}
+	}
+	errs := d.updateConcurrently(updates)
+
+	// Побеждает один из финальных статусов, переход из другого запрещён
+	final := d.status(t)
+	assert.True(t, final.IsFinal())
+	for i, err := range errs {
+		if updates[i].Status == final {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.True(t, errors.Is(err, common.ErrIllegalStatusTransition), "unexpected error: %v", err)
+	}
+
+	count, _ := d.credited(t)
+	if final == entity.OrderProcessed {
+		assert.Equal(t, 1, count)
+	} else {
+		assert.Equal(t, 0, count)
+	}
+}
+
+func TestOrderBalanceRepo_UpdateOrderBalance_finalStatusReleasesLease(t *testing.T) {
+	d := newIntegrationDeps(t)
+	ctx := context.Background()
+
+	_, err := d.pool.Exec(ctx,
+		"UPDATE orders SET locked_by = 'worker', locked_until = NOW() + INTERVAL '1 minute' WHERE number = $1",
+		d.order.Number)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	processed := d.order
+	processed.Status = entity.OrderProcessed
+	processed.Accrual = moneyPtr(10000)
+	assert.NoError(t, d.repo.UpdateOrderBalance(ctx, processed))
+
+	// Заказ в финальном статусе снимается с очереди вместе с арендой
+	var nextAttemptAt, lockedUntil *time.Time
+	var lockedBy *string
+	err = d.pool.QueryRow(ctx,
+		"SELECT next_attempt_at, locked_by, locked_until FROM orders WHERE number = $1",
+		d.order.Number).Scan(&nextAttemptAt, &lockedBy, &lockedUntil)
+	assert.NoError(t, err)
+	assert.Nil(t, nextAttemptAt)
+	assert.Nil(t, lockedBy)
+	assert.Nil(t, lockedUntil)
+}
@@ -3,8 +3,10 @@ package combined
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
 	"github.com/golang/mock/gomock"
@@ -17,35 +19,41 @@ func TestOrderBalanceRepo_UpdateOrderBalance(t *testing.T) {
 	order := entity.Order{
 		Number:  "12345",
 		UserID:  1,
-		Status:  "PROCESSED",
-		Accrual: float32Ptr(100.0),
+		Status:  entity.OrderProcessed,
+		Accrual: moneyPtr(10000),
 	}
 	orderNoAccrual := entity.Order{
 		Number: "67890",
 		UserID: 1,
-		Status: "PROCESSED",
+		Status: entity.OrderProcessed,
+	}
+	accrualEntry := entity.LedgerEntry{
+		UserID:      order.UserID,
+		Type:        entity.LedgerAccrual,
+		Amount:      *order.Accrual,
+		OrderNumber: order.Number,
 	}
 
 	tests := []struct {
 		name        string
 		order       entity.Order
-		setupMocks  func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, balanceRepo *mocks.MockBalanceRepository)
+		setupMocks  func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository)
 		expectedErr error
 	}{
 		{
 			name:  "Success with accrual",
 			order: order,
-			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, balanceRepo *mocks.MockBalanceRepository) {
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
 				tx := &mocks.MockTx{}
 				db.EXPECT().
 					BeginTx(ctx, pgx.TxOptions{}).
 					Return(tx, nil)
 				orderRepo.EXPECT().
 					Update(ctx, tx, order).
-					Return(nil)
-				balanceRepo.EXPECT().
-					Increase(ctx, tx, order.UserID, *order.Accrual).
-					Return(nil)
+					Return(entity.OrderProcessing, true, nil)
+				ledgerRepo.EXPECT().
+					Append(ctx, tx, accrualEntry).
+					Return(true, nil)
 				tx.CommitFn = func(ctx context.Context) error { return nil }
 			},
 			expectedErr: nil,
@@ -53,22 +61,58 @@ func TestOrderBalanceRepo_UpdateOrderBalance(t *testing.T) {
 		{
 			name:  "Success without accrual",
 			order: orderNoAccrual,
-			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, balanceRepo *mocks.MockBalanceRepository) {
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
 				tx := &mocks.MockTx{}
 				db.EXPECT().
 					BeginTx(ctx, pgx.TxOptions{}).
 					Return(tx, nil)
 				orderRepo.EXPECT().
 					Update(ctx, tx, orderNoAccrual).
-					Return(nil)
+					Return(entity.OrderProcessing, true, nil)
 				tx.CommitFn = func(ctx context.Context) error { return nil }
 			},
 			expectedErr: nil,
 		},
+		{
+			name:  "Order already in final status",
+			order: order,
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
+				tx := &mocks.MockTx{}
+				db.EXPECT().
+					BeginTx(ctx, pgx.TxOptions{}).
+					Return(tx, nil)
+				orderRepo.EXPECT().
+					Update(ctx, tx, order).
+					Return(entity.OrderProcessed, false, nil)
+				ledgerRepo.EXPECT().
+					Append(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+				tx.RollbackFn = func(ctx context.Context) error { return nil }
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Illegal status transition",
+			order: order,
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
+				tx := &mocks.MockTx{}
+				db.EXPECT().
+					BeginTx(ctx, pgx.TxOptions{}).
+					Return(tx, nil)
+				orderRepo.EXPECT().
+					Update(ctx, tx, order).
+					Return(entity.OrderInvalid, false, nil)
+				ledgerRepo.EXPECT().
+					Append(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+				tx.RollbackFn = func(ctx context.Context) error { return nil }
+			},
+			expectedErr: fmt.Errorf("%w: INVALID -> PROCESSED", common.ErrIllegalStatusTransition),
+		},
 		{
 			name:  "BeginTx error",
 			order: order,
-			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, balanceRepo *mocks.MockBalanceRepository) {
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
 				db.EXPECT().
 					BeginTx(ctx, pgx.TxOptions{}).
 					Return(nil, errors.New("begin tx error"))
@@ -78,50 +122,50 @@ func TestOrderBalanceRepo_UpdateOrderBalance(t *testing.T) {
 		{
 			name:  "Update order error",
 			order: order,
-			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, balanceRepo *mocks.MockBalanceRepository) {
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
 				tx := &mocks.MockTx{}
 				db.EXPECT().
 					BeginTx(ctx, pgx.TxOptions{}).
 					Return(tx, nil)
 				orderRepo.EXPECT().
 					Update(ctx, tx, order).
-					Return(errors.New("update error"))
+					Return(entity.OrderStatus(""), false, errors.New("update error"))
 				tx.RollbackFn = func(ctx context.Context) error { return nil }
 			},
 			expectedErr: errors.New("update error"),
 		},
 		{
-			name:  "Increase balance error",
+			name:  "Ledger error",
 			order: order,
-			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, balanceRepo *mocks.MockBalanceRepository) {
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
 				tx := &mocks.MockTx{}
 				db.EXPECT().
 					BeginTx(ctx, pgx.TxOptions{}).
 					Return(tx, nil)
 				orderRepo.EXPECT().
 					Update(ctx, tx, order).
-					Return(nil)
-				balanceRepo.EXPECT().
-					Increase(ctx, tx, order.UserID, *order.Accrual).
-					Return(errors.New("increase error"))
+					Return(entity.OrderProcessing, true, nil)
+				ledgerRepo.EXPECT().
+					Append(ctx, tx, accrualEntry).
+					Return(false, errors.New("ledger error"))
 				tx.RollbackFn = func(ctx context.Context) error { return nil }
 			},
-			expectedErr: errors.New("increase error"),
+			expectedErr: errors.New("ledger error"),
 		},
 		{
 			name:  "Commit error",
 			order: order,
-			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, balanceRepo *mocks.MockBalanceRepository) {
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
 				tx := &mocks.MockTx{}
 				db.EXPECT().
 					BeginTx(ctx, pgx.TxOptions{}).
 					Return(tx, nil)
 				orderRepo.EXPECT().
 					Update(ctx, tx, order).
-					Return(nil)
-				balanceRepo.EXPECT().
-					Increase(ctx, tx, order.UserID, *order.Accrual).
-					Return(nil)
+					Return(entity.OrderProcessing, true, nil)
+				ledgerRepo.EXPECT().
+					Append(ctx, tx, accrualEntry).
+					Return(true, nil)
 				tx.CommitFn = func(ctx context.Context) error { return errors.New("commit error") }
 			},
 			expectedErr: errors.New("commit error"),
@@ -129,14 +173,14 @@ func TestOrderBalanceRepo_UpdateOrderBalance(t *testing.T) {
 		{
 			name:  "Rollback error",
 			order: order,
-			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, balanceRepo *mocks.MockBalanceRepository) {
+			setupMocks: func(db *mocks.MockDBPool, orderRepo *mocks.MockOrderRepository, ledgerRepo *mocks.MockLedgerRepository) {
 				tx := &mocks.MockTx{}
 				db.EXPECT().
 					BeginTx(ctx, pgx.TxOptions{}).
 					Return(tx, nil)
 				orderRepo.EXPECT().
 					Update(ctx, tx, order).
-					Return(errors.New("update error"))
+					Return(entity.OrderStatus(""), false, errors.New("update error"))
 				tx.RollbackFn = func(ctx context.Context) error { return errors.New("rollback error") }
 			},
 			expectedErr: errors.New("rollback error\nupdate error"),
@@ -150,11 +194,11 @@ func TestOrderBalanceRepo_UpdateOrderBalance(t *testing.T) {
 
 			db := mocks.NewMockDBPool(ctrl)
 			orderRepo := mocks.NewMockOrderRepository(ctrl)
-			balanceRepo := mocks.NewMockBalanceRepository(ctrl)
+			ledgerRepo := mocks.NewMockLedgerRepository(ctrl)
 
-			tt.setupMocks(db, orderRepo, balanceRepo)
+			tt.setupMocks(db, orderRepo, ledgerRepo)
 
-			r := NewOrderBalanceRepo(db, orderRepo, balanceRepo)
+			r := NewOrderBalanceRepo(db, orderRepo, ledgerRepo)
 
 			err := r.UpdateOrderBalance(ctx, tt.order)
 
@@ -168,7 +212,7 @@ func TestOrderBalanceRepo_UpdateOrderBalance(t *testing.T) {
 	}
 }
 
-// Вспомогательная функция для создания указателя на float32
-func float32Ptr(f float32) *float32 {
-	return &f
+// Вспомогательная функция для создания указателя на сумму
+func moneyPtr(m entity.Money) *entity.Money {
+	return &m
 }
@@ -4,63 +4,55 @@ import (
 	"context"
 	"errors"
 
-	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type withdrawn interface {
-	Save(ctx context.Context, tx pgx.Tx, userID int64, withdrawn entity.Withdrawal) error
-}
-
-type balance interface {
-	Withdraw(ctx context.Context, tx pgx.Tx, userID int64, sum float32) error
+	Save(ctx context.Context, tx pgx.Tx, userID int64, withdrawn entity.Withdrawal) (int64, error)
 }
 
 type BalanceWithdrawnRepo struct {
-	db             *pgxpool.Pool
-	balanceRepo    balance
+	db             db
+	ledgerRepo     ledgerRepo
 	withdrawalRepo withdrawn
 }
 
-func NewBalanceWithdrawnRepo(db *pgxpool.Pool, bRepo balance, wRepo withdrawn) *BalanceWithdrawnRepo {
+func NewBalanceWithdrawnRepo(pool db, lRepo ledgerRepo, wRepo withdrawn) *BalanceWithdrawnRepo {
 	return &BalanceWithdrawnRepo{
-		db:             db,
-		balanceRepo:    bRepo,
+		db:             pool,
+		ledgerRepo:     lRepo,
 		withdrawalRepo: wRepo,
 	}
 }
 
 func (r *BalanceWithdrawnRepo) Withdraw(ctx context.Context, userID int64, withdrawal entity.Withdrawal) error {
-	tx, err := r.db.Begin(ctx)
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return err
 	}
 
-	err = r.balanceRepo.Withdraw(ctx, tx, userID, withdrawal.Sum)
-
+	id, err := r.withdrawalRepo.Save(ctx, tx, userID, withdrawal)
 	if err != nil {
-		err := tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
-			return common.ErrInsufficientBalance
-		}
-		return err
+		return rollback(ctx, tx, err)
 	}
 
-	err = r.withdrawalRepo.Save(ctx, tx, userID, withdrawal)
+	_, err = r.ledgerRepo.Append(ctx, tx, entity.LedgerEntry{
+		UserID:       userID,
+		Type:         entity.LedgerWithdrawal,
+		Amount:       -withdrawal.Sum,
+		WithdrawalID: id,
+	})
 	if err != nil {
-		err := tx.Rollback(ctx)
-		if err != nil {
-			return err
-		}
-		return err
+		return rollback(ctx, tx, err)
 	}
 
 	return tx.Commit(ctx)
 }
+
+func rollback(ctx context.Context, tx pgx.Tx, err error) error {
+	if rErr := tx.Rollback(ctx); rErr != nil {
+		return errors.Join(rErr, err)
+	}
+	return err
+}
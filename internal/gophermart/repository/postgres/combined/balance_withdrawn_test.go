@@ -0,0 +1,117 @@
+package combined
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceWithdrawnRepo_Withdraw(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+	withdrawal := entity.Withdrawal{
+		Order:       "2377225624",
+		Sum:         75110,
+		ProcessedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	debitEntry := entity.LedgerEntry{
+		UserID:       userID,
+		Type:         entity.LedgerWithdrawal,
+		Amount:       -75110,
+		WithdrawalID: 7,
+	}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(db *mocks.MockDBPool, ledgerRepo *mocks.MockLedgerRepository, withdrawalRepo *mocks.MockWithdrawalRepository)
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(db *mocks.MockDBPool, ledgerRepo *mocks.MockLedgerRepository, withdrawalRepo *mocks.MockWithdrawalRepository) {
+				tx := &mocks.MockTx{}
+				db.EXPECT().
+					BeginTx(ctx, pgx.TxOptions{}).
+					Return(tx, nil)
+				withdrawalRepo.EXPECT().
+					Save(ctx, tx, userID, withdrawal).
+					Return(int64(7), nil)
+				ledgerRepo.EXPECT().
+					Append(ctx, tx, debitEntry).
+					Return(true, nil)
+				tx.CommitFn = func(ctx context.Context) error { return nil }
+			},
+		},
+		{
+			name: "Insufficient balance",
+			setupMocks: func(db *mocks.MockDBPool, ledgerRepo *mocks.MockLedgerRepository, withdrawalRepo *mocks.MockWithdrawalRepository) {
+				tx := &mocks.MockTx{}
+				db.EXPECT().
+					BeginTx(ctx, pgx.TxOptions{}).
+					Return(tx, nil)
+				withdrawalRepo.EXPECT().
+					Save(ctx, tx, userID, withdrawal).
+					Return(int64(7), nil)
+				ledgerRepo.EXPECT().
+					Append(ctx, tx, debitEntry).
+					Return(false, common.ErrInsufficientBalance)
+				tx.RollbackFn = func(ctx context.Context) error { return nil }
+			},
+			expectedErr: common.ErrInsufficientBalance,
+		},
+		{
+			name: "Save withdrawal error",
+			setupMocks: func(db *mocks.MockDBPool, ledgerRepo *mocks.MockLedgerRepository, withdrawalRepo *mocks.MockWithdrawalRepository) {
+				tx := &mocks.MockTx{}
+				db.EXPECT().
+					BeginTx(ctx, pgx.TxOptions{}).
+					Return(tx, nil)
+				withdrawalRepo.EXPECT().
+					Save(ctx, tx, userID, withdrawal).
+					Return(int64(0), errors.New("save error"))
+				tx.RollbackFn = func(ctx context.Context) error { return nil }
+			},
+			expectedErr: errors.New("save error"),
+		},
+		{
+			name: "BeginTx error",
+			setupMocks: func(db *mocks.MockDBPool, ledgerRepo *mocks.MockLedgerRepository, withdrawalRepo *mocks.MockWithdrawalRepository) {
+				db.EXPECT().
+					BeginTx(ctx, pgx.TxOptions{}).
+					Return(nil, errors.New("begin tx error"))
+			},
+			expectedErr: errors.New("begin tx error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			db := mocks.NewMockDBPool(ctrl)
+			ledgerRepo := mocks.NewMockLedgerRepository(ctrl)
+			withdrawalRepo := mocks.NewMockWithdrawalRepository(ctrl)
+
+			tt.setupMocks(db, ledgerRepo, withdrawalRepo)
+
+			r := NewBalanceWithdrawnRepo(db, ledgerRepo, withdrawalRepo)
+
+			err := r.Withdraw(ctx, userID, withdrawal)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
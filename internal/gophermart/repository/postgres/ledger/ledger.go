@@ -0,0 +1,81 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	checkViolation      = "23514"
+	foreignKeyViolation = "23503"
+
+	balanceConstraint = "users_balance_check"
+	userConstraint    = "fk_ledger_entries_users"
+)
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+// LedgerRepository records balance changes in ledger_entries and keeps the
+// balance snapshot in users up to date.
+type LedgerRepository struct {
+	db storage.DB
+}
+
+const repoName = "postgres.LedgerRepo."
+
+func NewLedgerRepository(db storage.DB) *LedgerRepository {
+	return &LedgerRepository{
+		db: db,
+	}
+}
+
+// Append records entry within tx. It returns false if the order or the
+// withdrawal the entry refers to has already been recorded.
+func (*LedgerRepository) Append(ctx context.Context, tx pgx.Tx, entry entity.LedgerEntry) (bool, error) {
+	return appendEntry(ctx, tx, repoName+"Append", entry)
+}
+
+func appendEntry(ctx context.Context, db execer, op string, entry entity.LedgerEntry) (bool, error) {
+	tag, err := db.Exec(ctx, appendStmt,
+		entry.UserID, entry.Type, entry.Amount, entry.OrderNumber, entry.WithdrawalID, entry.Reason)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch {
+			case pgErr.Code == checkViolation && pgErr.ConstraintName == balanceConstraint:
+				return false, storage.NewRepositoryError(op, common.ErrInsufficientBalance)
+			case pgErr.Code == foreignKeyViolation && pgErr.ConstraintName == userConstraint:
+				return false, storage.NewRepositoryError(op, common.ErrUserNotFound)
+			}
+		}
+		return false, storage.NewRepositoryError(op, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Mismatches returns users whose balance snapshot does not match the sum of
+// their ledger entries.
+func (r *LedgerRepository) Mismatches(ctx context.Context) ([]entity.BalanceMismatch, error) {
+	const op = repoName + "Mismatches"
+	rows, err := r.db.Query(ctx, selectMismatchesStmt)
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	defer rows.Close()
+
+	mismatches, err := pgx.CollectRows(rows, pgx.RowToStructByName[entity.BalanceMismatch])
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+
+	return mismatches, nil
+}
@@ -0,0 +1,26 @@
+package ledger
+
+// appendStmt records an entry and applies it to the balance snapshot. A
+// repeated accrual of an order or debit of a withdrawal conflicts with the
+// unique indexes and changes nothing.
+const appendStmt = `WITH entry AS (
+    INSERT INTO ledger_entries (user_id, type, amount, order_id, withdrawal_id, reason)
+    VALUES ($1, $2, $3, (SELECT id FROM orders WHERE number = NULLIF($4, '')), NULLIF($5, 0), NULLIF($6, ''))
+    ON CONFLICT DO NOTHING
+    RETURNING user_id, type, amount
+)
+UPDATE users AS u
+SET balance = u.balance + entry.amount,
+withdrawn = u.withdrawn - CASE WHEN entry.type = 'WITHDRAWAL' THEN entry.amount ELSE 0 END
+FROM entry
+WHERE u.id = entry.user_id;`
+
+const selectMismatchesStmt = `SELECT u.id AS user_id, u.balance, u.withdrawn,
+COALESCE(l.balance, 0) AS ledger_balance, COALESCE(l.withdrawn, 0) AS ledger_withdrawn
+FROM users AS u LEFT JOIN (
+    SELECT user_id, SUM(amount) AS balance,
+    COALESCE(-SUM(amount) FILTER (WHERE type = 'WITHDRAWAL'), 0) AS withdrawn
+    FROM ledger_entries GROUP BY user_id
+) AS l ON l.user_id = u.id
+WHERE u.balance <> COALESCE(l.balance, 0) OR u.withdrawn <> COALESCE(l.withdrawn, 0)
+ORDER BY u.id;`
@@ -0,0 +1,118 @@
+package loginattempt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LoginAttemptRepository counts login attempts.
+type LoginAttemptRepository struct {
+	db *pgxpool.Pool
+}
+
+const repoName = "postgres.LoginAttemptRepo."
+
+func NewLoginAttemptRepository(db *pgxpool.Pool) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+// LockedUntil returns the end of the latest lockout of the keys, the zero
+// time if none is locked.
+func (r *LoginAttemptRepository) LockedUntil(ctx context.Context, keys []entity.LoginAttemptKey) (time.Time, error) {
+	scopes := make([]string, 0, len(keys))
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		scopes = append(scopes, string(k.Scope))
+		names = append(names, k.Key)
+	}
+
+	var lockedUntil *time.Time
+	err := r.db.QueryRow(ctx, selectLockedUntilStmt, scopes, names).Scan(&lockedUntil)
+	if err != nil {
+		return time.Time{}, storage.NewRepositoryError(repoName+"LockedUntil", err)
+	}
+	if lockedUntil == nil {
+		return time.Time{}, nil
+	}
+	return *lockedUntil, nil
+}
+
+// Reserve counts an attempt for every key at now, unless one of them is
+// locked, and returns whether the attempt is allowed. The keys are counted in
+// one transaction, so either all of them or none are. Callers pass the keys
+// in the same scope order to avoid deadlocks.
+func (r *LoginAttemptRepository) Reserve(
+	ctx context.Context,
+	keys []entity.LoginAttemptKey,
+	now time.Time,
+	windowStart time.Time,
+) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, storage.NewRepositoryError(repoName+"Reserve", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, k := range keys {
+		var failures int
+		err := tx.QueryRow(
+			ctx,
+			reserveStmt,
+			k.Scope,
+			k.Key,
+			now,
+			windowStart,
+			k.FreeAttempts,
+			k.BaseLockout.Seconds(),
+			k.MaxLockout.Seconds(),
+		).Scan(&failures)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		if err != nil {
+			return false, storage.NewRepositoryError(repoName+"Reserve", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, storage.NewRepositoryError(repoName+"Reserve", err)
+	}
+	return true, nil
+}
+
+// Release takes back a reserved attempt of the key which has succeeded.
+func (r *LoginAttemptRepository) Release(ctx context.Context, key entity.LoginAttemptKey) error {
+	_, err := r.db.Exec(ctx, releaseStmt, key.Scope, key.Key, key.FreeAttempts)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"Release", err)
+	}
+	return nil
+}
+
+// Reset forgets the failures of the key and lifts its lockout.
+func (r *LoginAttemptRepository) Reset(ctx context.Context, scope entity.LoginAttemptScope, key string) error {
+	_, err := r.db.Exec(ctx, deleteStmt, scope, key)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"Reset", err)
+	}
+	return nil
+}
+
+// DeleteStale removes the counters which have not failed since before and
+// are not locked at now.
+func (r *LoginAttemptRepository) DeleteStale(ctx context.Context, before time.Time, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, deleteStaleStmt, before, now)
+	if err != nil {
+		return 0, storage.NewRepositoryError(repoName+"DeleteStale", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,142 @@
+package loginattempt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/pgtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// testKey returns a login key of its own for the test.
+func testKey(t *testing.T, repo *LoginAttemptRepository) entity.LoginAttemptKey {
+	key := entity.LoginAttemptKey{
+		Scope:        entity.LoginAttemptByLogin,
+		Key:          fmt.Sprintf("attempts-%d", time.Now().UnixNano()),
+		FreeAttempts: 3,
+		BaseLockout:  time.Minute,
+		MaxLockout:   time.Hour,
+	}
+	t.Cleanup(func() {
+		_ = repo.Reset(context.Background(), key.Scope, key.Key)
+	})
+	return key
+}
+
+func TestLoginAttemptRepository_Reserve_locksAfterFreeAttempts(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewLoginAttemptRepository(pool)
+	ctx := context.Background()
+	key := testKey(t, repo)
+	now := time.Now().Truncate(time.Microsecond)
+	windowStart := now.Add(-time.Hour)
+
+	// Свободные попытки и первая сверх них разрешены, последняя блокирует логин
+	for i := 0; i <= key.FreeAttempts; i++ {
+		allowed, err := repo.Reserve(ctx, []entity.LoginAttemptKey{key}, now, windowStart)
+		assert.NoError(t, err)
+		assert.True(t, allowed, "attempt %d", i+1)
+	}
+
+	allowed, err := repo.Reserve(ctx, []entity.LoginAttemptKey{key}, now, windowStart)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	lockedUntil, err := repo.LockedUntil(ctx, []entity.LoginAttemptKey{key})
+	assert.NoError(t, err)
+	assert.True(t, lockedUntil.Equal(now.Add(key.BaseLockout)), "locked until %v", lockedUntil)
+
+	// После блокировки следующая попытка блокирует вдвое дольше
+	later := lockedUntil
+	allowed, err = repo.Reserve(ctx, []entity.LoginAttemptKey{key}, later, windowStart)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	lockedUntil, err = repo.LockedUntil(ctx, []entity.LoginAttemptKey{key})
+	assert.NoError(t, err)
+	assert.True(t, lockedUntil.Equal(later.Add(2*key.BaseLockout)), "locked until %v", lockedUntil)
+}
+
+func TestLoginAttemptRepository_Reserve_parallel(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewLoginAttemptRepository(pool)
+	ctx := context.Background()
+	key := testKey(t, repo)
+	now := time.Now()
+
+	const guesses = 20
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := repo.Reserve(ctx, []entity.LoginAttemptKey{key}, now, now.Add(-time.Hour))
+			assert.NoError(t, err)
+			if ok {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, key.FreeAttempts+1, allowed)
+}
+
+func TestLoginAttemptRepository_Release(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewLoginAttemptRepository(pool)
+	ctx := context.Background()
+	key := testKey(t, repo)
+	now := time.Now()
+	windowStart := now.Add(-time.Hour)
+
+	for i := 0; i <= key.FreeAttempts; i++ {
+		_, err := repo.Reserve(ctx, []entity.LoginAttemptKey{key}, now, windowStart)
+		assert.NoError(t, err)
+	}
+
+	// Успешная попытка сверх свободных снимает блокировку, которую она поставила
+	assert.NoError(t, repo.Release(ctx, key))
+
+	allowed, err := repo.Reserve(ctx, []entity.LoginAttemptKey{key}, now, windowStart)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestLoginAttemptRepository_Reserve_resetScopeApart(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewLoginAttemptRepository(pool)
+	ctx := context.Background()
+	login := testKey(t, repo)
+	reset := login
+	reset.Scope = entity.PasswordResetByLogin
+	t.Cleanup(func() {
+		_ = repo.Reset(context.Background(), reset.Scope, reset.Key)
+	})
+	now := time.Now()
+	windowStart := now.Add(-time.Hour)
+
+	// Запросы сброса блокируют только сброс, но не вход
+	for i := 0; i <= reset.FreeAttempts; i++ {
+		_, err := repo.Reserve(ctx, []entity.LoginAttemptKey{reset}, now, windowStart)
+		assert.NoError(t, err)
+	}
+
+	allowed, err := repo.Reserve(ctx, []entity.LoginAttemptKey{reset}, now, windowStart)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = repo.Reserve(ctx, []entity.LoginAttemptKey{login}, now, windowStart)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+}
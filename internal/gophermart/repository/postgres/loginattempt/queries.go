@@ -0,0 +1,44 @@
+package loginattempt
+
+const selectLockedUntilStmt = `
+SELECT MAX(a.locked_until)
+FROM login_attempts a
+JOIN unnest($1::text[], $2::text[]) AS k (scope, key) ON a.scope = k.scope AND a.key = k.key;`
+
+// reserveStmt counts an attempt of the key unless it is locked at $3. The
+// counter starts over if the previous attempt happened before the window
+// starting at $4. Once $5 attempts have been made, every further attempt
+// locks the key for $6 seconds, doubled with each attempt up to $7 seconds.
+// The lockout is set by the same statement that counts the attempt, so a
+// concurrent attempt waits for the row and sees the lockout. No row is
+// returned if the key is locked.
+const reserveStmt = `
+INSERT INTO login_attempts AS a (scope, key, failures, last_failure_at)
+VALUES ($1, $2, 1, $3)
+ON CONFLICT (scope, key) DO UPDATE
+SET failures = CASE
+        WHEN a.last_failure_at < $4 THEN 1
+        ELSE a.failures + 1
+    END,
+    last_failure_at = EXCLUDED.last_failure_at,
+    locked_until = CASE
+        WHEN a.last_failure_at >= $4 AND a.failures >= $5
+        THEN $3 + make_interval(secs => LEAST($7::float8, $6::float8 * power(2, LEAST(a.failures - $5, 32))))
+        ELSE a.locked_until
+    END
+WHERE a.locked_until IS NULL OR a.locked_until <= $3
+RETURNING a.failures;`
+
+// releaseStmt takes back an attempt which has succeeded. The lockout is
+// lifted if the attempts left are within the $3 free ones.
+const releaseStmt = `
+UPDATE login_attempts
+SET failures = GREATEST(failures - 1, 0),
+    locked_until = CASE WHEN failures - 1 <= $3 THEN NULL ELSE locked_until END
+WHERE scope = $1 AND key = $2;`
+
+const deleteStmt = "DELETE FROM login_attempts WHERE scope = $1 AND key = $2;"
+
+const deleteStaleStmt = `
+DELETE FROM login_attempts
+WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2);`
@@ -0,0 +1,54 @@
+package nonce
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NonceRepository stores the nonces of accepted signed requests.
+type NonceRepository struct {
+	db *pgxpool.Pool
+}
+
+const repoName = "postgres.NonceRepo."
+
+func NewNonceRepository(db *pgxpool.Pool) *NonceRepository {
+	return &NonceRepository{
+		db: db,
+	}
+}
+
+// Remember records the nonce of the key until expiresAt and reports false if
+// it is already recorded and has not expired at now.
+func (r *NonceRepository) Remember(
+	ctx context.Context,
+	keyID int64,
+	nonce string,
+	now time.Time,
+	expiresAt time.Time,
+) (bool, error) {
+	var id int64
+	err := r.db.QueryRow(ctx, rememberStmt, keyID, nonce, now, expiresAt).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, storage.NewRepositoryError(repoName+"Remember", err)
+	}
+	return true, nil
+}
+
+// DeleteExpired removes the nonces expired at now.
+func (r *NonceRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, deleteExpiredStmt, now)
+	if err != nil {
+		return 0, storage.NewRepositoryError(repoName+"DeleteExpired", err)
+	}
+	return tag.RowsAffected(), nil
+}
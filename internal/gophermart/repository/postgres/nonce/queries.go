@@ -0,0 +1,13 @@
+package nonce
+
+// rememberStmt records a nonce. An expired nonce is taken over, a live one is
+// left as is and no row is returned.
+const rememberStmt = `
+INSERT INTO signature_nonces (key_id, nonce, expires_at)
+VALUES ($1, $2, $4)
+ON CONFLICT (key_id, nonce) DO UPDATE
+SET expires_at = EXCLUDED.expires_at
+WHERE signature_nonces.expires_at < $3
+RETURNING key_id;`
+
+const deleteExpiredStmt = "DELETE FROM signature_nonces WHERE expires_at < $1;"
@@ -3,22 +3,30 @@ package order
 import (
 	"context"
 	"errors"
+	"time"
+
 	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
 
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type OrderRepository struct {
-	db *pgxpool.Pool
+	db             *pgxpool.Pool
+	firstPollDelay time.Duration
 }
 
 const repoName = "postgres.OrderRepo."
 
-func NewOrderRepository(pool *pgxpool.Pool) *OrderRepository {
+// NewOrderRepository creates the repository. A saved order is first polled
+// after firstPollDelay, e.g. to give the accrual system time to report its
+// status with a callback.
+func NewOrderRepository(pool *pgxpool.Pool, firstPollDelay time.Duration) *OrderRepository {
 	return &OrderRepository{
-		db: pool,
+		db:             pool,
+		firstPollDelay: firstPollDelay,
 	}
 }
 
@@ -31,6 +39,7 @@ func (r *OrderRepository) Save(ctx context.Context, order entity.Order) error {
 		order.Status,
 		order.Accrual,
 		order.UploadedAt,
+		r.firstPollDelay.Seconds(),
 	)
 	if err != nil {
 		return storage.NewRepositoryError(repoName+"Save", err)
@@ -55,12 +64,31 @@ func (r *OrderRepository) Find(ctx context.Context, number string) (entity.Order
 	return order, storage.NewRepositoryError(op, err)
 }
 
-func (r *OrderRepository) Update(ctx context.Context, tx pgx.Tx, order entity.Order) error {
-	_, err := tx.Exec(ctx, updateStmt, order.Status, order.Accrual, order.Number)
+// Update moves an order to order.Status and sets its accrual if the status
+// transition is allowed. It returns the status the order had before and
+// whether the order has been updated.
+func (r *OrderRepository) Update(ctx context.Context, tx pgx.Tx, order entity.Order) (entity.OrderStatus, bool, error) {
+	const op = repoName + "Update"
+	var (
+		previous entity.OrderStatus
+		updated  bool
+	)
+
+	predecessors := make([]string, 0, len(order.Status.Predecessors()))
+	for _, status := range order.Status.Predecessors() {
+		predecessors = append(predecessors, string(status))
+	}
+
+	err := tx.QueryRow(ctx, updateStmt, order.Status, order.Accrual, order.Number, predecessors).
+		Scan(&previous, &updated)
 	if err != nil {
-		return storage.NewRepositoryError(repoName+"Update", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return previous, false, storage.NewRepositoryError(op, common.ErrNonExistentOrder)
+		}
+		return previous, false, storage.NewRepositoryError(op, err)
 	}
-	return nil
+
+	return previous, updated, nil
 }
 
 func (r *OrderRepository) GetAll(ctx context.Context, userID int64) ([]entity.Order, error) {
@@ -1,18 +1,36 @@
 package order
 
-const selectAllStmt = `SELECT o.user_id, o.number, s.status, o.accrual, o.uploaded_at
+const selectAllStmt = `SELECT o.user_id, o.number, s.status, o.accrual, o.uploaded_at, o.attempts
 FROM orders AS o JOIN order_statuses AS s ON o.status_id = s.id
 WHERE user_id = $1 ORDER BY o.uploaded_at DESC;`
 
-const selectByNumber = `SELECT o.user_id, o.number, s.status, o.accrual, o.uploaded_at
+const selectByNumber = `SELECT o.user_id, o.number, s.status, o.accrual, o.uploaded_at, o.attempts
 FROM orders AS o JOIN order_statuses AS s ON o.status_id = s.id
 WHERE number = $1;`
 
-const insertStmt = `INSERT INTO orders (user_id, number, status_id, accrual, uploaded_at)
+const insertStmt = `INSERT INTO orders (user_id, number, status_id, accrual, uploaded_at, next_attempt_at)
 VALUES ($1,$2,
 (SELECT id FROM order_statuses WHERE status=$3),
-$4, $5);`
+$4, $5, NOW() + make_interval(secs => $6));`
 
-const updateStmt = `UPDATE orders
-SET status_id = (SELECT id FROM order_statuses WHERE status=$1), accrual = $2
-WHERE number=$3;`
+// updateStmt moves an order to status $1 only if its current status is one of
+// the allowed predecessors $4, and returns the status the order had before.
+// The row is locked first: a concurrent update of the same order waits for the
+// lock and then sees the committed status, so only one of them can move the
+// order to PROCESSED and credit the accrual. A final status takes the order
+// off the queue: it is not polled again and its lease is released.
+const updateStmt = `WITH current AS (
+    SELECT id, status_id FROM orders WHERE number = $3 FOR UPDATE
+), updated AS (
+    UPDATE orders AS o
+    SET status_id = (SELECT id FROM order_statuses WHERE status = $1), accrual = $2,
+    next_attempt_at = CASE WHEN $1 IN ('INVALID', 'PROCESSED') THEN NULL ELSE o.next_attempt_at END,
+    locked_by = CASE WHEN $1 IN ('INVALID', 'PROCESSED') THEN NULL ELSE o.locked_by END,
+    locked_until = CASE WHEN $1 IN ('INVALID', 'PROCESSED') THEN NULL ELSE o.locked_until END
+    FROM current
+    WHERE o.id = current.id
+    AND current.status_id IN (SELECT id FROM order_statuses WHERE status = ANY($4))
+    RETURNING o.id
+)
+SELECT s.status, EXISTS (SELECT 1 FROM updated)
+FROM current JOIN order_statuses AS s ON s.id = current.status_id;`
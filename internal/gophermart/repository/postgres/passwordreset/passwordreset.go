@@ -0,0 +1,49 @@
+package passwordreset
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PasswordResetRepository struct {
+	db *pgxpool.Pool
+}
+
+const repoName = "postgres.PasswordResetRepo."
+
+func NewPasswordResetRepository(db *pgxpool.Pool) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+func (r *PasswordResetRepository) Save(ctx context.Context, reset entity.PasswordReset) error {
+	_, err := r.db.Exec(ctx, insertStmt, reset.UserID, reset.Hash, reset.ExpiresAt)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"Save", err)
+	}
+	return nil
+}
+
+// Consume marks the token with the given hash as used and returns the id of
+// the user who requested it. Unknown, used and expired tokens are rejected
+// with common.ErrInvalidToken.
+func (r *PasswordResetRepository) Consume(ctx context.Context, hash string) (int64, error) {
+	const op = repoName + "Consume"
+	var userID int64
+	err := r.db.QueryRow(ctx, consumeStmt, hash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, storage.NewRepositoryError(op, common.ErrInvalidToken)
+		}
+		return 0, storage.NewRepositoryError(op, err)
+	}
+	return userID, nil
+}
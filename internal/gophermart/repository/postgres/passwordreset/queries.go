@@ -0,0 +1,22 @@
+package passwordreset
+
+const insertStmt = `
+INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3);`
+
+// consumeStmt marks a valid token as used and invalidates the other tokens
+// requested by the same user, so that only one reset succeeds.
+const consumeStmt = `
+WITH consumed AS (
+    UPDATE password_reset_tokens
+    SET used_at = NOW()
+    WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+    RETURNING user_id
+), invalidated AS (
+    UPDATE password_reset_tokens
+    SET used_at = NOW()
+    WHERE user_id IN (SELECT user_id FROM consumed)
+      AND token_hash <> $1
+      AND used_at IS NULL
+)
+SELECT user_id FROM consumed;`
@@ -0,0 +1,45 @@
+// Package pgtest provides a migrated Postgres database to the repository
+// tests which cannot be covered with mocks.
+package pgtest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/migrator"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+// DatabaseEnv names the DSN of a disposable database for the tests which
+// need Postgres. They are skipped if it is not set.
+const DatabaseEnv = "TEST_DATABASE_URI"
+
+// NewPool migrates the test database and returns a pool closed when the test
+// ends. The test is skipped if no database is configured.
+func NewPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv(DatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", DatabaseEnv)
+	}
+	ctx := context.Background()
+
+	migrationPool, err := pgxpool.New(ctx, dsn)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	m, err := migrator.NewMigrator(migrationPool)
+	if !assert.NoError(t, err) || !assert.NoError(t, m.InitializeDB()) {
+		t.FailNow()
+	}
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
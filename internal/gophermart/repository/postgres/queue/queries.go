@@ -0,0 +1,68 @@
+package queue
+
+// pullStmt leases due orders to a single worker. Rows locked by a concurrent
+// pull are skipped, and leases of crashed workers expire by locked_until.
+const pullStmt = `WITH due AS (
+    SELECT id FROM orders
+    WHERE next_attempt_at <= NOW() AND (locked_until IS NULL OR locked_until < NOW())
+    ORDER BY next_attempt_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE orders AS o
+SET locked_by = $1, locked_until = NOW() + make_interval(secs => $3)
+FROM due, order_statuses AS s
+WHERE o.id = due.id AND s.id = o.status_id
+RETURNING o.user_id, o.number, s.status, o.accrual, o.uploaded_at, o.attempts;`
+
+// rescheduleStmt keeps a later next_attempt_at, so that a poll postponed by a
+// callback while the order was leased is not brought forward.
+const rescheduleStmt = `UPDATE orders
+SET next_attempt_at = GREATEST(next_attempt_at, NOW() + make_interval(secs => $3)), attempts = 0, last_error = NULL,
+locked_by = NULL, locked_until = NULL
+WHERE number = $2 AND locked_by = $1 AND next_attempt_at IS NOT NULL;`
+
+// releaseStmt returns a leased order which has not been polled to the queue.
+// Its failed attempts are kept, so that an order failing between throttled
+// polls is still dead-lettered.
+const releaseStmt = `UPDATE orders
+SET next_attempt_at = GREATEST(next_attempt_at, NOW() + make_interval(secs => $3)),
+locked_by = NULL, locked_until = NULL
+WHERE number = $2 AND locked_by = $1 AND next_attempt_at IS NOT NULL;`
+
+const retryStmt = `UPDATE orders
+SET next_attempt_at = NOW() + make_interval(secs => $4), attempts = attempts + 1, last_error = $3,
+locked_by = NULL, locked_until = NULL
+WHERE number = $2 AND locked_by = $1 AND next_attempt_at IS NOT NULL;`
+
+// postponeStmt moves the next poll of a queued order, leased or not. Orders
+// out of the queue, processed or dead-lettered, are left alone. Failed
+// attempts are kept: a callback is no successful poll, and an order whose
+// polls keep failing is still dead-lettered.
+const postponeStmt = `UPDATE orders
+SET next_attempt_at = NOW() + make_interval(secs => $2)
+WHERE number = $1 AND next_attempt_at IS NOT NULL;`
+
+const deadLetterStmt = `UPDATE orders
+SET next_attempt_at = NULL, attempts = attempts + 1, last_error = $3, dead_lettered_at = NOW(),
+locked_by = NULL, locked_until = NULL
+WHERE number = $2 AND locked_by = $1 AND next_attempt_at IS NOT NULL;`
+
+const selectDeadLettersStmt = `SELECT o.user_id, o.number, s.status, o.accrual, o.uploaded_at, o.attempts,
+COALESCE(o.last_error, '') AS last_error, o.dead_lettered_at
+FROM orders AS o JOIN order_statuses AS s ON o.status_id = s.id
+WHERE o.dead_lettered_at IS NOT NULL ORDER BY o.dead_lettered_at DESC;`
+
+// requeueStmt makes a pending order due right away, dead-lettered or not, and
+// returns its status. Orders in a final status are not polled again.
+const requeueStmt = `WITH current AS (
+    SELECT o.id, s.status FROM orders AS o JOIN order_statuses AS s ON o.status_id = s.id
+    WHERE o.number = $1
+), requeued AS (
+    UPDATE orders AS o
+    SET next_attempt_at = NOW(), attempts = 0, last_error = NULL, dead_lettered_at = NULL
+    FROM current
+    WHERE o.id = current.id AND current.status IN ('NEW', 'PROCESSING')
+    RETURNING o.id
+)
+SELECT current.status, EXISTS (SELECT 1 FROM requeued) FROM current;`
@@ -0,0 +1,146 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/jackc/pgx/v5"
+)
+
+// QueueRepository keeps the order processing queue in the orders table: an
+// order is queued while its next_attempt_at is set. Orders are enqueued by
+// the insert statement of the order repository, so an order can never be
+// saved without being queued.
+type QueueRepository struct {
+	db storage.DB
+}
+
+const repoName = "postgres.QueueRepo."
+
+func NewQueueRepository(db storage.DB) *QueueRepository {
+	return &QueueRepository{
+		db: db,
+	}
+}
+
+// Pull leases up to limit due orders to owner for the lease period. While the
+// lease is held no other worker can pull the order. An order that is neither
+// rescheduled nor completed before the lease expires becomes due again.
+func (r *QueueRepository) Pull(ctx context.Context, owner string, limit int, lease time.Duration) ([]entity.Order, error) {
+	const op = repoName + "Pull"
+	rows, err := r.db.Query(ctx, pullStmt, owner, limit, lease.Seconds())
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	defer rows.Close()
+
+	orders, err := pgx.CollectRows(rows, pgx.RowToStructByName[entity.Order])
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+
+	return orders, nil
+}
+
+// Reschedule releases the lease held by owner and schedules the next poll of
+// the order after delay, resetting its failed attempts. A poll postponed
+// beyond that during the lease is kept. It is a no-op if the lease has been
+// lost.
+func (r *QueueRepository) Reschedule(ctx context.Context, owner string, number string, delay time.Duration) error {
+	_, err := r.db.Exec(ctx, rescheduleStmt, owner, number, delay.Seconds())
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"Reschedule", err)
+	}
+	return nil
+}
+
+// Release releases the lease held by owner on an order which has not been
+// polled and schedules the next poll after delay. Unlike Reschedule, it keeps
+// the failed attempts of the order.
+func (r *QueueRepository) Release(ctx context.Context, owner string, number string, delay time.Duration) error {
+	_, err := r.db.Exec(ctx, releaseStmt, owner, number, delay.Seconds())
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"Release", err)
+	}
+	return nil
+}
+
+// Retry records a failed attempt, releases the lease held by owner and
+// schedules the next attempt after delay.
+func (r *QueueRepository) Retry(ctx context.Context, owner string, number string, reason string, delay time.Duration) error {
+	_, err := r.db.Exec(ctx, retryStmt, owner, number, reason, delay.Seconds())
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"Retry", err)
+	}
+	return nil
+}
+
+// Postpone schedules the next poll of the order after delay, e.g. because
+// its status has just been reported by a callback. Failed attempts are kept.
+func (r *QueueRepository) Postpone(ctx context.Context, number string, delay time.Duration) error {
+	_, err := r.db.Exec(ctx, postponeStmt, number, delay.Seconds())
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"Postpone", err)
+	}
+	return nil
+}
+
+// DeadLetter records the last failed attempt and removes the order from the
+// queue until it is requeued manually.
+func (r *QueueRepository) DeadLetter(ctx context.Context, owner string, number string, reason string) error {
+	_, err := r.db.Exec(ctx, deadLetterStmt, owner, number, reason)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"DeadLetter", err)
+	}
+	return nil
+}
+
+func (r *QueueRepository) DeadLetters(ctx context.Context) ([]entity.DeadLetter, error) {
+	const op = repoName + "DeadLetters"
+	rows, err := r.db.Query(ctx, selectDeadLettersStmt)
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	defer rows.Close()
+
+	deadLetters, err := pgx.CollectRows(rows, pgx.RowToStructByName[entity.DeadLetter])
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+
+	return deadLetters, nil
+}
+
+// Requeue makes a pending order due right away, e.g. a dead-lettered one.
+// Orders in a final status can not be requeued.
+func (r *QueueRepository) Requeue(ctx context.Context, number string) error {
+	const op = repoName + "Requeue"
+	var (
+		status   entity.OrderStatus
+		requeued bool
+	)
+	rows, err := r.db.Query(ctx, requeueStmt, number)
+	if err != nil {
+		return storage.NewRepositoryError(op, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return storage.NewRepositoryError(op, err)
+		}
+		return storage.NewRepositoryError(op, common.ErrNonExistentOrder)
+	}
+	if err := rows.Scan(&status, &requeued); err != nil {
+		return storage.NewRepositoryError(op, err)
+	}
+	if !requeued {
+		return storage.NewRepositoryError(op, fmt.Errorf("%w: order is %s", common.ErrIllegalStatusTransition, status))
+	}
+	return nil
+}
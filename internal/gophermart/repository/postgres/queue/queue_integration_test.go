@@ -0,0 +1,138 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	orderrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/order"
+	"github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/pgtest"
+	userrepo "github.com/MxTrap/gophermart/internal/gophermart/repository/postgres/user"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+// leasedOrder saves a due order and leases it to owner.
+func leasedOrder(t *testing.T, pool *pgxpool.Pool, repo *QueueRepository, owner string) string {
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+	userID, err := userrepo.NewUserRepository(pool).SaveUser(ctx, entity.User{
+		Login:    fmt.Sprintf("queue-%d", suffix),
+		Password: "hash",
+		Role:     entity.RoleUser,
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	number := fmt.Sprint(suffix)
+	err = orderrepo.NewOrderRepository(pool, 0).Save(ctx, entity.Order{UserID: userID, Number: number, Status: entity.OrderNew})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "DELETE FROM orders WHERE user_id = $1", userID)
+		_, _ = pool.Exec(ctx, "DELETE FROM users WHERE id = $1", userID)
+	})
+
+	// Other due orders of the shared database may be leased as well, so they
+	// are released before the test goes on.
+	orders, err := repo.Pull(ctx, owner, 1000, time.Minute)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	leased := false
+	for _, order := range orders {
+		if order.Number == number {
+			leased = true
+			continue
+		}
+		_, _ = pool.Exec(ctx, "UPDATE orders SET locked_by = NULL, locked_until = NULL WHERE number = $1", order.Number)
+	}
+	if !assert.True(t, leased, "order should be leased") {
+		t.FailNow()
+	}
+
+	return number
+}
+
+// pollIn returns how long until the next poll of the order.
+func pollIn(t *testing.T, pool *pgxpool.Pool, number string) time.Duration {
+	var seconds float64
+	err := pool.QueryRow(context.Background(),
+		"SELECT EXTRACT(EPOCH FROM next_attempt_at - NOW()) FROM orders WHERE number = $1", number).Scan(&seconds)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func TestQueueRepository_Reschedule_keepsPostponement(t *testing.T) {
+	ctx := context.Background()
+	pool := pgtest.NewPool(t)
+	repo := NewQueueRepository(pool)
+	const owner = "worker"
+
+	t.Run("postponed during the lease", func(t *testing.T) {
+		number := leasedOrder(t, pool, repo, owner)
+
+		// Колбэк пришёл, пока заказ был в работе у воркера.
+		assert.NoError(t, repo.Postpone(ctx, number, time.Hour))
+		assert.NoError(t, repo.Reschedule(ctx, owner, number, 5*time.Second))
+
+		assert.Greater(t, pollIn(t, pool, number), 50*time.Minute)
+	})
+
+	t.Run("not postponed", func(t *testing.T) {
+		number := leasedOrder(t, pool, repo, owner)
+
+		assert.NoError(t, repo.Reschedule(ctx, owner, number, 5*time.Second))
+
+		assert.Less(t, pollIn(t, pool, number), time.Minute)
+	})
+}
+
+func TestQueueRepository_Release_keepsAttempts(t *testing.T) {
+	ctx := context.Background()
+	pool := pgtest.NewPool(t)
+	repo := NewQueueRepository(pool)
+	const owner = "worker"
+
+	number := leasedOrder(t, pool, repo, owner)
+	_, err := pool.Exec(ctx, "UPDATE orders SET attempts = 3, last_error = 'timeout' WHERE number = $1", number)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Release(ctx, owner, number, 5*time.Second))
+
+	var attempts int
+	var lastError, lockedBy *string
+	err = pool.QueryRow(ctx, "SELECT attempts, last_error, locked_by FROM orders WHERE number = $1", number).
+		Scan(&attempts, &lastError, &lockedBy)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	if assert.NotNil(t, lastError) {
+		assert.Equal(t, "timeout", *lastError)
+	}
+	assert.Nil(t, lockedBy)
+	assert.Less(t, pollIn(t, pool, number), time.Minute)
+}
+
+func TestQueueRepository_Postpone_keepsAttempts(t *testing.T) {
+	ctx := context.Background()
+	pool := pgtest.NewPool(t)
+	repo := NewQueueRepository(pool)
+
+	// Колбэки с промежуточным статусом не должны спасать заказ от dead letters.
+	number := leasedOrder(t, pool, repo, "worker")
+	_, err := pool.Exec(ctx, "UPDATE orders SET attempts = 3 WHERE number = $1", number)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Postpone(ctx, number, time.Hour))
+
+	var attempts int
+	err = pool.QueryRow(ctx, "SELECT attempts FROM orders WHERE number = $1", number).Scan(&attempts)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Greater(t, pollIn(t, pool, number), 50*time.Minute)
+}
@@ -0,0 +1,32 @@
+package refreshtoken
+
+const insertStmt = `
+INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4);`
+
+const findByHashStmt = `
+SELECT id, user_id, family_id, token_hash, expires_at, rotated_at, revoked_at
+FROM refresh_tokens
+WHERE token_hash = $1;`
+
+// rotateStmt exchanges an unused token for the next one of its family in a
+// single statement, so that a token can not be rotated twice concurrently.
+const rotateStmt = `
+WITH rotated AS (
+    UPDATE refresh_tokens
+    SET rotated_at = NOW()
+    WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL
+    RETURNING user_id, family_id
+)
+INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+SELECT user_id, family_id, $2, $3 FROM rotated;`
+
+const revokeFamilyStmt = `
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL;`
+
+const revokeUserStmt = `
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;`
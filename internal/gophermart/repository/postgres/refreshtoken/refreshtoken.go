@@ -0,0 +1,79 @@
+package refreshtoken
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RefreshTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+const repoName = "postgres.RefreshTokenRepo."
+
+func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db: db,
+	}
+}
+
+// Save stores the first token of a new family.
+func (r *RefreshTokenRepository) Save(ctx context.Context, token entity.RefreshToken) error {
+	_, err := r.db.Exec(ctx, insertStmt, token.UserID, token.FamilyID, token.Hash, token.ExpiresAt)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"Save", err)
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	const op = repoName + "FindByHash"
+	rows, err := r.db.Query(ctx, findByHashStmt, hash)
+	if err != nil {
+		return entity.RefreshToken{}, storage.NewRepositoryError(op, err)
+	}
+	defer rows.Close()
+
+	token, err := pgx.CollectOneRow(rows, pgx.RowToStructByPos[entity.RefreshToken])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return token, storage.NewRepositoryError(op, common.ErrInvalidToken)
+		}
+		return token, storage.NewRepositoryError(op, err)
+	}
+	return token, nil
+}
+
+// Rotate marks the token with the given id as used and stores next in its
+// family. It returns false if the token has been used in the meantime.
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, id int64, next entity.RefreshToken) (bool, error) {
+	tag, err := r.db.Exec(ctx, rotateStmt, id, next.Hash, next.ExpiresAt)
+	if err != nil {
+		return false, storage.NewRepositoryError(repoName+"Rotate", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.Exec(ctx, revokeFamilyStmt, familyID)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"RevokeFamily", err)
+	}
+	return nil
+}
+
+// RevokeUser revokes all refresh tokens of the user.
+func (r *RefreshTokenRepository) RevokeUser(ctx context.Context, userID int64) error {
+	_, err := r.db.Exec(ctx, revokeUserStmt, userID)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"RevokeUser", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package revocation
+
+const insertTokenStmt = `
+INSERT INTO revoked_tokens (jti, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO NOTHING;`
+
+const upsertUserStmt = `
+INSERT INTO user_token_revocations (user_id, revoked_before, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before),
+    expires_at = GREATEST(user_token_revocations.expires_at, EXCLUDED.expires_at);`
+
+const selectTokensStmt = `
+SELECT jti, user_id, expires_at
+FROM revoked_tokens
+WHERE expires_at > NOW();`
+
+const selectUsersStmt = `
+SELECT user_id, revoked_before, expires_at
+FROM user_token_revocations
+WHERE expires_at > NOW();`
+
+const deleteExpiredTokensStmt = "DELETE FROM revoked_tokens WHERE expires_at <= NOW();"
+
+const deleteExpiredUsersStmt = "DELETE FROM user_token_revocations WHERE expires_at <= NOW();"
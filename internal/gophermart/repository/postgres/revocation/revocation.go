@@ -0,0 +1,83 @@
+package revocation
+
+import (
+	"context"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RevocationRepository stores revoked access tokens until they expire.
+type RevocationRepository struct {
+	db *pgxpool.Pool
+}
+
+const repoName = "postgres.RevocationRepo."
+
+func NewRevocationRepository(db *pgxpool.Pool) *RevocationRepository {
+	return &RevocationRepository{
+		db: db,
+	}
+}
+
+func (r *RevocationRepository) RevokeToken(ctx context.Context, token entity.RevokedToken) error {
+	_, err := r.db.Exec(ctx, insertTokenStmt, token.ID, token.UserID, token.ExpiresAt)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"RevokeToken", err)
+	}
+	return nil
+}
+
+// RevokeUser records a user-wide revocation. A later revocation of the same
+// user extends the earlier one.
+func (r *RevocationRepository) RevokeUser(ctx context.Context, revocation entity.UserRevocation) error {
+	_, err := r.db.Exec(ctx, upsertUserStmt, revocation.UserID, revocation.RevokedBefore, revocation.ExpiresAt)
+	if err != nil {
+		return storage.NewRepositoryError(repoName+"RevokeUser", err)
+	}
+	return nil
+}
+
+// Active returns the revocations which have not expired yet.
+func (r *RevocationRepository) Active(ctx context.Context) ([]entity.RevokedToken, []entity.UserRevocation, error) {
+	const op = repoName + "Active"
+
+	rows, err := r.db.Query(ctx, selectTokensStmt)
+	if err != nil {
+		return nil, nil, storage.NewRepositoryError(op, err)
+	}
+	tokens, err := pgx.CollectRows(rows, pgx.RowToStructByPos[entity.RevokedToken])
+	if err != nil {
+		return nil, nil, storage.NewRepositoryError(op, err)
+	}
+
+	rows, err = r.db.Query(ctx, selectUsersStmt)
+	if err != nil {
+		return nil, nil, storage.NewRepositoryError(op, err)
+	}
+	users, err := pgx.CollectRows(rows, pgx.RowToStructByPos[entity.UserRevocation])
+	if err != nil {
+		return nil, nil, storage.NewRepositoryError(op, err)
+	}
+
+	return tokens, users, nil
+}
+
+// DeleteExpired removes the revocations of tokens which have expired anyway
+// and returns the number of removed entries.
+func (r *RevocationRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	const op = repoName + "DeleteExpired"
+
+	var deleted int64
+	for _, stmt := range []string{deleteExpiredTokensStmt, deleteExpiredUsersStmt} {
+		tag, err := r.db.Exec(ctx, stmt)
+		if err != nil {
+			return deleted, storage.NewRepositoryError(op, err)
+		}
+		deleted += tag.RowsAffected()
+	}
+	return deleted, nil
+}
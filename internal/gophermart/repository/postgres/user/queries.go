@@ -1,7 +1,20 @@
 package posgress
 
-const insertStmt = "INSERT INTO users (login, password) VALUES ($1, $2) RETURNING id;"
+const insertStmt = "INSERT INTO users (login, password, role) VALUES ($1, $2, $3) RETURNING id;"
 
-const findByIDStmt = "SELECT * FROM users WHERE id = $1;"
+const selectColumns = "SELECT id, login, password, balance, withdrawn, role, external_id FROM users"
 
-const findByUsernameStmt = "SELECT * FROM users WHERE login = $1;"
+const findByIDStmt = selectColumns + " WHERE id = $1;"
+
+const findByUsernameStmt = selectColumns + " WHERE login = $1;"
+
+const findByExternalIDStmt = selectColumns + " WHERE external_id = $1;"
+
+const updatePasswordStmt = "UPDATE users SET password = $2 WHERE id = $1;"
+
+const updateRoleStmt = "UPDATE users SET role = $2 WHERE id = $1;"
+
+const updateExternalIDStmt = "UPDATE users SET external_id = $2 WHERE id = $1;"
+
+// searchStmt matches logins by a LIKE pattern, case-insensitively.
+const searchStmt = selectColumns + " WHERE login ILIKE $1 ORDER BY login LIMIT $2;"
@@ -3,15 +3,22 @@ package posgress
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	storage "github.com/MxTrap/gophermart/internal/gophermart/repository"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	uniqueViolation      = "23505"
+	externalIDConstraint = "uq_users_external_id"
+)
+
 type UserRepository struct {
 	db *pgxpool.Pool
 }
@@ -29,7 +36,7 @@ func (s UserRepository) SaveUser(
 	user entity.User,
 ) (int64, error) {
 	var id int64
-	err := s.db.QueryRow(ctx, insertStmt, user.Login, user.Password).Scan(&id)
+	err := s.db.QueryRow(ctx, insertStmt, user.Login, user.Password, user.Role).Scan(&id)
 
 	if err != nil {
 		return 0, storage.NewRepositoryError(repoName+".SaveUser", err)
@@ -73,3 +80,76 @@ func (s UserRepository) FindUserByUsername(ctx context.Context, username string)
 
 	return s.collectUser(row)
 }
+
+func (s UserRepository) FindUserByExternalID(ctx context.Context, externalID string) (entity.User, error) {
+	var user entity.User
+
+	row, err := s.db.Query(ctx, findByExternalIDStmt, externalID)
+	if err != nil {
+		return user, storage.NewRepositoryError(repoName+"FindUserByExternalID", err)
+	}
+	defer row.Close()
+
+	return s.collectUser(row)
+}
+
+func (s UserRepository) UpdatePassword(ctx context.Context, userID int64, password string) error {
+	const op = repoName + "UpdatePassword"
+	tag, err := s.db.Exec(ctx, updatePasswordStmt, userID, password)
+	if err != nil {
+		return storage.NewRepositoryError(op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.NewRepositoryError(op, common.ErrUserNotFound)
+	}
+	return nil
+}
+
+func (s UserRepository) UpdateRole(ctx context.Context, userID int64, role entity.Role) error {
+	const op = repoName + "UpdateRole"
+	tag, err := s.db.Exec(ctx, updateRoleStmt, userID, role)
+	if err != nil {
+		return storage.NewRepositoryError(op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.NewRepositoryError(op, common.ErrUserNotFound)
+	}
+	return nil
+}
+
+// UpdateExternalID sets the id of the user in partner systems, nil removes
+// it.
+func (s UserRepository) UpdateExternalID(ctx context.Context, userID int64, externalID *string) error {
+	const op = repoName + "UpdateExternalID"
+	tag, err := s.db.Exec(ctx, updateExternalIDStmt, userID, externalID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == externalIDConstraint {
+			return storage.NewRepositoryError(op, common.ErrExternalIDTaken)
+		}
+		return storage.NewRepositoryError(op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.NewRepositoryError(op, common.ErrUserNotFound)
+	}
+	return nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchByLogin returns up to limit users whose login starts with prefix,
+// ignoring case.
+func (s UserRepository) SearchByLogin(ctx context.Context, prefix string, limit int) ([]entity.User, error) {
+	const op = repoName + "SearchByLogin"
+	rows, err := s.db.Query(ctx, searchStmt, likeEscaper.Replace(prefix)+"%", limit)
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	defer rows.Close()
+
+	users, err := pgx.CollectRows(rows, pgx.RowToStructByPos[entity.User])
+	if err != nil {
+		return nil, storage.NewRepositoryError(op, err)
+	}
+	return users, nil
+}
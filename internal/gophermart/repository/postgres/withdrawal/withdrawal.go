@@ -37,10 +37,11 @@ func (r *WithdrawnRepo) GetAll(ctx context.Context, userID int64) ([]entity.With
 	return withdrawals, nil
 }
 
-func (*WithdrawnRepo) Save(ctx context.Context, tx pgx.Tx, userID int64, withdrawn entity.Withdrawal) error {
-	_, err := tx.Exec(ctx, insertStmt, userID, withdrawn.Order, withdrawn.Sum, withdrawn.ProcessedAt)
+func (*WithdrawnRepo) Save(ctx context.Context, tx pgx.Tx, userID int64, withdrawn entity.Withdrawal) (int64, error) {
+	var id int64
+	err := tx.QueryRow(ctx, insertStmt, userID, withdrawn.Order, withdrawn.Sum, withdrawn.ProcessedAt).Scan(&id)
 	if err != nil {
-		return storage.NewRepositoryError(repoName+"Save", err)
+		return 0, storage.NewRepositoryError(repoName+"Save", err)
 	}
-	return nil
+	return id, nil
 }
@@ -1,9 +1,13 @@
 package accrual
 
 import (
+	"context"
 	"errors"
-	"fmt"
+	"net"
 	"net/http"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
@@ -11,30 +15,104 @@ import (
 	"resty.dev/v3"
 )
 
+const (
+	// maxRetries is the number of extra attempts made after a network error
+	// or a 5xx response. 429 is never retried here, it is left to the rate
+	// limiter of the order worker.
+	maxRetries   = 2
+	retryWait    = 100 * time.Millisecond
+	retryMaxWait = time.Second
+	// maxIdleConns matches the number of order worker goroutines.
+	maxIdleConns = 5
+)
+
 type AccrualService struct {
-	log *logger.Logger
-	url string
+	log     *logger.Logger
+	client  *resty.Client
+	timeout time.Duration
+	breaker *CircuitBreaker
 }
 
-func NewAccrualService(log *logger.Logger, url string) *AccrualService {
+// NewAccrualService creates the accrual client. requestTimeout bounds every
+// single HTTP attempt while timeout bounds the whole call including retries.
+// Calls are rejected with common.ErrCircuitOpen while breaker is open.
+func NewAccrualService(
+	log *logger.Logger,
+	url string,
+	requestTimeout, timeout time.Duration,
+	breaker *CircuitBreaker,
+) *AccrualService {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   requestTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConns,
+		IdleConnTimeout:       90 * time.Second,
+		ResponseHeaderTimeout: requestTimeout,
+	}
+
+	client := resty.NewWithClient(&http.Client{
+		Transport: transport,
+		Timeout:   requestTimeout,
+	}).
+		SetBaseURL(url).
+		SetRetryCount(maxRetries).
+		SetRetryWaitTime(retryWait).
+		SetRetryMaxWaitTime(retryMaxWait).
+		SetRetryDefaultConditions(false).
+		AddRetryConditions(isRetryable)
+
 	return &AccrualService{
-		log: log,
-		url: url,
+		log:     log,
+		client:  client,
+		timeout: timeout,
+		breaker: breaker,
 	}
 }
 
+// Close releases idle connections of the client.
+func (s *AccrualService) Close() error {
+	return s.client.Close()
+}
+
+// isRetryable retries network errors, including a timed out attempt, and 5xx
+// responses unless the caller's context is already done.
+func isRetryable(res *resty.Response, err error) bool {
+	if res.Request.Context().Err() != nil {
+		return false
+	}
+	return err != nil || res.StatusCode() >= http.StatusInternalServerError
+}
+
 type accrualDto struct {
-	Order   string   `json:"order"`
-	Status  string   `json:"status"`
-	Accrual *float32 `json:"accrual,omitempty"`
+	Order   string        `json:"order"`
+	Status  string        `json:"status"`
+	Accrual *entity.Money `json:"accrual,omitempty"`
 }
 
-func (s *AccrualService) GetOrderAccrual(number string) (entity.Order, error) {
+func (s *AccrualService) GetOrderAccrual(ctx context.Context, number string) (entity.Order, error) {
+	if err := s.breaker.Allow(); err != nil {
+		return entity.Order{}, err
+	}
+
+	reqCtx := ctx
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
 	var order accrualDto
-	res, err := resty.New().
+	res, err := s.client.
 		R().
+		SetContext(reqCtx).
 		SetResult(&order).
-		Get(fmt.Sprintf("%s/api/orders/%s", s.url, number))
+		SetPathParam("number", number).
+		Get("/api/orders/{number}")
+	s.record(ctx, res, err)
 
 	if err != nil {
 		return entity.Order{}, err
@@ -44,20 +122,67 @@ func (s *AccrualService) GetOrderAccrual(number string) (entity.Order, error) {
 		return entity.Order{}, common.ErrNonExistentOrder
 	}
 
+	if res.StatusCode() == http.StatusTooManyRequests {
+		return entity.Order{}, &common.TooManyRequestsError{
+			RetryAfter: parseRetryAfter(res.Header().Get("Retry-After")),
+			Limit:      parseRateLimit(res.String()),
+		}
+	}
+
 	if res.StatusCode() != http.StatusOK {
 		return entity.Order{}, errors.New(res.Status())
 	}
 
-	return s.mapDtoToOrder(order), nil
+	return s.mapDtoToOrder(order)
+}
+
+// record reports the outcome of a call to the breaker. Network errors,
+// timeouts and 5xx responses are failures, any other response means the
+// accrual system is up. A call cancelled by the caller proves nothing.
+func (s *AccrualService) record(ctx context.Context, res *resty.Response, err error) {
+	switch {
+	case ctx.Err() != nil:
+		s.breaker.Cancel()
+	case err != nil || res.StatusCode() >= http.StatusInternalServerError:
+		s.breaker.Failure()
+	default:
+		s.breaker.Success()
+	}
 }
 
-func (*AccrualService) mapDtoToOrder(dto accrualDto) entity.Order {
-	status := dto.Status
-	if status == "REGISTERED" {
-		status = entity.OrderNew
+func (*AccrualService) mapDtoToOrder(dto accrualDto) (entity.Order, error) {
+	status, err := entity.ParseAccrualStatus(dto.Status)
+	if err != nil {
+		return entity.Order{}, err
 	}
 	return entity.Order{
 		Status:  status,
 		Accrual: dto.Accrual,
+	}, nil
+}
+
+var rateLimitRe = regexp.MustCompile(`No more than (\d+) requests per minute allowed`)
+
+// parseRetryAfter accepts both forms of the Retry-After header: delay in
+// seconds and HTTP-date. Zero is returned if the header is absent or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+func parseRateLimit(body string) int {
+	match := rateLimitRe.FindStringSubmatch(body)
+	if match == nil {
+		return 0
 	}
+	limit, _ := strconv.Atoi(match[1])
+	return limit
 }
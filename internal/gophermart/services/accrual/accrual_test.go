@@ -1,24 +1,33 @@
 package accrual
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/MxTrap/gophermart/internal/accrualsim"
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/MxTrap/gophermart/logger"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
+func newTestService(log *logger.Logger, url string, requestTimeout, timeout time.Duration) *AccrualService {
+	return NewAccrualService(log, url, requestTimeout, timeout, NewCircuitBreaker(log, 5, time.Minute))
+}
+
 func TestAccrualService_GetOrderAccrual(t *testing.T) {
 	log := logger.NewLogger()
 	orderNumber := "12345"
 	baseURL := "/api/orders/"
 
 	t.Run("successful response", func(t *testing.T) {
-		accrualValue := float32(100.50)
+		accrualValue := entity.Money(10050)
 		dto := accrualDto{
 			Order:   orderNumber,
 			Status:  "PROCESSED",
@@ -32,24 +41,38 @@ func TestAccrualService_GetOrderAccrual(t *testing.T) {
 		}))
 		defer server.Close()
 
-		svc := NewAccrualService(log, server.URL)
-		order, err := svc.GetOrderAccrual(orderNumber)
+		svc := newTestService(log, server.URL, time.Second, 5*time.Second)
+		order, err := svc.GetOrderAccrual(context.Background(), orderNumber)
 		fmt.Println(order, err)
 		assert.NoError(t, err)
 		assert.Equal(t, entity.Order{
-			Status:  "PROCESSED",
+			Status:  entity.OrderProcessed,
 			Accrual: &accrualValue,
 		}, order)
 	})
 
+	t.Run("unknown status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(accrualDto{Order: orderNumber, Status: "UNKNOWN"})
+		}))
+		defer server.Close()
+
+		svc := newTestService(log, server.URL, time.Second, 5*time.Second)
+		order, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+		assert.ErrorIs(t, err, common.ErrUnknownOrderStatus)
+		assert.Equal(t, entity.Order{}, order)
+	})
+
 	t.Run("no content response", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNoContent)
 		}))
 		defer server.Close()
 
-		svc := NewAccrualService(log, server.URL)
-		order, err := svc.GetOrderAccrual(orderNumber)
+		svc := newTestService(log, server.URL, time.Second, 5*time.Second)
+		order, err := svc.GetOrderAccrual(context.Background(), orderNumber)
 		assert.ErrorIs(t, err, common.ErrNonExistentOrder)
 		assert.Equal(t, entity.Order{}, order)
 	})
@@ -60,28 +83,265 @@ func TestAccrualService_GetOrderAccrual(t *testing.T) {
 		}))
 		defer server.Close()
 
-		svc := NewAccrualService(log, server.URL)
-		order, err := svc.GetOrderAccrual(orderNumber)
+		svc := newTestService(log, server.URL, time.Second, 5*time.Second)
+		order, err := svc.GetOrderAccrual(context.Background(), orderNumber)
 		assert.Error(t, err)
 		assert.Equal(t, entity.Order{}, order)
 	})
 
+	t.Run("too many requests", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("No more than 10 requests per minute allowed"))
+		}))
+		defer server.Close()
+
+		svc := newTestService(log, server.URL, time.Second, 5*time.Second)
+		order, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+		assert.ErrorIs(t, err, common.ErrTooManyRequests)
+		var rateErr *common.TooManyRequestsError
+		assert.ErrorAs(t, err, &rateErr)
+		assert.Equal(t, 60*time.Second, rateErr.RetryAfter)
+		assert.Equal(t, 10, rateErr.Limit)
+		assert.Equal(t, entity.Order{}, order)
+	})
+
 	t.Run("network error", func(t *testing.T) {
-		svc := NewAccrualService(log, "http://invalid-url")
-		order, err := svc.GetOrderAccrual(orderNumber)
+		svc := newTestService(log, "http://invalid-url", time.Second, 5*time.Second)
+		order, err := svc.GetOrderAccrual(context.Background(), orderNumber)
 		assert.Error(t, err)
 		assert.Equal(t, entity.Order{}, order)
 	})
 }
 
+func TestAccrualService_GetOrderAccrual_Timeouts(t *testing.T) {
+	log := logger.NewLogger()
+	orderNumber := "12345"
+
+	// blockingServer держит запрос, пока клиент не отменит его.
+	blockingServer := func(attempts *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		}))
+	}
+
+	t.Run("cancelled context", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := blockingServer(&attempts)
+		defer server.Close()
+
+		svc := newTestService(log, server.URL, 5*time.Second, 10*time.Second)
+		defer svc.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		order, err := svc.GetOrderAccrual(ctx, orderNumber)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(1), attempts.Load())
+		assert.Equal(t, entity.Order{}, order)
+	})
+
+	t.Run("request timeout is retried", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := blockingServer(&attempts)
+		defer server.Close()
+
+		svc := newTestService(log, server.URL, 100*time.Millisecond, 10*time.Second)
+		defer svc.Close()
+
+		_, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+		assert.Error(t, err)
+		assert.Equal(t, int32(maxRetries+1), attempts.Load())
+	})
+
+	t.Run("overall timeout", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := blockingServer(&attempts)
+		defer server.Close()
+
+		svc := newTestService(log, server.URL, 5*time.Second, 200*time.Millisecond)
+		defer svc.Close()
+
+		start := time.Now()
+		_, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("server error is retried", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(accrualDto{Order: orderNumber, Status: "PROCESSING"})
+		}))
+		defer server.Close()
+
+		svc := newTestService(log, server.URL, time.Second, 5*time.Second)
+		defer svc.Close()
+
+		order, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.OrderProcessing, order.Status)
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("too many requests is not retried", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		svc := newTestService(log, server.URL, time.Second, 5*time.Second)
+		defer svc.Close()
+
+		_, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+		assert.ErrorIs(t, err, common.ErrTooManyRequests)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("connections are reused", func(t *testing.T) {
+		var mu sync.Mutex
+		remotes := map[string]struct{}{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			remotes[r.RemoteAddr] = struct{}{}
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(accrualDto{Order: orderNumber, Status: "REGISTERED"})
+		}))
+		defer server.Close()
+
+		svc := newTestService(log, server.URL, time.Second, 5*time.Second)
+		defer svc.Close()
+
+		for i := 0; i < 3; i++ {
+			_, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+			assert.NoError(t, err)
+		}
+		assert.Len(t, remotes, 1)
+	})
+}
+
+func TestAccrualService_GetOrderAccrual_Breaker(t *testing.T) {
+	log := logger.NewLogger()
+	orderNumber := "12345"
+
+	var attempts atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(accrualDto{Order: orderNumber, Status: "PROCESSING"})
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(log, 2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	svc := NewAccrualService(log, server.URL, time.Second, 5*time.Second, breaker)
+	defer svc.Close()
+
+	// Две неудачные попытки (с ретраями) открывают цепь.
+	for i := 0; i < 2; i++ {
+		_, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, BreakerOpen, breaker.State())
+	assert.Equal(t, int32(2*(maxRetries+1)), attempts.Load())
+
+	_, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+	assert.ErrorIs(t, err, common.ErrCircuitOpen)
+	assert.Equal(t, int32(2*(maxRetries+1)), attempts.Load())
+
+	// После интервала пробный запрос закрывает цепь.
+	now = now.Add(time.Minute)
+	status.Store(http.StatusOK)
+	order, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderProcessing, order.Status)
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	t.Run("client errors do not open the circuit", func(t *testing.T) {
+		status.Store(http.StatusNoContent)
+		for i := 0; i < 3; i++ {
+			_, err := svc.GetOrderAccrual(context.Background(), orderNumber)
+			assert.ErrorIs(t, err, common.ErrNonExistentOrder)
+		}
+		assert.Equal(t, BreakerClosed, breaker.State())
+	})
+}
+
+func TestAccrualService_GetOrderAccrual_Simulator(t *testing.T) {
+	log := logger.NewLogger()
+	orderNumber := "12345678903"
+
+	sim := accrualsim.New()
+	server := httptest.NewServer(sim.Handler())
+	defer server.Close()
+
+	assert.NoError(t, sim.AddRule(accrualsim.Rule{Match: "Bork", Reward: 1000, RewardType: accrualsim.RewardPercent}))
+	assert.NoError(t, sim.RegisterOrder(orderNumber, []accrualsim.Good{{Description: "Чайник Bork", Price: 700000}}))
+
+	svc := newTestService(log, server.URL, 100*time.Millisecond, time.Second)
+	defer svc.Close()
+	ctx := context.Background()
+
+	order, err := svc.GetOrderAccrual(ctx, orderNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderNew, order.Status)
+
+	assert.NoError(t, sim.InjectFault(accrualsim.Fault{Status: http.StatusTooManyRequests, RetryAfter: time.Minute, Limit: 10, Count: 1}))
+	_, err = svc.GetOrderAccrual(ctx, orderNumber)
+	var rateErr *common.TooManyRequestsError
+	assert.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, time.Minute, rateErr.RetryAfter)
+	assert.Equal(t, 10, rateErr.Limit)
+
+	// Первая попытка зависает, ретрай проходит.
+	assert.NoError(t, sim.InjectFault(accrualsim.Fault{Delay: time.Second, Count: 1}))
+	order, err = svc.GetOrderAccrual(ctx, orderNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderProcessing, order.Status)
+
+	order, err = svc.GetOrderAccrual(ctx, orderNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderProcessed, order.Status)
+	accrual := entity.Money(70000)
+	assert.Equal(t, &accrual, order.Accrual)
+
+	_, err = svc.GetOrderAccrual(ctx, "79927398713")
+	assert.ErrorIs(t, err, common.ErrNonExistentOrder)
+}
+
 func TestAccrualService_mapDtoToOrder(t *testing.T) {
 	svc := &AccrualService{}
-	accrualValue := float32(50.25)
+	accrualValue := entity.Money(5025)
 
 	tests := []struct {
-		name     string
-		dto      accrualDto
-		expected entity.Order
+		name        string
+		dto         accrualDto
+		expected    entity.Order
+		expectedErr error
 	}{
 		{
 			name: "registered status",
@@ -103,10 +363,20 @@ func TestAccrualService_mapDtoToOrder(t *testing.T) {
 				Accrual: &accrualValue,
 			},
 			expected: entity.Order{
-				Status:  "PROCESSED",
+				Status:  entity.OrderProcessed,
 				Accrual: &accrualValue,
 			},
 		},
+		{
+			name: "processing status",
+			dto: accrualDto{
+				Order:  "123",
+				Status: "PROCESSING",
+			},
+			expected: entity.Order{
+				Status: entity.OrderProcessing,
+			},
+		},
 		{
 			name: "no accrual",
 			dto: accrualDto{
@@ -114,16 +384,78 @@ func TestAccrualService_mapDtoToOrder(t *testing.T) {
 				Status: "INVALID",
 			},
 			expected: entity.Order{
-				Status:  "INVALID",
+				Status:  entity.OrderInvalid,
 				Accrual: nil,
 			},
 		},
+		{
+			name: "unknown status",
+			dto: accrualDto{
+				Order:   "123",
+				Status:  "CANCELLED",
+				Accrual: &accrualValue,
+			},
+			expected:    entity.Order{},
+			expectedErr: common.ErrUnknownOrderStatus,
+		},
+		{
+			name: "order status is not an accrual status",
+			dto: accrualDto{
+				Order:  "123",
+				Status: "NEW",
+			},
+			expected:    entity.Order{},
+			expectedErr: common.ErrUnknownOrderStatus,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := svc.mapDtoToOrder(tt.dto)
+			result, err := svc.mapDtoToOrder(tt.dto)
+			assert.ErrorIs(t, err, tt.expectedErr)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "120", 2 * time.Minute},
+		{"invalid", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseRetryAfter(tt.value))
+		})
+	}
+
+	t.Run("http date", func(t *testing.T) {
+		date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+		got := parseRetryAfter(date)
+		assert.InDelta(t, float64(time.Minute), float64(got), float64(2*time.Second))
+	})
+}
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"contract body", "No more than 42 requests per minute allowed", 42},
+		{"unknown body", "slow down", 0},
+		{"empty body", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseRateLimit(tt.body))
+		})
+	}
+}
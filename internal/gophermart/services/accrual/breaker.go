@@ -0,0 +1,191 @@
+package accrual
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects requests until the probe interval has passed.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe through: its outcome closes or
+	// reopens the circuit.
+	BreakerHalfOpen
+)
+
+var breakerStateNames = map[BreakerState]string{
+	BreakerClosed:   "closed",
+	BreakerOpen:     "open",
+	BreakerHalfOpen: "half-open",
+}
+
+func (s BreakerState) String() string {
+	return breakerStateNames[s]
+}
+
+func (s BreakerState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// BreakerSnapshot describes the circuit for health reports.
+type BreakerSnapshot struct {
+	State    BreakerState `json:"state"`
+	Failures int          `json:"consecutive_failures"`
+	// ProbeAt is set while the circuit is open.
+	ProbeAt *time.Time `json:"probe_at,omitempty"`
+}
+
+// CircuitBreaker stops calls to the accrual system after failureThreshold
+// consecutive failures. While open, callers are rejected with
+// common.ErrCircuitOpen; after probeInterval one probe is let through.
+type CircuitBreaker struct {
+	log              *logger.Logger
+	failureThreshold int
+	probeInterval    time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+func NewCircuitBreaker(log *logger.Logger, failureThreshold int, probeInterval time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		log:              log,
+		failureThreshold: failureThreshold,
+		probeInterval:    probeInterval,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a request may be sent. Every allowed request must be
+// followed by Success or Failure.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Before(b.openedAt.Add(b.probeInterval)) {
+			return common.ErrCircuitOpen
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return common.ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	if b.state != BreakerClosed {
+		b.setState(BreakerClosed)
+	}
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.failureThreshold) {
+		b.openedAt = b.now()
+		b.setState(BreakerOpen)
+	}
+}
+
+// Cancel releases a probe whose outcome is unknown, e.g. because the caller's
+// context was cancelled.
+func (b *CircuitBreaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Wait blocks while the circuit is open and the next probe is not due yet,
+// or until ctx is done.
+func (b *CircuitBreaker) Wait(ctx context.Context) error {
+	for {
+		delay := b.untilProbe()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (b *CircuitBreaker) untilProbe() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerOpen {
+		return 0
+	}
+	return b.openedAt.Add(b.probeInterval).Sub(b.now())
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *CircuitBreaker) Snapshot() BreakerSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	snapshot := BreakerSnapshot{
+		State:    b.state,
+		Failures: b.failures,
+	}
+	if b.state == BreakerOpen {
+		probeAt := b.openedAt.Add(b.probeInterval)
+		snapshot.ProbeAt = &probeAt
+	}
+	return snapshot
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	from := b.state
+	b.state = state
+	if state == BreakerOpen {
+		b.log.Warnw("accrual circuit breaker state changed",
+			"from", from, "to", state, "failures", b.failures, "probe_in", b.probeInterval)
+		return
+	}
+	b.log.Infow("accrual circuit breaker state changed", "from", from, "to", state)
+}
@@ -0,0 +1,118 @@
+package accrual
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBreaker(now *time.Time, threshold int, probeInterval time.Duration) *CircuitBreaker {
+	b := NewCircuitBreaker(logger.NewLogger(), threshold, probeInterval)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("opens after threshold", func(t *testing.T) {
+		now := start
+		b := newTestBreaker(&now, 3, time.Minute)
+
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, b.Allow())
+			b.Failure()
+		}
+		assert.Equal(t, BreakerClosed, b.State())
+
+		// Успех сбрасывает счётчик.
+		assert.NoError(t, b.Allow())
+		b.Success()
+		assert.Equal(t, 0, b.Snapshot().Failures)
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, b.Allow())
+			b.Failure()
+		}
+		assert.Equal(t, BreakerOpen, b.State())
+		assert.ErrorIs(t, b.Allow(), common.ErrCircuitOpen)
+
+		snapshot := b.Snapshot()
+		assert.Equal(t, 3, snapshot.Failures)
+		if assert.NotNil(t, snapshot.ProbeAt) {
+			assert.Equal(t, start.Add(time.Minute), *snapshot.ProbeAt)
+		}
+	})
+
+	t.Run("half-open lets a single probe through", func(t *testing.T) {
+		now := start
+		b := newTestBreaker(&now, 1, time.Minute)
+		assert.NoError(t, b.Allow())
+		b.Failure()
+
+		now = now.Add(time.Minute)
+		assert.NoError(t, b.Allow())
+		assert.Equal(t, BreakerHalfOpen, b.State())
+		assert.ErrorIs(t, b.Allow(), common.ErrCircuitOpen)
+
+		b.Success()
+		assert.Equal(t, BreakerClosed, b.State())
+		assert.NoError(t, b.Allow())
+	})
+
+	t.Run("failed probe reopens", func(t *testing.T) {
+		now := start
+		b := newTestBreaker(&now, 1, time.Minute)
+		assert.NoError(t, b.Allow())
+		b.Failure()
+
+		now = now.Add(time.Minute)
+		assert.NoError(t, b.Allow())
+		b.Failure()
+		assert.Equal(t, BreakerOpen, b.State())
+		assert.ErrorIs(t, b.Allow(), common.ErrCircuitOpen)
+		assert.Equal(t, now.Add(time.Minute), *b.Snapshot().ProbeAt)
+	})
+
+	t.Run("cancelled probe is released", func(t *testing.T) {
+		now := start
+		b := newTestBreaker(&now, 1, time.Minute)
+		assert.NoError(t, b.Allow())
+		b.Failure()
+
+		now = now.Add(time.Minute)
+		assert.NoError(t, b.Allow())
+		b.Cancel()
+		assert.Equal(t, BreakerHalfOpen, b.State())
+		assert.NoError(t, b.Allow())
+	})
+}
+
+func TestCircuitBreaker_Wait(t *testing.T) {
+	t.Run("closed does not block", func(t *testing.T) {
+		b := NewCircuitBreaker(logger.NewLogger(), 1, time.Hour)
+		assert.NoError(t, b.Wait(context.Background()))
+	})
+
+	t.Run("open blocks until probe", func(t *testing.T) {
+		b := NewCircuitBreaker(logger.NewLogger(), 1, 50*time.Millisecond)
+		b.Failure()
+
+		start := time.Now()
+		assert.NoError(t, b.Wait(context.Background()))
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		b := NewCircuitBreaker(logger.NewLogger(), 1, time.Hour)
+		b.Failure()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, b.Wait(ctx), context.DeadlineExceeded)
+	})
+}
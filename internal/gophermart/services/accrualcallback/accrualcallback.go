@@ -0,0 +1,108 @@
+package accrualcallback
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+type orderFinder interface {
+	Find(ctx context.Context, number string) (entity.Order, error)
+}
+
+type orderBalanceRepo interface {
+	UpdateOrderBalance(ctx context.Context, order entity.Order) error
+}
+
+type pollPostponer interface {
+	Postpone(ctx context.Context, number string, delay time.Duration) error
+}
+
+// CallbackService applies order statuses pushed by the accrual system. The
+// orders stay queued: an order which is not final yet is polled if the next
+// callback does not come within timeout.
+type CallbackService struct {
+	log     *logger.Logger
+	orders  orderFinder
+	repo    orderBalanceRepo
+	queue   pollPostponer
+	timeout time.Duration
+}
+
+func NewCallbackService(
+	log *logger.Logger,
+	orders orderFinder,
+	repo orderBalanceRepo,
+	queue pollPostponer,
+	timeout time.Duration,
+) *CallbackService {
+	return &CallbackService{
+		log:     log,
+		orders:  orders,
+		repo:    repo,
+		queue:   queue,
+		timeout: timeout,
+	}
+}
+
+// Apply applies every update on its own and returns an error per update:
+// nil, common.ErrNonExistentOrder, common.ErrIllegalStatusTransition or
+// common.ErrInternalError. Updates are idempotent, so a callback can be
+// safely delivered again.
+func (s *CallbackService) Apply(ctx context.Context, updates []entity.Order) []error {
+	errs := make([]error, len(updates))
+	for i, update := range updates {
+		errs[i] = s.apply(ctx, update)
+	}
+	return errs
+}
+
+func (s *CallbackService) apply(ctx context.Context, update entity.Order) error {
+	log := s.log.With("op", "CallbackService.Apply", "number", update.Number, "status", update.Status)
+
+	order, err := s.orders.Find(ctx, update.Number)
+	if err != nil {
+		log.Error(err)
+		return common.ErrInternalError
+	}
+	if order.Number == "" {
+		return common.ErrNonExistentOrder
+	}
+
+	// The same status is reported again, e.g. the callback is retried or
+	// the order has already been polled: nothing to credit.
+	if update.Status != order.Status {
+		if !order.Status.CanTransitionTo(update.Status) {
+			log.Warnw("illegal order status transition rejected", "from", order.Status)
+			return common.ErrIllegalStatusTransition
+		}
+		// The repository checks the transition again under the row lock.
+		err = s.repo.UpdateOrderBalance(ctx, entity.Order{
+			UserID:  order.UserID,
+			Number:  order.Number,
+			Status:  update.Status,
+			Accrual: update.Accrual,
+		})
+		if errors.Is(err, common.ErrIllegalStatusTransition) {
+			log.Warnw("illegal order status transition rejected", "from", order.Status)
+			return common.ErrIllegalStatusTransition
+		}
+		if err != nil {
+			log.Error(err)
+			return common.ErrInternalError
+		}
+		log.Infow("order status applied from callback", "from", order.Status)
+	}
+
+	if !update.Status.IsFinal() {
+		if err := s.queue.Postpone(ctx, order.Number, s.timeout); err != nil {
+			// The order is only polled earlier than needed.
+			log.Error("failed to postpone order poll: ", err)
+		}
+	}
+	return nil
+}
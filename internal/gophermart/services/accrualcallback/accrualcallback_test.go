@@ -0,0 +1,165 @@
+package accrualcallback
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallbackService_Apply(t *testing.T) {
+	ctx := context.Background()
+	timeout := time.Minute
+	accrual := entity.Money(50000)
+	stored := entity.Order{UserID: 1, Number: "12345678903", Status: entity.OrderNew}
+
+	tests := []struct {
+		name        string
+		update      entity.Order
+		setupMocks  func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer)
+		expectedErr error
+	}{
+		{
+			name:   "processed",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessed, Accrual: &accrual},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				orders.EXPECT().Find(ctx, "12345678903").Return(stored, nil)
+				repo.EXPECT().UpdateOrderBalance(ctx, entity.Order{
+					UserID:  1,
+					Number:  "12345678903",
+					Status:  entity.OrderProcessed,
+					Accrual: &accrual,
+				}).Return(nil)
+			},
+		},
+		{
+			// Промежуточный статус откладывает опрос заказа
+			name:   "processing",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessing},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				orders.EXPECT().Find(ctx, "12345678903").Return(stored, nil)
+				repo.EXPECT().UpdateOrderBalance(ctx, entity.Order{
+					UserID: 1,
+					Number: "12345678903",
+					Status: entity.OrderProcessing,
+				}).Return(nil)
+				queue.EXPECT().Postpone(ctx, "12345678903", timeout).Return(nil)
+			},
+		},
+		{
+			name:   "same status",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderNew},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				orders.EXPECT().Find(ctx, "12345678903").Return(stored, nil)
+				queue.EXPECT().Postpone(ctx, "12345678903", timeout).Return(nil)
+			},
+		},
+		{
+			name:   "repeated final status",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessed, Accrual: &accrual},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				processed := stored
+				processed.Status = entity.OrderProcessed
+				orders.EXPECT().Find(ctx, "12345678903").Return(processed, nil)
+			},
+		},
+		{
+			name:   "postpone error is ignored",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessing},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				orders.EXPECT().Find(ctx, "12345678903").Return(stored, nil)
+				repo.EXPECT().UpdateOrderBalance(ctx, gomock.Any()).Return(nil)
+				queue.EXPECT().Postpone(ctx, "12345678903", timeout).Return(errors.New("db error"))
+			},
+		},
+		{
+			name:   "unknown order",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessing},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				orders.EXPECT().Find(ctx, "12345678903").Return(entity.Order{}, nil)
+			},
+			expectedErr: common.ErrNonExistentOrder,
+		},
+		{
+			name:   "illegal transition",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessing},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				invalid := stored
+				invalid.Status = entity.OrderInvalid
+				orders.EXPECT().Find(ctx, "12345678903").Return(invalid, nil)
+			},
+			expectedErr: common.ErrIllegalStatusTransition,
+		},
+		{
+			// Статус изменился параллельно, между поиском и обновлением
+			name:   "illegal transition under lock",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessing},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				orders.EXPECT().Find(ctx, "12345678903").Return(stored, nil)
+				repo.EXPECT().UpdateOrderBalance(ctx, gomock.Any()).Return(common.ErrIllegalStatusTransition)
+			},
+			expectedErr: common.ErrIllegalStatusTransition,
+		},
+		{
+			name:   "find error",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessing},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				orders.EXPECT().Find(ctx, "12345678903").Return(entity.Order{}, errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			name:   "update error",
+			update: entity.Order{Number: "12345678903", Status: entity.OrderProcessed, Accrual: &accrual},
+			setupMocks: func(orders *MockorderFinder, repo *MockorderBalanceRepo, queue *MockpollPostponer) {
+				orders.EXPECT().Find(ctx, "12345678903").Return(stored, nil)
+				repo.EXPECT().UpdateOrderBalance(ctx, gomock.Any()).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orders := NewMockorderFinder(ctrl)
+			repo := NewMockorderBalanceRepo(ctrl)
+			queue := NewMockpollPostponer(ctrl)
+			tt.setupMocks(orders, repo, queue)
+
+			s := NewCallbackService(logger.NewLogger(), orders, repo, queue, timeout)
+			errs := s.Apply(ctx, []entity.Order{tt.update})
+
+			assert.Len(t, errs, 1)
+			assert.ErrorIs(t, errs[0], tt.expectedErr)
+		})
+	}
+
+	t.Run("updates are applied independently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		orders := NewMockorderFinder(ctrl)
+		repo := NewMockorderBalanceRepo(ctrl)
+		queue := NewMockpollPostponer(ctrl)
+		orders.EXPECT().Find(ctx, "79927398713").Return(entity.Order{}, nil)
+		orders.EXPECT().Find(ctx, "12345678903").Return(stored, nil)
+		repo.EXPECT().UpdateOrderBalance(ctx, gomock.Any()).Return(nil)
+
+		s := NewCallbackService(logger.NewLogger(), orders, repo, queue, timeout)
+		errs := s.Apply(ctx, []entity.Order{
+			{Number: "79927398713", Status: entity.OrderProcessed},
+			{Number: "12345678903", Status: entity.OrderInvalid},
+		})
+
+		assert.Equal(t, []error{common.ErrNonExistentOrder, nil}, errs)
+	})
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: accrualcallback.go
+
+// Package accrualcallback is a generated GoMock package.
+package accrualcallback
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockorderFinder is a mock of orderFinder interface.
+type MockorderFinder struct {
+	ctrl     *gomock.Controller
+	recorder *MockorderFinderMockRecorder
+}
+
+// MockorderFinderMockRecorder is the mock recorder for MockorderFinder.
+type MockorderFinderMockRecorder struct {
+	mock *MockorderFinder
+}
+
+// NewMockorderFinder creates a new mock instance.
+func NewMockorderFinder(ctrl *gomock.Controller) *MockorderFinder {
+	mock := &MockorderFinder{ctrl: ctrl}
+	mock.recorder = &MockorderFinderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockorderFinder) EXPECT() *MockorderFinderMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockorderFinder) Find(ctx context.Context, number string) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, number)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockorderFinderMockRecorder) Find(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockorderFinder)(nil).Find), ctx, number)
+}
+
+// MockorderBalanceRepo is a mock of orderBalanceRepo interface.
+type MockorderBalanceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockorderBalanceRepoMockRecorder
+}
+
+// MockorderBalanceRepoMockRecorder is the mock recorder for MockorderBalanceRepo.
+type MockorderBalanceRepoMockRecorder struct {
+	mock *MockorderBalanceRepo
+}
+
+// NewMockorderBalanceRepo creates a new mock instance.
+func NewMockorderBalanceRepo(ctrl *gomock.Controller) *MockorderBalanceRepo {
+	mock := &MockorderBalanceRepo{ctrl: ctrl}
+	mock.recorder = &MockorderBalanceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockorderBalanceRepo) EXPECT() *MockorderBalanceRepoMockRecorder {
+	return m.recorder
+}
+
+// UpdateOrderBalance mocks base method.
+func (m *MockorderBalanceRepo) UpdateOrderBalance(ctx context.Context, order entity.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderBalance", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrderBalance indicates an expected call of UpdateOrderBalance.
+func (mr *MockorderBalanceRepoMockRecorder) UpdateOrderBalance(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderBalance", reflect.TypeOf((*MockorderBalanceRepo)(nil).UpdateOrderBalance), ctx, order)
+}
+
+// MockpollPostponer is a mock of pollPostponer interface.
+type MockpollPostponer struct {
+	ctrl     *gomock.Controller
+	recorder *MockpollPostponerMockRecorder
+}
+
+// MockpollPostponerMockRecorder is the mock recorder for MockpollPostponer.
+type MockpollPostponerMockRecorder struct {
+	mock *MockpollPostponer
+}
+
+// NewMockpollPostponer creates a new mock instance.
+func NewMockpollPostponer(ctrl *gomock.Controller) *MockpollPostponer {
+	mock := &MockpollPostponer{ctrl: ctrl}
+	mock.recorder = &MockpollPostponerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpollPostponer) EXPECT() *MockpollPostponerMockRecorder {
+	return m.recorder
+}
+
+// Postpone mocks base method.
+func (m *MockpollPostponer) Postpone(ctx context.Context, number string, delay time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Postpone", ctx, number, delay)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Postpone indicates an expected call of Postpone.
+func (mr *MockpollPostponerMockRecorder) Postpone(ctx, number, delay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Postpone", reflect.TypeOf((*MockpollPostponer)(nil).Postpone), ctx, number, delay)
+}
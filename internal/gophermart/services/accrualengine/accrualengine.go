@@ -0,0 +1,105 @@
+package accrualengine
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/utils"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+type engineRepo interface {
+	AddRule(ctx context.Context, rule entity.RewardRule) error
+	ListRules(ctx context.Context) ([]entity.RewardRule, error)
+	SaveOrder(ctx context.Context, number string, goods []entity.Good, accrual entity.Money) error
+	FindOrderAccrual(ctx context.Context, number string) (entity.Money, error)
+}
+
+// AccrualEngine calculates accruals in-process, for deployments without the
+// external accrual system. Shops register reward rules and orders with their
+// goods the same way as in the accrual system, and the order worker polls the
+// engine instead of AccrualService.
+type AccrualEngine struct {
+	log  *logger.Logger
+	repo engineRepo
+}
+
+func NewAccrualEngine(log *logger.Logger, repo engineRepo) *AccrualEngine {
+	return &AccrualEngine{
+		log:  log,
+		repo: repo,
+	}
+}
+
+func (e *AccrualEngine) AddRule(ctx context.Context, rule entity.RewardRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	err := e.repo.AddRule(ctx, rule)
+	if err != nil {
+		if errors.Is(err, common.ErrRewardRuleExists) {
+			return common.ErrRewardRuleExists
+		}
+		e.log.Error("failed to add reward rule: ", err)
+		return common.ErrInternalError
+	}
+	e.log.Infow("reward rule added", "match", rule.Match, "reward", rule.Reward, "reward_type", rule.RewardType)
+
+	return nil
+}
+
+// RegisterOrder calculates the accrual for goods with the rules known at the
+// moment: rules added later do not change it.
+func (e *AccrualEngine) RegisterOrder(ctx context.Context, number string, goods []entity.Good) error {
+	if !utils.IsOrderNumberValid(number) {
+		return common.ErrInvalidOrderNumber
+	}
+	for _, good := range goods {
+		if err := good.Validate(); err != nil {
+			return err
+		}
+	}
+
+	rules, err := e.repo.ListRules(ctx)
+	if err != nil {
+		e.log.Error("failed to list reward rules: ", err)
+		return common.ErrInternalError
+	}
+
+	accrual, err := entity.CalculateAccrual(goods, rules)
+	if err != nil {
+		return err
+	}
+	err = e.repo.SaveOrder(ctx, number, goods, accrual)
+	if err != nil {
+		if errors.Is(err, common.ErrAccrualOrderExists) {
+			return common.ErrAccrualOrderExists
+		}
+		e.log.Error("failed to save accrual order: ", err)
+		return common.ErrInternalError
+	}
+
+	return nil
+}
+
+// GetOrderAccrual answers like the accrual system: an order is processed as
+// soon as it is registered, and has no accrual if none of its goods is
+// rewarded.
+func (e *AccrualEngine) GetOrderAccrual(ctx context.Context, number string) (entity.Order, error) {
+	accrual, err := e.repo.FindOrderAccrual(ctx, number)
+	if err != nil {
+		if errors.Is(err, common.ErrNonExistentOrder) {
+			return entity.Order{}, common.ErrNonExistentOrder
+		}
+		return entity.Order{}, err
+	}
+
+	order := entity.Order{Status: entity.OrderProcessed}
+	if accrual > 0 {
+		order.Accrual = &accrual
+	}
+	return order, nil
+}
@@ -0,0 +1,237 @@
+package accrualengine
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const orderNumber = "12345678903"
+
+func TestAccrualEngine_AddRule(t *testing.T) {
+	ctx := context.Background()
+	rule := entity.RewardRule{Match: "Bork", Reward: 1000, RewardType: entity.RewardPercent}
+
+	tests := []struct {
+		name        string
+		rule        entity.RewardRule
+		setupMocks  func(repo *MockengineRepo)
+		expectedErr error
+	}{
+		{
+			name: "success",
+			rule: rule,
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().AddRule(ctx, rule).Return(nil)
+			},
+		},
+		{
+			name:        "invalid rule",
+			rule:        entity.RewardRule{Match: "Bork", RewardType: entity.RewardPercent},
+			setupMocks:  func(repo *MockengineRepo) {},
+			expectedErr: common.ErrInvalidRewardRule,
+		},
+		{
+			name: "duplicate rule",
+			rule: rule,
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().AddRule(ctx, rule).Return(common.ErrRewardRuleExists)
+			},
+			expectedErr: common.ErrRewardRuleExists,
+		},
+		{
+			name: "repository error",
+			rule: rule,
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().AddRule(ctx, rule).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockengineRepo(ctrl)
+			tt.setupMocks(repo)
+
+			engine := NewAccrualEngine(logger.NewLogger(), repo)
+			assert.ErrorIs(t, engine.AddRule(ctx, tt.rule), tt.expectedErr)
+		})
+	}
+}
+
+func TestAccrualEngine_RegisterOrder(t *testing.T) {
+	ctx := context.Background()
+	rules := []entity.RewardRule{
+		{Match: "Bork", Reward: 1000, RewardType: entity.RewardPercent},
+		{Match: "Miele", Reward: 5000, RewardType: entity.RewardPoints},
+	}
+	goods := []entity.Good{
+		{Description: "Чайник Bork", Price: 700000},
+		{Description: "Стиральная машинка Miele", Price: 4500050},
+	}
+
+	tests := []struct {
+		name        string
+		number      string
+		goods       []entity.Good
+		setupMocks  func(repo *MockengineRepo)
+		expectedErr error
+	}{
+		{
+			name:   "accrual is calculated",
+			number: orderNumber,
+			goods:  goods,
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().ListRules(ctx).Return(rules, nil)
+				// 10% от 7000 + 50 баллов
+				repo.EXPECT().SaveOrder(ctx, orderNumber, goods, entity.Money(75000)).Return(nil)
+			},
+		},
+		{
+			name:        "invalid number",
+			number:      "12345678900",
+			goods:       goods,
+			setupMocks:  func(repo *MockengineRepo) {},
+			expectedErr: common.ErrInvalidOrderNumber,
+		},
+		{
+			name:        "negative price",
+			number:      orderNumber,
+			goods:       []entity.Good{{Description: "Чайник Bork", Price: -1}},
+			setupMocks:  func(repo *MockengineRepo) {},
+			expectedErr: common.ErrInvalidAmount,
+		},
+		{
+			name:        "price too large",
+			number:      orderNumber,
+			goods:       []entity.Good{{Description: "Чайник Bork", Price: entity.MaxPrice + 1}},
+			setupMocks:  func(repo *MockengineRepo) {},
+			expectedErr: common.ErrInvalidAmount,
+		},
+		{
+			name:   "accrual out of range",
+			number: orderNumber,
+			goods: []entity.Good{
+				{Description: "Стиральная машинка Miele"},
+				{Description: "Сушильная машина Miele"},
+			},
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().ListRules(ctx).Return([]entity.RewardRule{
+					{Match: "Miele", Reward: math.MaxInt64, RewardType: entity.RewardPoints},
+				}, nil)
+			},
+			expectedErr: common.ErrInvalidAmount,
+		},
+		{
+			name:   "already registered",
+			number: orderNumber,
+			goods:  goods,
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().ListRules(ctx).Return(rules, nil)
+				repo.EXPECT().SaveOrder(ctx, orderNumber, goods, entity.Money(75000)).Return(common.ErrAccrualOrderExists)
+			},
+			expectedErr: common.ErrAccrualOrderExists,
+		},
+		{
+			name:   "rules error",
+			number: orderNumber,
+			goods:  goods,
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().ListRules(ctx).Return(nil, errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			name:   "save error",
+			number: orderNumber,
+			goods:  goods,
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().ListRules(ctx).Return(rules, nil)
+				repo.EXPECT().SaveOrder(ctx, orderNumber, goods, entity.Money(75000)).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockengineRepo(ctrl)
+			tt.setupMocks(repo)
+
+			engine := NewAccrualEngine(logger.NewLogger(), repo)
+			assert.ErrorIs(t, engine.RegisterOrder(ctx, tt.number, tt.goods), tt.expectedErr)
+		})
+	}
+}
+
+func TestAccrualEngine_GetOrderAccrual(t *testing.T) {
+	ctx := context.Background()
+	accrual := entity.Money(75000)
+	dbErr := errors.New("db error")
+
+	tests := []struct {
+		name        string
+		setupMocks  func(repo *MockengineRepo)
+		expected    entity.Order
+		expectedErr error
+	}{
+		{
+			name: "processed with accrual",
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().FindOrderAccrual(ctx, orderNumber).Return(accrual, nil)
+			},
+			expected: entity.Order{Status: entity.OrderProcessed, Accrual: &accrual},
+		},
+		{
+			name: "processed without accrual",
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().FindOrderAccrual(ctx, orderNumber).Return(entity.Money(0), nil)
+			},
+			expected: entity.Order{Status: entity.OrderProcessed},
+		},
+		{
+			name: "not registered",
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().FindOrderAccrual(ctx, orderNumber).Return(entity.Money(0), common.ErrNonExistentOrder)
+			},
+			expectedErr: common.ErrNonExistentOrder,
+		},
+		{
+			// Ошибка уходит воркеру и считается неудачной попыткой
+			name: "repository error",
+			setupMocks: func(repo *MockengineRepo) {
+				repo.EXPECT().FindOrderAccrual(ctx, orderNumber).Return(entity.Money(0), dbErr)
+			},
+			expectedErr: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockengineRepo(ctrl)
+			tt.setupMocks(repo)
+
+			engine := NewAccrualEngine(logger.NewLogger(), repo)
+			order, err := engine.GetOrderAccrual(ctx, orderNumber)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, order)
+		})
+	}
+}
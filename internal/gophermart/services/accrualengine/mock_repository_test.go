@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: accrualengine.go
+
+// Package accrualengine is a generated GoMock package.
+package accrualengine
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockengineRepo is a mock of engineRepo interface.
+type MockengineRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockengineRepoMockRecorder
+}
+
+// MockengineRepoMockRecorder is the mock recorder for MockengineRepo.
+type MockengineRepoMockRecorder struct {
+	mock *MockengineRepo
+}
+
+// NewMockengineRepo creates a new mock instance.
+func NewMockengineRepo(ctrl *gomock.Controller) *MockengineRepo {
+	mock := &MockengineRepo{ctrl: ctrl}
+	mock.recorder = &MockengineRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockengineRepo) EXPECT() *MockengineRepoMockRecorder {
+	return m.recorder
+}
+
+// AddRule mocks base method.
+func (m *MockengineRepo) AddRule(ctx context.Context, rule entity.RewardRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRule indicates an expected call of AddRule.
+func (mr *MockengineRepoMockRecorder) AddRule(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRule", reflect.TypeOf((*MockengineRepo)(nil).AddRule), ctx, rule)
+}
+
+// FindOrderAccrual mocks base method.
+func (m *MockengineRepo) FindOrderAccrual(ctx context.Context, number string) (entity.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderAccrual", ctx, number)
+	ret0, _ := ret[0].(entity.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderAccrual indicates an expected call of FindOrderAccrual.
+func (mr *MockengineRepoMockRecorder) FindOrderAccrual(ctx, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderAccrual", reflect.TypeOf((*MockengineRepo)(nil).FindOrderAccrual), ctx, number)
+}
+
+// ListRules mocks base method.
+func (m *MockengineRepo) ListRules(ctx context.Context) ([]entity.RewardRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules", ctx)
+	ret0, _ := ret[0].([]entity.RewardRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockengineRepoMockRecorder) ListRules(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockengineRepo)(nil).ListRules), ctx)
+}
+
+// SaveOrder mocks base method.
+func (m *MockengineRepo) SaveOrder(ctx context.Context, number string, goods []entity.Good, accrual entity.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOrder", ctx, number, goods, accrual)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOrder indicates an expected call of SaveOrder.
+func (mr *MockengineRepoMockRecorder) SaveOrder(ctx, number, goods, accrual interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOrder", reflect.TypeOf((*MockengineRepo)(nil).SaveOrder), ctx, number, goods, accrual)
+}
@@ -0,0 +1,219 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+const (
+	// searchLimit caps the users found by a login search.
+	searchLimit = 50
+
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+type userRepo interface {
+	SearchByLogin(ctx context.Context, prefix string, limit int) ([]entity.User, error)
+	UpdateExternalID(ctx context.Context, userID int64, externalID *string) error
+}
+
+type auditedRepo interface {
+	Adjust(ctx context.Context, entry entity.LedgerEntry, audit entity.AuditEntry) error
+	InvalidateOrder(ctx context.Context, number string, audit entity.AuditEntry) error
+}
+
+type auditLog interface {
+	Record(ctx context.Context, entry entity.AuditEntry) error
+	List(ctx context.Context, limit int, offset int) ([]entity.AuditEntry, error)
+}
+
+type orderRequeuer interface {
+	Requeue(ctx context.Context, number string) error
+}
+
+type loginUnlocker interface {
+	Unlock(ctx context.Context, login string) error
+}
+
+// AdminService backs the operations of support staff. Every change is
+// recorded in the audit log together with the user who made it.
+type AdminService struct {
+	log     *logger.Logger
+	users   userRepo
+	audited auditedRepo
+	audit   auditLog
+	queue   orderRequeuer
+	lockout loginUnlocker
+}
+
+func NewAdminService(
+	log *logger.Logger,
+	users userRepo,
+	audited auditedRepo,
+	audit auditLog,
+	queue orderRequeuer,
+	lockout loginUnlocker,
+) *AdminService {
+	return &AdminService{
+		log:     log,
+		users:   users,
+		audited: audited,
+		audit:   audit,
+		queue:   queue,
+		lockout: lockout,
+	}
+}
+
+// SearchUsers returns the users whose login starts with prefix. Password
+// hashes are not returned.
+func (s *AdminService) SearchUsers(ctx context.Context, prefix string) ([]entity.User, error) {
+	log := s.log.With("op", "AdminService.SearchUsers", "prefix", prefix)
+
+	users, err := s.users.SearchByLogin(ctx, prefix, searchLimit)
+	if err != nil {
+		log.Error(err)
+		return nil, common.ErrInternalError
+	}
+	for i := range users {
+		users[i].Password = ""
+	}
+	return users, nil
+}
+
+// SetExternalID sets the id partner systems know the user by, see
+// entity.PartnerCustomer. An empty id removes it.
+func (s *AdminService) SetExternalID(ctx context.Context, actorID int64, userID int64, externalID string) error {
+	log := s.log.With("op", "AdminService.SetExternalID", "actor", actorID, "user_id", userID)
+
+	var id *string
+	if externalID != "" {
+		id = &externalID
+	}
+	if err := s.users.UpdateExternalID(ctx, userID, id); err != nil {
+		if errors.Is(err, common.ErrUserNotFound) || errors.Is(err, common.ErrExternalIDTaken) {
+			return err
+		}
+		log.Error(err)
+		return common.ErrInternalError
+	}
+
+	return s.record(ctx, entity.AuditEntry{
+		ActorID: actorID,
+		Action:  entity.AuditExternalIDSet,
+		Target:  strconv.FormatInt(userID, 10),
+	})
+}
+
+// Adjust credits (positive amount) or debits (negative amount) a user's
+// balance by hand. The reason is mandatory.
+func (s *AdminService) Adjust(ctx context.Context, actorID int64, userID int64, amount entity.Money, reason string) error {
+	log := s.log.With("op", "AdminService.Adjust", "actor", actorID, "user_id", userID)
+	if amount == 0 {
+		return common.ErrInvalidAmount
+	}
+	if reason == "" {
+		return common.ErrInvalidReason
+	}
+
+	err := s.audited.Adjust(ctx, entity.LedgerEntry{
+		UserID: userID,
+		Type:   entity.LedgerAdjustment,
+		Amount: amount,
+		Reason: reason,
+	}, entity.AuditEntry{
+		ActorID: actorID,
+		Action:  entity.AuditBalanceAdjusted,
+		Target:  strconv.FormatInt(userID, 10),
+		Amount:  &amount,
+		Reason:  reason,
+	})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Infow("balance adjusted", "amount", amount, "reason", reason)
+	return nil
+}
+
+// InvalidateOrder marks a pending order INVALID, e.g. a fraudulent one. The
+// reason is mandatory.
+func (s *AdminService) InvalidateOrder(ctx context.Context, actorID int64, number string, reason string) error {
+	log := s.log.With("op", "AdminService.InvalidateOrder", "actor", actorID, "number", number)
+	if reason == "" {
+		return common.ErrInvalidReason
+	}
+
+	err := s.audited.InvalidateOrder(ctx, number, entity.AuditEntry{
+		ActorID: actorID,
+		Action:  entity.AuditOrderInvalidated,
+		Target:  number,
+		Reason:  reason,
+	})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Infow("order invalidated", "reason", reason)
+	return nil
+}
+
+// Requeue makes the order due, so that the accrual system is polled again.
+func (s *AdminService) Requeue(ctx context.Context, actorID int64, number string) error {
+	if err := s.queue.Requeue(ctx, number); err != nil {
+		return err
+	}
+	return s.record(ctx, entity.AuditEntry{
+		ActorID: actorID,
+		Action:  entity.AuditOrderRequeued,
+		Target:  number,
+	})
+}
+
+// Unlock lifts the lockout of a login after too many failed login attempts.
+func (s *AdminService) Unlock(ctx context.Context, actorID int64, login string) error {
+	if err := s.lockout.Unlock(ctx, login); err != nil {
+		return err
+	}
+	return s.record(ctx, entity.AuditEntry{
+		ActorID: actorID,
+		Action:  entity.AuditLoginUnlocked,
+		Target:  login,
+	})
+}
+
+// record audits an operation which has been done already. Repeating these
+// operations is harmless, so a failure is reported to retry them.
+func (s *AdminService) record(ctx context.Context, entry entity.AuditEntry) error {
+	if err := s.audit.Record(ctx, entry); err != nil {
+		s.log.Errorw("failed to record audit entry",
+			"op", "AdminService.record", "action", entry.Action, "target", entry.Target, "error", err)
+		return common.ErrInternalError
+	}
+	return nil
+}
+
+// AuditLog returns a page of the audit log, the latest entries first. A
+// missing limit defaults to defaultAuditLimit, a larger one than
+// maxAuditLimit is capped.
+func (s *AdminService) AuditLog(ctx context.Context, limit int, offset int) ([]entity.AuditEntry, error) {
+	if limit < 0 || offset < 0 {
+		return nil, common.ErrInvalidHistoryFilter
+	}
+	if limit == 0 {
+		limit = defaultAuditLimit
+	}
+	limit = min(limit, maxAuditLimit)
+
+	entries, err := s.audit.List(ctx, limit, offset)
+	if err != nil {
+		s.log.Errorw("failed to list audit log", "op", "AdminService.AuditLog", "error", err)
+		return nil, common.ErrInternalError
+	}
+	return entries, nil
+}
@@ -0,0 +1,323 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDeps struct {
+	users   *MockuserRepo
+	audited *MockauditedRepo
+	audit   *MockauditLog
+	queue   *MockorderRequeuer
+	lockout *MockloginUnlocker
+}
+
+func newTestService(ctrl *gomock.Controller) (*AdminService, testDeps) {
+	deps := testDeps{
+		users:   NewMockuserRepo(ctrl),
+		audited: NewMockauditedRepo(ctrl),
+		audit:   NewMockauditLog(ctrl),
+		queue:   NewMockorderRequeuer(ctrl),
+		lockout: NewMockloginUnlocker(ctrl),
+	}
+	svc := NewAdminService(logger.NewLogger(), deps.users, deps.audited, deps.audit, deps.queue, deps.lockout)
+	return svc, deps
+}
+
+func TestAdminService_SearchUsers(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestService(ctrl)
+
+	t.Run("hides password hashes", func(t *testing.T) {
+		deps.users.EXPECT().
+			SearchByLogin(ctx, "test", searchLimit).
+			Return([]entity.User{{ID: 1, Login: "testuser", Password: "$argon2id$..."}}, nil)
+
+		users, err := svc.SearchUsers(ctx, "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entity.User{{ID: 1, Login: "testuser"}}, users)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		deps.users.EXPECT().
+			SearchByLogin(ctx, "test", searchLimit).
+			Return(nil, errors.New("db error"))
+
+		_, err := svc.SearchUsers(ctx, "test")
+
+		assert.ErrorIs(t, err, common.ErrInternalError)
+	})
+}
+
+func TestAdminService_Adjust(t *testing.T) {
+	ctx := context.Background()
+	actorID, userID := int64(7), int64(1)
+
+	tests := []struct {
+		name        string
+		amount      entity.Money
+		reason      string
+		setupMocks  func(deps testDeps)
+		expectedErr error
+	}{
+		{
+			name:   "credit",
+			amount: 15000,
+			reason: "compensation",
+			setupMocks: func(deps testDeps) {
+				amount := entity.Money(15000)
+				deps.audited.EXPECT().
+					Adjust(ctx, entity.LedgerEntry{
+						UserID: userID,
+						Type:   entity.LedgerAdjustment,
+						Amount: 15000,
+						Reason: "compensation",
+					}, entity.AuditEntry{
+						ActorID: actorID,
+						Action:  entity.AuditBalanceAdjusted,
+						Target:  "1",
+						Amount:  &amount,
+						Reason:  "compensation",
+					}).
+					Return(nil)
+			},
+		},
+		{
+			name:   "debit below zero",
+			amount: -15000,
+			reason: "fraud",
+			setupMocks: func(deps testDeps) {
+				deps.audited.EXPECT().
+					Adjust(ctx, gomock.Any(), gomock.Any()).
+					Return(common.ErrInsufficientBalance)
+			},
+			expectedErr: common.ErrInsufficientBalance,
+		},
+		{
+			name:        "zero amount",
+			amount:      0,
+			reason:      "nothing",
+			setupMocks:  func(deps testDeps) {},
+			expectedErr: common.ErrInvalidAmount,
+		},
+		{
+			name:        "no reason",
+			amount:      100,
+			setupMocks:  func(deps testDeps) {},
+			expectedErr: common.ErrInvalidReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, deps := newTestService(ctrl)
+			tt.setupMocks(deps)
+
+			err := svc.Adjust(ctx, actorID, userID, tt.amount, tt.reason)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestAdminService_InvalidateOrder(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestService(ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		deps.audited.EXPECT().
+			InvalidateOrder(ctx, "12345678903", entity.AuditEntry{
+				ActorID: 7,
+				Action:  entity.AuditOrderInvalidated,
+				Target:  "12345678903",
+				Reason:  "fraud",
+			}).
+			Return(nil)
+
+		assert.NoError(t, svc.InvalidateOrder(ctx, 7, "12345678903", "fraud"))
+	})
+
+	t.Run("no reason", func(t *testing.T) {
+		assert.ErrorIs(t, svc.InvalidateOrder(ctx, 7, "12345678903", ""), common.ErrInvalidReason)
+	})
+
+	t.Run("order is final", func(t *testing.T) {
+		deps.audited.EXPECT().
+			InvalidateOrder(ctx, "12345678903", gomock.Any()).
+			Return(common.ErrIllegalStatusTransition)
+
+		err := svc.InvalidateOrder(ctx, 7, "12345678903", "fraud")
+
+		assert.ErrorIs(t, err, common.ErrIllegalStatusTransition)
+	})
+}
+
+func TestAdminService_Requeue(t *testing.T) {
+	ctx := context.Background()
+	audit := entity.AuditEntry{ActorID: 7, Action: entity.AuditOrderRequeued, Target: "12345678903"}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(deps testDeps)
+		expectedErr error
+	}{
+		{
+			name: "success",
+			setupMocks: func(deps testDeps) {
+				gomock.InOrder(
+					deps.queue.EXPECT().Requeue(ctx, "12345678903").Return(nil),
+					deps.audit.EXPECT().Record(ctx, audit).Return(nil),
+				)
+			},
+		},
+		{
+			// Без изменения запись в журнал не нужна
+			name: "unknown order",
+			setupMocks: func(deps testDeps) {
+				deps.queue.EXPECT().Requeue(ctx, "12345678903").Return(common.ErrNonExistentOrder)
+			},
+			expectedErr: common.ErrNonExistentOrder,
+		},
+		{
+			name: "audit error",
+			setupMocks: func(deps testDeps) {
+				deps.queue.EXPECT().Requeue(ctx, "12345678903").Return(nil)
+				deps.audit.EXPECT().Record(ctx, audit).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, deps := newTestService(ctrl)
+			tt.setupMocks(deps)
+
+			err := svc.Requeue(ctx, 7, "12345678903")
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestAdminService_Unlock(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestService(ctrl)
+
+	gomock.InOrder(
+		deps.lockout.EXPECT().Unlock(ctx, "testuser").Return(nil),
+		deps.audit.EXPECT().
+			Record(ctx, entity.AuditEntry{ActorID: 7, Action: entity.AuditLoginUnlocked, Target: "testuser"}).
+			Return(nil),
+	)
+
+	assert.NoError(t, svc.Unlock(ctx, 7, "testuser"))
+}
+
+func TestAdminService_AuditLog(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		limit         int
+		offset        int
+		expectedLimit int
+		expectedErr   error
+	}{
+		{name: "default limit", expectedLimit: defaultAuditLimit},
+		{name: "capped limit", limit: 10000, offset: 20, expectedLimit: maxAuditLimit},
+		{name: "negative offset", offset: -1, expectedErr: common.ErrInvalidHistoryFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, deps := newTestService(ctrl)
+			if tt.expectedErr == nil {
+				deps.audit.EXPECT().List(ctx, tt.expectedLimit, tt.offset).Return(nil, nil)
+			}
+
+			_, err := svc.AuditLog(ctx, tt.limit, tt.offset)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestAdminService_SetExternalID(t *testing.T) {
+	ctx := context.Background()
+	externalID := "pos-42"
+	audit := entity.AuditEntry{ActorID: 7, Action: entity.AuditExternalIDSet, Target: "1"}
+
+	tests := []struct {
+		name        string
+		externalID  string
+		setupMocks  func(deps testDeps)
+		expectedErr error
+	}{
+		{
+			name:       "set",
+			externalID: externalID,
+			setupMocks: func(deps testDeps) {
+				deps.users.EXPECT().UpdateExternalID(ctx, int64(1), &externalID).Return(nil)
+				deps.audit.EXPECT().Record(ctx, audit).Return(nil)
+			},
+		},
+		{
+			name: "removed",
+			setupMocks: func(deps testDeps) {
+				deps.users.EXPECT().UpdateExternalID(ctx, int64(1), nil).Return(nil)
+				deps.audit.EXPECT().Record(ctx, audit).Return(nil)
+			},
+		},
+		{
+			name:       "taken by another user",
+			externalID: externalID,
+			setupMocks: func(deps testDeps) {
+				deps.users.EXPECT().UpdateExternalID(ctx, int64(1), &externalID).Return(common.ErrExternalIDTaken)
+			},
+			expectedErr: common.ErrExternalIDTaken,
+		},
+		{
+			name:       "repository error",
+			externalID: externalID,
+			setupMocks: func(deps testDeps) {
+				deps.users.EXPECT().UpdateExternalID(ctx, int64(1), &externalID).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, deps := newTestService(ctrl)
+			tt.setupMocks(deps)
+
+			err := svc.SetExternalID(ctx, 7, 1, tt.externalID)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
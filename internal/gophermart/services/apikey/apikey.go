@@ -0,0 +1,167 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+const (
+	// keyPrefix marks gophermart API keys, e.g. for secret scanners.
+	keyPrefix = "gmk_"
+	// secretPrefix marks request signing secrets.
+	secretPrefix = "gms_"
+	// shownPrefixLen is the length of the key prefix kept to tell keys apart.
+	shownPrefixLen = len(keyPrefix) + 8
+)
+
+type keyRepo interface {
+	Save(ctx context.Context, key entity.APIKey) (entity.APIKey, error)
+	FindByHash(ctx context.Context, hash string) (entity.APIKey, error)
+	List(ctx context.Context) ([]entity.APIKey, error)
+	Revoke(ctx context.Context, id int64) error
+}
+
+type auditLog interface {
+	Record(ctx context.Context, entry entity.AuditEntry) error
+}
+
+// APIKeyService issues and checks the API keys of partner systems.
+type APIKeyService struct {
+	log   *logger.Logger
+	repo  keyRepo
+	audit auditLog
+}
+
+func NewAPIKeyService(log *logger.Logger, repo keyRepo, audit auditLog) *APIKeyService {
+	return &APIKeyService{
+		log:   log,
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+// Create issues a key with scopes and a secret to sign requests with. The
+// key is returned only here, it can not be recovered later; the secret is
+// set in the returned entity.APIKey.
+func (s *APIKeyService) Create(
+	ctx context.Context,
+	actorID int64,
+	name string,
+	scopes []entity.APIKeyScope,
+) (entity.APIKey, entity.Token, error) {
+	log := s.log.With("op", "APIKeyService.Create", "actor", actorID, "name", name)
+
+	token, err := randomToken(keyPrefix)
+	if err != nil {
+		log.Error(err)
+		return entity.APIKey{}, "", common.ErrInternalError
+	}
+	secret, err := randomToken(secretPrefix)
+	if err != nil {
+		log.Error(err)
+		return entity.APIKey{}, "", common.ErrInternalError
+	}
+
+	key, err := s.repo.Save(ctx, entity.APIKey{
+		Name:          name,
+		Prefix:        string(token[:shownPrefixLen]),
+		Hash:          hashKey(token),
+		Scopes:        scopes,
+		SigningSecret: string(secret),
+		CreatedBy:     actorID,
+	})
+	if err != nil {
+		log.Error(err)
+		return entity.APIKey{}, "", common.ErrInternalError
+	}
+
+	err = s.audit.Record(ctx, entity.AuditEntry{
+		ActorID: actorID,
+		Action:  entity.AuditAPIKeyCreated,
+		Target:  strconv.FormatInt(key.ID, 10),
+	})
+	if err != nil {
+		// The key has not been shown to anyone, so it is better revoked.
+		log.Error("failed to record audit entry", err)
+		if err := s.repo.Revoke(ctx, key.ID); err != nil {
+			log.Error("failed to revoke unaudited key", err)
+		}
+		return entity.APIKey{}, "", common.ErrInternalError
+	}
+	log.Infow("api key created", "key_id", key.ID, "prefix", key.Prefix)
+
+	return key, token, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]entity.APIKey, error) {
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Errorw("failed to list api keys", "op", "APIKeyService.List", "error", err)
+		return nil, common.ErrInternalError
+	}
+	return keys, nil
+}
+
+// Revoke revokes the key at once: requests with it are no longer accepted.
+func (s *APIKeyService) Revoke(ctx context.Context, actorID int64, id int64) error {
+	log := s.log.With("op", "APIKeyService.Revoke", "actor", actorID, "key_id", id)
+
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		if errors.Is(err, common.ErrAPIKeyNotFound) {
+			return common.ErrAPIKeyNotFound
+		}
+		log.Error(err)
+		return common.ErrInternalError
+	}
+
+	err := s.audit.Record(ctx, entity.AuditEntry{
+		ActorID: actorID,
+		Action:  entity.AuditAPIKeyRevoked,
+		Target:  strconv.FormatInt(id, 10),
+	})
+	if err != nil {
+		log.Error("failed to record audit entry", err)
+		return common.ErrInternalError
+	}
+	log.Info("api key revoked")
+
+	return nil
+}
+
+// Authenticate returns the active key matching token.
+func (s *APIKeyService) Authenticate(ctx context.Context, token entity.Token) (entity.APIKey, error) {
+	key, err := s.repo.FindByHash(ctx, hashKey(token))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidAPIKey) {
+			return entity.APIKey{}, common.ErrInvalidAPIKey
+		}
+		s.log.Errorw("failed to find api key", "op", "APIKeyService.Authenticate", "error", err)
+		return entity.APIKey{}, common.ErrInternalError
+	}
+	if key.RevokedAt != nil {
+		return entity.APIKey{}, common.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+func randomToken(prefix string) (entity.Token, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return entity.Token(prefix + base64.RawURLEncoding.EncodeToString(raw)), nil
+}
+
+func hashKey(token entity.Token) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,155 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyService_Create(t *testing.T) {
+	ctx := context.Background()
+	scopes := []entity.APIKeyScope{entity.ScopeOrdersWrite}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := NewMockkeyRepo(ctrl)
+		audit := NewMockauditLog(ctrl)
+
+		var saved entity.APIKey
+		repo.EXPECT().
+			Save(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, key entity.APIKey) (entity.APIKey, error) {
+				saved = key
+				key.ID = 3
+				return key, nil
+			})
+		audit.EXPECT().
+			Record(ctx, entity.AuditEntry{ActorID: 7, Action: entity.AuditAPIKeyCreated, Target: "3"}).
+			Return(nil)
+
+		s := NewAPIKeyService(logger.NewLogger(), repo, audit)
+		key, token, err := s.Create(ctx, 7, "pos", scopes)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), key.ID)
+		assert.True(t, strings.HasPrefix(string(token), keyPrefix))
+		assert.True(t, strings.HasPrefix(string(token), key.Prefix))
+		// Хранится только хеш ключа
+		assert.Equal(t, hashKey(token), saved.Hash)
+		assert.Equal(t, "pos", saved.Name)
+		assert.Equal(t, scopes, saved.Scopes)
+		assert.Equal(t, int64(7), saved.CreatedBy)
+		assert.True(t, strings.HasPrefix(saved.SigningSecret, secretPrefix))
+		assert.Equal(t, saved.SigningSecret, key.SigningSecret)
+	})
+
+	t.Run("audit error revokes the key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		repo := NewMockkeyRepo(ctrl)
+		audit := NewMockauditLog(ctrl)
+
+		repo.EXPECT().Save(ctx, gomock.Any()).Return(entity.APIKey{ID: 3}, nil)
+		audit.EXPECT().Record(ctx, gomock.Any()).Return(errors.New("db error"))
+		repo.EXPECT().Revoke(ctx, int64(3)).Return(nil)
+
+		s := NewAPIKeyService(logger.NewLogger(), repo, audit)
+		_, token, err := s.Create(ctx, 7, "pos", scopes)
+
+		assert.ErrorIs(t, err, common.ErrInternalError)
+		assert.Empty(t, token)
+	})
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	token := entity.Token("gmk_secret")
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name        string
+		key         entity.APIKey
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "active key", key: entity.APIKey{ID: 3}},
+		{name: "revoked key", key: entity.APIKey{ID: 3, RevokedAt: &revokedAt}, expectedErr: common.ErrInvalidAPIKey},
+		{name: "unknown key", repoErr: common.ErrInvalidAPIKey, expectedErr: common.ErrInvalidAPIKey},
+		{name: "repository error", repoErr: errors.New("db error"), expectedErr: common.ErrInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := NewMockkeyRepo(ctrl)
+			repo.EXPECT().FindByHash(ctx, hashKey(token)).Return(tt.key, tt.repoErr)
+
+			s := NewAPIKeyService(logger.NewLogger(), repo, NewMockauditLog(ctrl))
+			key, err := s.Authenticate(ctx, token)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				assert.Equal(t, tt.key, key)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		setupMocks  func(repo *MockkeyRepo, audit *MockauditLog)
+		expectedErr error
+	}{
+		{
+			name: "success",
+			setupMocks: func(repo *MockkeyRepo, audit *MockauditLog) {
+				repo.EXPECT().Revoke(ctx, int64(3)).Return(nil)
+				audit.EXPECT().
+					Record(ctx, entity.AuditEntry{ActorID: 7, Action: entity.AuditAPIKeyRevoked, Target: "3"}).
+					Return(nil)
+			},
+		},
+		{
+			name: "unknown or revoked key",
+			setupMocks: func(repo *MockkeyRepo, audit *MockauditLog) {
+				repo.EXPECT().Revoke(ctx, int64(3)).Return(common.ErrAPIKeyNotFound)
+			},
+			expectedErr: common.ErrAPIKeyNotFound,
+		},
+		{
+			name: "repository error",
+			setupMocks: func(repo *MockkeyRepo, audit *MockauditLog) {
+				repo.EXPECT().Revoke(ctx, int64(3)).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := NewMockkeyRepo(ctrl)
+			audit := NewMockauditLog(ctrl)
+			tt.setupMocks(repo, audit)
+
+			s := NewAPIKeyService(logger.NewLogger(), repo, audit)
+			err := s.Revoke(ctx, 7, 3)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
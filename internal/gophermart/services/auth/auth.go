@@ -2,14 +2,16 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/MxTrap/gophermart/logger"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 type userFinder interface {
@@ -21,39 +23,82 @@ type userSaver interface {
 	SaveUser(ctx context.Context, user entity.User) (uid int64, err error)
 }
 
+type userUpdater interface {
+	UpdatePassword(ctx context.Context, userID int64, password string) error
+}
+
 type userRepo interface {
 	userSaver
 	userFinder
+	userUpdater
+}
+
+type passwordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, encoded string) (ok bool, rehash bool, err error)
 }
 
 type jwtService interface {
 	GenerateAccessToken(user entity.User, ttl time.Duration) (entity.Token, error)
 }
 
+type refreshTokenRepo interface {
+	Save(ctx context.Context, token entity.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (entity.RefreshToken, error)
+	Rotate(ctx context.Context, id int64, next entity.RefreshToken) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUser(ctx context.Context, userID int64) error
+}
+
+type tokenRevoker interface {
+	RevokeToken(ctx context.Context, claims entity.AccessClaims) error
+	RevokeUser(ctx context.Context, userID int64) error
+}
+
+type loginGuard interface {
+	Reserve(ctx context.Context, login string, ip string) error
+	Succeed(ctx context.Context, login string, ip string) error
+}
+
 type AuthService struct {
-	log      *logger.Logger
-	userRepo userRepo
-	jwtSvc   jwtService
-	tokenTTL time.Duration
+	log        *logger.Logger
+	userRepo   userRepo
+	hasher     passwordHasher
+	tokenRepo  refreshTokenRepo
+	revoker    tokenRevoker
+	guard      loginGuard
+	jwtSvc     jwtService
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
 func NewAuthService(
 	logger *logger.Logger,
 	userRepo userRepo,
+	hasher passwordHasher,
+	tokenRepo refreshTokenRepo,
+	revoker tokenRevoker,
+	guard loginGuard,
 	jwtSvc jwtService,
-	tokenTTL time.Duration,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
 ) *AuthService {
 	return &AuthService{
-		log:      logger,
-		userRepo: userRepo,
-		jwtSvc:   jwtSvc,
-		tokenTTL: tokenTTL,
+		log:        logger,
+		userRepo:   userRepo,
+		hasher:     hasher,
+		tokenRepo:  tokenRepo,
+		revoker:    revoker,
+		guard:      guard,
+		jwtSvc:     jwtSvc,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
 	}
 }
 
-func (s *AuthService) RegisterNewUser(ctx context.Context, user entity.User) (entity.Token, error) {
+func (s *AuthService) RegisterNewUser(ctx context.Context, user entity.User) (entity.TokenPair, error) {
 	log := s.log.With("op", "AuthService.RegisterNewUser", "login", user.Login)
-	var token entity.Token
+	var token entity.TokenPair
 
 	existingUser, err := s.userRepo.FindUserByUsername(ctx, user.Login)
 	if err != nil && !errors.Is(err, common.ErrUserNotFound) {
@@ -64,7 +109,7 @@ func (s *AuthService) RegisterNewUser(ctx context.Context, user entity.User) (en
 		return token, common.ErrUserAlreadyExist
 	}
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	passHash, err := s.hasher.Hash(user.Password)
 
 	if err != nil {
 		log.Error("failed to generate password hash: ", err)
@@ -72,7 +117,8 @@ func (s *AuthService) RegisterNewUser(ctx context.Context, user entity.User) (en
 		return token, common.ErrInternalError
 	}
 
-	user.Password = string(passHash)
+	user.Password = passHash
+	user.Role = entity.RoleUser
 	id, err := s.userRepo.SaveUser(ctx, user)
 
 	if err != nil {
@@ -80,7 +126,7 @@ func (s *AuthService) RegisterNewUser(ctx context.Context, user entity.User) (en
 		return token, common.ErrInternalError
 	}
 	user.ID = id
-	token, err = s.jwtSvc.GenerateAccessToken(user, s.tokenTTL)
+	token, err = s.issueTokens(ctx, user)
 	if err != nil {
 		log.Error("failed to generate tokens", err)
 		return token, err
@@ -89,10 +135,22 @@ func (s *AuthService) RegisterNewUser(ctx context.Context, user entity.User) (en
 	return token, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, user entity.User) (entity.Token, error) {
-	log := s.log.With("op", "AuthService.Login", "login", user.Login)
+// Login checks the credentials of the user logging in from ip. Attempts are
+// throttled: while the login or the ip is locked, it returns a
+// *common.TooManyRequestsError without checking the password.
+func (s *AuthService) Login(ctx context.Context, user entity.User, ip string) (entity.TokenPair, error) {
+	log := s.log.With("op", "AuthService.Login", "login", user.Login, "ip", ip)
+
+	var token entity.TokenPair
 
-	var token entity.Token
+	if err := s.guard.Reserve(ctx, user.Login, ip); err != nil {
+		if errors.Is(err, common.ErrTooManyRequests) {
+			log.Info("login is locked")
+			return token, err
+		}
+		log.Error(err)
+		return token, common.ErrInternalError
+	}
 
 	existingUser, err := s.userRepo.FindUserByUsername(ctx, user.Login)
 
@@ -106,13 +164,24 @@ func (s *AuthService) Login(ctx context.Context, user entity.User) (entity.Token
 		return token, common.ErrInternalError
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(existingUser.Password), []byte(user.Password)); err != nil {
-		log.Info("invalid password", err)
-
+	ok, rehash, err := s.hasher.Verify(user.Password, existingUser.Password)
+	if err != nil {
+		log.Error("failed to verify password", err)
+		return token, common.ErrInternalError
+	}
+	if !ok {
+		log.Info("invalid password")
 		return token, common.ErrInvalidCredentials
 	}
 
-	token, err = s.jwtSvc.GenerateAccessToken(existingUser, s.tokenTTL)
+	if err := s.guard.Succeed(ctx, user.Login, ip); err != nil {
+		log.Error("failed to release login attempt", err)
+	}
+	if rehash {
+		s.rehash(ctx, existingUser.ID, user.Password)
+	}
+
+	token, err = s.issueTokens(ctx, existingUser)
 	if err != nil {
 		log.Error("failed to generate tokens", err)
 		return token, common.ErrInternalError
@@ -120,3 +189,180 @@ func (s *AuthService) Login(ctx context.Context, user entity.User) (entity.Token
 
 	return token, nil
 }
+
+// rehash replaces an outdated password hash while the plain password is at
+// hand. The user has logged in anyway, so an error is only logged.
+func (s *AuthService) rehash(ctx context.Context, userID int64, password string) {
+	log := s.log.With("op", "AuthService.rehash", "uid", userID)
+
+	passHash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Error("failed to generate password hash", err)
+		return
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, passHash); err != nil {
+		log.Error("failed to update password hash", err)
+		return
+	}
+	log.Info("password hash upgraded")
+}
+
+// Refresh exchanges a refresh token for a new token pair. Every refresh token
+// is accepted once: if a used token is presented again, it has been leaked,
+// and all tokens of its family are revoked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken entity.Token) (entity.TokenPair, error) {
+	log := s.log.With("op", "AuthService.Refresh")
+
+	var token entity.TokenPair
+
+	stored, err := s.tokenRepo.FindByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidToken) {
+			return token, common.ErrInvalidToken
+		}
+		log.Error(err)
+		return token, common.ErrInternalError
+	}
+	log = log.With("uid", stored.UserID, "family", stored.FamilyID)
+
+	if stored.Used() {
+		return token, s.revokeFamily(ctx, stored)
+	}
+	if !time.Now().Before(stored.ExpiresAt) {
+		return token, common.ErrTokenHasExpired
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, stored.UserID)
+	if err != nil {
+		log.Error("failed to find token owner", err)
+		return token, common.ErrInternalError
+	}
+
+	access, err := s.jwtSvc.GenerateAccessToken(user, s.accessTTL)
+	if err != nil {
+		log.Error("failed to generate access token", err)
+		return token, common.ErrInternalError
+	}
+
+	refresh, next, err := s.newRefreshToken(user.ID, stored.FamilyID)
+	if err != nil {
+		log.Error("failed to generate refresh token", err)
+		return token, common.ErrInternalError
+	}
+	rotated, err := s.tokenRepo.Rotate(ctx, stored.ID, next)
+	if err != nil {
+		log.Error("failed to rotate refresh token", err)
+		return token, common.ErrInternalError
+	}
+	if !rotated {
+		// The token has been used concurrently.
+		return token, s.revokeFamily(ctx, stored)
+	}
+
+	return entity.TokenPair{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+// Logout revokes the access token the user is logged in with and, if given,
+// the refresh token issued together with it.
+func (s *AuthService) Logout(ctx context.Context, claims entity.AccessClaims, refreshToken entity.Token) error {
+	log := s.log.With("op", "AuthService.Logout", "uid", claims.UserID)
+
+	if err := s.revoker.RevokeToken(ctx, claims); err != nil {
+		return common.ErrInternalError
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.tokenRepo.FindByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidToken) {
+			return nil
+		}
+		log.Error(err)
+		return common.ErrInternalError
+	}
+	// A user can not log out someone else's session.
+	if stored.UserID != claims.UserID {
+		return nil
+	}
+	if err := s.tokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		log.Error("failed to revoke token family", err)
+		return common.ErrInternalError
+	}
+
+	return nil
+}
+
+// LogoutAll revokes all access and refresh tokens of the user, so that every
+// session has to log in again.
+func (s *AuthService) LogoutAll(ctx context.Context, userID int64) error {
+	log := s.log.With("op", "AuthService.LogoutAll", "uid", userID)
+
+	if err := s.tokenRepo.RevokeUser(ctx, userID); err != nil {
+		log.Error("failed to revoke refresh tokens", err)
+		return common.ErrInternalError
+	}
+	if err := s.revoker.RevokeUser(ctx, userID); err != nil {
+		return common.ErrInternalError
+	}
+
+	return nil
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, reused entity.RefreshToken) error {
+	log := s.log.With("op", "AuthService.revokeFamily", "uid", reused.UserID, "family", reused.FamilyID)
+	log.Warn("refresh token reused, revoking the token family")
+	if err := s.tokenRepo.RevokeFamily(ctx, reused.FamilyID); err != nil {
+		log.Error("failed to revoke token family", err)
+		return common.ErrInternalError
+	}
+	return common.ErrTokenReused
+}
+
+// issueTokens issues an access token and the first refresh token of a new
+// family.
+func (s *AuthService) issueTokens(ctx context.Context, user entity.User) (entity.TokenPair, error) {
+	var token entity.TokenPair
+
+	access, err := s.jwtSvc.GenerateAccessToken(user, s.accessTTL)
+	if err != nil {
+		return token, err
+	}
+
+	family := make([]byte, 16)
+	if _, err := rand.Read(family); err != nil {
+		return token, err
+	}
+	refresh, stored, err := s.newRefreshToken(user.ID, hex.EncodeToString(family))
+	if err != nil {
+		return token, err
+	}
+	if err := s.tokenRepo.Save(ctx, stored); err != nil {
+		return token, err
+	}
+
+	return entity.TokenPair{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+// newRefreshToken generates an opaque refresh token and the record to store
+// for it.
+func (s *AuthService) newRefreshToken(userID int64, familyID string) (entity.Token, entity.RefreshToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", entity.RefreshToken{}, err
+	}
+	token := entity.Token(base64.RawURLEncoding.EncodeToString(raw))
+
+	return token, entity.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		Hash:      hashToken(token),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}, nil
+}
+
+func hashToken(token entity.Token) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
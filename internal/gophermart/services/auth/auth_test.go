@@ -5,25 +5,38 @@ import (
 	"errors"
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/hasher"
 	"github.com/MxTrap/gophermart/logger"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 	"testing"
 	"time"
 )
 
+func newTestHasher(t *testing.T) *hasher.PasswordHasher {
+	h, err := hasher.NewPasswordHasher(hasher.Argon2Params{Memory: 64, Time: 1, Threads: 1})
+	assert.NoError(t, err)
+	return h
+}
+
 func TestAuthService_RegisterNewUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserRepo := NewMockuserRepo(ctrl)
 	mockJwtService := NewMockjwtService(ctrl)
+	mockTokenRepo := NewMockrefreshTokenRepo(ctrl)
+	mockRevoker := NewMocktokenRevoker(ctrl)
+	mockGuard := NewMockloginGuard(ctrl)
 	log := logger.NewLogger()
-	tokenTTL := 24 * time.Hour
+	tokenTTL := 15 * time.Minute
+	refreshTTL := 24 * time.Hour
 	ctx := context.Background()
+	passwordHasher := newTestHasher(t)
 
-	authService := NewAuthService(log, mockUserRepo, mockJwtService, tokenTTL)
+	authService := NewAuthService(log, mockUserRepo, passwordHasher, mockTokenRepo, mockRevoker, mockGuard, mockJwtService, tokenTTL, refreshTTL)
 
 	user := entity.User{
 		Login:    "testuser",
@@ -41,6 +54,8 @@ func TestAuthService_RegisterNewUser(t *testing.T) {
 			SaveUser(ctx, gomock.Any()).
 			DoAndReturn(func(_ context.Context, u entity.User) (int64, error) {
 				assert.NotEqual(t, user.Password, u.Password) // Пароль должен быть хеширован
+				assert.True(t, strings.HasPrefix(u.Password, "$argon2id$"))
+				assert.Equal(t, entity.RoleUser, u.Role)
 				return userID, nil
 			})
 
@@ -52,9 +67,23 @@ func TestAuthService_RegisterNewUser(t *testing.T) {
 				return token, nil
 			})
 
+		var saved entity.RefreshToken
+		mockTokenRepo.EXPECT().
+			Save(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, rt entity.RefreshToken) error {
+				saved = rt
+				return nil
+			})
+
 		resultToken, err := authService.RegisterNewUser(ctx, user)
 		assert.NoError(t, err)
-		assert.Equal(t, token, resultToken)
+		assert.Equal(t, token, resultToken.AccessToken)
+		assert.Equal(t, userID, saved.UserID)
+		assert.Len(t, saved.FamilyID, 32)
+		// Хранится только хеш refresh-токена
+		assert.Equal(t, hashToken(resultToken.RefreshToken), saved.Hash)
+		assert.NotEqual(t, string(resultToken.RefreshToken), saved.Hash)
+		assert.WithinDuration(t, time.Now().Add(refreshTTL), saved.ExpiresAt, 5*time.Second)
 	})
 
 	t.Run("user already exists", func(t *testing.T) {
@@ -65,7 +94,7 @@ func TestAuthService_RegisterNewUser(t *testing.T) {
 
 		resultToken, err := authService.RegisterNewUser(ctx, user)
 		assert.ErrorIs(t, err, common.ErrUserAlreadyExist)
-		assert.Equal(t, entity.Token(""), resultToken)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
 	})
 
 	t.Run("find user error", func(t *testing.T) {
@@ -76,7 +105,7 @@ func TestAuthService_RegisterNewUser(t *testing.T) {
 
 		resultToken, err := authService.RegisterNewUser(ctx, user)
 		assert.ErrorIs(t, err, common.ErrInternalError)
-		assert.Equal(t, entity.Token(""), resultToken)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
 	})
 
 	t.Run("save user error", func(t *testing.T) {
@@ -91,7 +120,7 @@ func TestAuthService_RegisterNewUser(t *testing.T) {
 
 		resultToken, err := authService.RegisterNewUser(ctx, user)
 		assert.ErrorIs(t, err, common.ErrInternalError)
-		assert.Equal(t, entity.Token(""), resultToken)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
 	})
 
 	t.Run("generate token error", func(t *testing.T) {
@@ -110,7 +139,7 @@ func TestAuthService_RegisterNewUser(t *testing.T) {
 
 		resultToken, err := authService.RegisterNewUser(ctx, user)
 		assert.ErrorIs(t, err, tokenErr)
-		assert.Equal(t, entity.Token(""), resultToken)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
 	})
 }
 
@@ -120,11 +149,16 @@ func TestAuthService_Login(t *testing.T) {
 
 	mockUserRepo := NewMockuserRepo(ctrl)
 	mockJwtService := NewMockjwtService(ctrl)
+	mockTokenRepo := NewMockrefreshTokenRepo(ctrl)
+	mockRevoker := NewMocktokenRevoker(ctrl)
+	mockGuard := NewMockloginGuard(ctrl)
 	log := logger.NewLogger()
-	tokenTTL := 24 * time.Hour
+	tokenTTL := 15 * time.Minute
+	refreshTTL := 24 * time.Hour
 	ctx := context.Background()
+	passwordHasher := newTestHasher(t)
 
-	authService := NewAuthService(log, mockUserRepo, mockJwtService, tokenTTL)
+	authService := NewAuthService(log, mockUserRepo, passwordHasher, mockTokenRepo, mockRevoker, mockGuard, mockJwtService, tokenTTL, refreshTTL)
 
 	user := entity.User{
 		Login:    "testuser",
@@ -132,18 +166,21 @@ func TestAuthService_Login(t *testing.T) {
 	}
 	userID := int64(1)
 	token := entity.Token("jwt-token")
+	ip := "192.0.2.1"
 
 	// Подготовка хешированного пароля
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := passwordHasher.Hash(user.Password)
 	assert.NoError(t, err)
 
 	existingUser := entity.User{
 		ID:       userID,
 		Login:    user.Login,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 	}
 
 	t.Run("successful login", func(t *testing.T) {
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
+		mockGuard.EXPECT().Succeed(ctx, user.Login, ip).Return(nil)
 		mockUserRepo.EXPECT().
 			FindUserByUsername(ctx, user.Login).
 			Return(existingUser, nil)
@@ -152,22 +189,104 @@ func TestAuthService_Login(t *testing.T) {
 			GenerateAccessToken(existingUser, tokenTTL).
 			Return(token, nil)
 
-		resultToken, err := authService.Login(ctx, user)
+		mockTokenRepo.EXPECT().
+			Save(ctx, gomock.Any()).
+			Return(nil)
+
+		resultToken, err := authService.Login(ctx, user, ip)
+		assert.NoError(t, err)
+		assert.Equal(t, token, resultToken.AccessToken)
+		assert.NotEmpty(t, resultToken.RefreshToken)
+	})
+
+	t.Run("legacy bcrypt hash is upgraded", func(t *testing.T) {
+		bcryptHash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.MinCost)
+		assert.NoError(t, err)
+		legacyUser := existingUser
+		legacyUser.Password = string(bcryptHash)
+
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
+		mockGuard.EXPECT().Succeed(ctx, user.Login, ip).Return(nil)
+		mockUserRepo.EXPECT().
+			FindUserByUsername(ctx, user.Login).
+			Return(legacyUser, nil)
+		mockUserRepo.EXPECT().
+			UpdatePassword(ctx, userID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int64, hash string) error {
+				ok, rehash, err := passwordHasher.Verify(user.Password, hash)
+				assert.NoError(t, err)
+				assert.True(t, ok)
+				assert.False(t, rehash)
+				return nil
+			})
+		mockJwtService.EXPECT().
+			GenerateAccessToken(legacyUser, tokenTTL).
+			Return(token, nil)
+		mockTokenRepo.EXPECT().
+			Save(ctx, gomock.Any()).
+			Return(nil)
+
+		resultToken, err := authService.Login(ctx, user, ip)
 		assert.NoError(t, err)
-		assert.Equal(t, token, resultToken)
+		assert.Equal(t, token, resultToken.AccessToken)
+	})
+
+	t.Run("outdated parameters rehash error", func(t *testing.T) {
+		// Ошибка обновления хеша не мешает входу
+		weakHasher, err := hasher.NewPasswordHasher(hasher.Argon2Params{Memory: 8, Time: 1, Threads: 1})
+		assert.NoError(t, err)
+		weakHash, err := weakHasher.Hash(user.Password)
+		assert.NoError(t, err)
+		outdatedUser := existingUser
+		outdatedUser.Password = weakHash
+
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
+		mockGuard.EXPECT().Succeed(ctx, user.Login, ip).Return(nil)
+		mockUserRepo.EXPECT().
+			FindUserByUsername(ctx, user.Login).
+			Return(outdatedUser, nil)
+		mockUserRepo.EXPECT().
+			UpdatePassword(ctx, userID, gomock.Any()).
+			Return(errors.New("database error"))
+		mockJwtService.EXPECT().
+			GenerateAccessToken(outdatedUser, tokenTTL).
+			Return(token, nil)
+		mockTokenRepo.EXPECT().
+			Save(ctx, gomock.Any()).
+			Return(nil)
+
+		resultToken, err := authService.Login(ctx, user, ip)
+		assert.NoError(t, err)
+		assert.Equal(t, token, resultToken.AccessToken)
+	})
+
+	t.Run("malformed password hash", func(t *testing.T) {
+		brokenUser := existingUser
+		brokenUser.Password = "plaintext"
+
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
+		mockUserRepo.EXPECT().
+			FindUserByUsername(ctx, user.Login).
+			Return(brokenUser, nil)
+
+		resultToken, err := authService.Login(ctx, user, ip)
+		assert.ErrorIs(t, err, common.ErrInternalError)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
 	})
 
 	t.Run("user not found", func(t *testing.T) {
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
 		mockUserRepo.EXPECT().
 			FindUserByUsername(ctx, user.Login).
 			Return(entity.User{}, common.ErrUserNotFound)
 
-		resultToken, err := authService.Login(ctx, user)
+		resultToken, err := authService.Login(ctx, user, ip)
 		assert.ErrorIs(t, err, common.ErrInvalidCredentials)
-		assert.Equal(t, entity.Token(""), resultToken)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
 	})
 
 	t.Run("invalid password", func(t *testing.T) {
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
 		mockUserRepo.EXPECT().
 			FindUserByUsername(ctx, user.Login).
 			Return(existingUser, nil)
@@ -177,23 +296,26 @@ func TestAuthService_Login(t *testing.T) {
 			Password: "wrongpassword",
 		}
 
-		resultToken, err := authService.Login(ctx, invalidUser)
+		resultToken, err := authService.Login(ctx, invalidUser, ip)
 		assert.ErrorIs(t, err, common.ErrInvalidCredentials)
-		assert.Equal(t, entity.Token(""), resultToken)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
 	})
 
 	t.Run("database error", func(t *testing.T) {
 		dbErr := errors.New("database error")
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
 		mockUserRepo.EXPECT().
 			FindUserByUsername(ctx, user.Login).
 			Return(entity.User{}, dbErr)
 
-		resultToken, err := authService.Login(ctx, user)
+		resultToken, err := authService.Login(ctx, user, ip)
 		assert.ErrorIs(t, err, common.ErrInternalError)
-		assert.Equal(t, entity.Token(""), resultToken)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
 	})
 
 	t.Run("generate token error", func(t *testing.T) {
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
+		mockGuard.EXPECT().Succeed(ctx, user.Login, ip).Return(nil)
 		mockUserRepo.EXPECT().
 			FindUserByUsername(ctx, user.Login).
 			Return(existingUser, nil)
@@ -203,8 +325,244 @@ func TestAuthService_Login(t *testing.T) {
 			GenerateAccessToken(existingUser, tokenTTL).
 			Return(entity.Token(""), tokenErr)
 
-		resultToken, err := authService.Login(ctx, user)
+		resultToken, err := authService.Login(ctx, user, ip)
 		assert.ErrorIs(t, err, common.ErrInternalError)
-		assert.Equal(t, entity.Token(""), resultToken)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
+	})
+
+	t.Run("login locked", func(t *testing.T) {
+		// Пароль не проверяется, пока логин заблокирован
+		lockErr := &common.TooManyRequestsError{RetryAfter: time.Minute}
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(lockErr)
+
+		resultToken, err := authService.Login(ctx, user, ip)
+		assert.ErrorIs(t, err, common.ErrTooManyRequests)
+		var tooManyRequests *common.TooManyRequestsError
+		assert.ErrorAs(t, err, &tooManyRequests)
+		assert.Equal(t, time.Minute, tooManyRequests.RetryAfter)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
+	})
+
+	t.Run("lockout check error", func(t *testing.T) {
+		mockGuard.EXPECT().Reserve(ctx, user.Login, ip).Return(errors.New("database error"))
+
+		resultToken, err := authService.Login(ctx, user, ip)
+		assert.ErrorIs(t, err, common.ErrInternalError)
+		assert.Equal(t, entity.TokenPair{}, resultToken)
+	})
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	tokenTTL := 15 * time.Minute
+	refreshTTL := 24 * time.Hour
+
+	refreshToken := entity.Token("refresh-token")
+	accessToken := entity.Token("jwt-token")
+	user := entity.User{ID: 1, Login: "testuser"}
+	rotatedAt := time.Now().Add(-time.Minute)
+
+	valid := entity.RefreshToken{
+		ID:        10,
+		UserID:    user.ID,
+		FamilyID:  "family",
+		Hash:      hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	rotated := valid
+	rotated.RotatedAt = &rotatedAt
+	expired := valid
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name        string
+		setupMocks  func(users *MockuserRepo, jwt *MockjwtService, tokens *MockrefreshTokenRepo)
+		expectedErr error
+	}{
+		{
+			name: "successful rotation",
+			setupMocks: func(users *MockuserRepo, jwt *MockjwtService, tokens *MockrefreshTokenRepo) {
+				tokens.EXPECT().FindByHash(ctx, valid.Hash).Return(valid, nil)
+				users.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil)
+				jwt.EXPECT().GenerateAccessToken(user, tokenTTL).Return(accessToken, nil)
+				tokens.EXPECT().
+					Rotate(ctx, valid.ID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, next entity.RefreshToken) (bool, error) {
+						assert.Equal(t, valid.FamilyID, next.FamilyID)
+						assert.NotEqual(t, valid.Hash, next.Hash)
+						return true, nil
+					})
+			},
+		},
+		{
+			name: "unknown token",
+			setupMocks: func(_ *MockuserRepo, _ *MockjwtService, tokens *MockrefreshTokenRepo) {
+				tokens.EXPECT().FindByHash(ctx, valid.Hash).Return(entity.RefreshToken{}, common.ErrInvalidToken)
+			},
+			expectedErr: common.ErrInvalidToken,
+		},
+		{
+			name: "expired token",
+			setupMocks: func(_ *MockuserRepo, _ *MockjwtService, tokens *MockrefreshTokenRepo) {
+				tokens.EXPECT().FindByHash(ctx, valid.Hash).Return(expired, nil)
+			},
+			expectedErr: common.ErrTokenHasExpired,
+		},
+		{
+			// Повторное использование токена отзывает всё семейство
+			name: "reused token",
+			setupMocks: func(_ *MockuserRepo, _ *MockjwtService, tokens *MockrefreshTokenRepo) {
+				tokens.EXPECT().FindByHash(ctx, valid.Hash).Return(rotated, nil)
+				tokens.EXPECT().RevokeFamily(ctx, valid.FamilyID).Return(nil)
+			},
+			expectedErr: common.ErrTokenReused,
+		},
+		{
+			name: "concurrently rotated token",
+			setupMocks: func(users *MockuserRepo, jwt *MockjwtService, tokens *MockrefreshTokenRepo) {
+				tokens.EXPECT().FindByHash(ctx, valid.Hash).Return(valid, nil)
+				users.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil)
+				jwt.EXPECT().GenerateAccessToken(user, tokenTTL).Return(accessToken, nil)
+				tokens.EXPECT().Rotate(ctx, valid.ID, gomock.Any()).Return(false, nil)
+				tokens.EXPECT().RevokeFamily(ctx, valid.FamilyID).Return(nil)
+			},
+			expectedErr: common.ErrTokenReused,
+		},
+		{
+			name: "database error",
+			setupMocks: func(_ *MockuserRepo, _ *MockjwtService, tokens *MockrefreshTokenRepo) {
+				tokens.EXPECT().FindByHash(ctx, valid.Hash).Return(entity.RefreshToken{}, errors.New("database error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUserRepo := NewMockuserRepo(ctrl)
+			mockJwtService := NewMockjwtService(ctrl)
+			mockTokenRepo := NewMockrefreshTokenRepo(ctrl)
+			tt.setupMocks(mockUserRepo, mockJwtService, mockTokenRepo)
+
+			authService := NewAuthService(logger.NewLogger(), mockUserRepo, nil, mockTokenRepo, nil, nil, mockJwtService, tokenTTL, refreshTTL)
+			result, err := authService.Refresh(ctx, refreshToken)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Equal(t, entity.TokenPair{}, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, accessToken, result.AccessToken)
+			assert.NotEmpty(t, result.RefreshToken)
+			assert.NotEqual(t, refreshToken, result.RefreshToken)
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctx := context.Background()
+	claims := entity.AccessClaims{ID: "jti", UserID: 1}
+	refreshToken := entity.Token("refresh-token")
+	stored := entity.RefreshToken{ID: 10, UserID: 1, FamilyID: "family", Hash: hashToken(refreshToken)}
+
+	tests := []struct {
+		name         string
+		refreshToken entity.Token
+		setupMocks   func(tokens *MockrefreshTokenRepo, revoker *MocktokenRevoker)
+		expectedErr  error
+	}{
+		{
+			name: "access token only",
+			setupMocks: func(_ *MockrefreshTokenRepo, revoker *MocktokenRevoker) {
+				revoker.EXPECT().RevokeToken(ctx, claims).Return(nil)
+			},
+		},
+		{
+			name:         "with refresh token",
+			refreshToken: refreshToken,
+			setupMocks: func(tokens *MockrefreshTokenRepo, revoker *MocktokenRevoker) {
+				revoker.EXPECT().RevokeToken(ctx, claims).Return(nil)
+				tokens.EXPECT().FindByHash(ctx, stored.Hash).Return(stored, nil)
+				tokens.EXPECT().RevokeFamily(ctx, stored.FamilyID).Return(nil)
+			},
+		},
+		{
+			name:         "unknown refresh token",
+			refreshToken: refreshToken,
+			setupMocks: func(tokens *MockrefreshTokenRepo, revoker *MocktokenRevoker) {
+				revoker.EXPECT().RevokeToken(ctx, claims).Return(nil)
+				tokens.EXPECT().FindByHash(ctx, stored.Hash).Return(entity.RefreshToken{}, common.ErrInvalidToken)
+			},
+		},
+		{
+			// Чужой refresh-токен не отзывается
+			name:         "refresh token of another user",
+			refreshToken: refreshToken,
+			setupMocks: func(tokens *MockrefreshTokenRepo, revoker *MocktokenRevoker) {
+				other := stored
+				other.UserID = 2
+				revoker.EXPECT().RevokeToken(ctx, claims).Return(nil)
+				tokens.EXPECT().FindByHash(ctx, stored.Hash).Return(other, nil)
+			},
+		},
+		{
+			name: "revocation error",
+			setupMocks: func(_ *MockrefreshTokenRepo, revoker *MocktokenRevoker) {
+				revoker.EXPECT().RevokeToken(ctx, claims).Return(errors.New("database error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTokenRepo := NewMockrefreshTokenRepo(ctrl)
+			mockRevoker := NewMocktokenRevoker(ctrl)
+			tt.setupMocks(mockTokenRepo, mockRevoker)
+
+			authService := NewAuthService(logger.NewLogger(), nil, nil, mockTokenRepo, mockRevoker, nil, nil, time.Minute, time.Hour)
+			err := authService.Logout(ctx, claims, tt.refreshToken)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthService_LogoutAll(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all tokens revoked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockTokenRepo := NewMockrefreshTokenRepo(ctrl)
+		mockRevoker := NewMocktokenRevoker(ctrl)
+		mockTokenRepo.EXPECT().RevokeUser(ctx, int64(1)).Return(nil)
+		mockRevoker.EXPECT().RevokeUser(ctx, int64(1)).Return(nil)
+
+		authService := NewAuthService(logger.NewLogger(), nil, nil, mockTokenRepo, mockRevoker, nil, nil, time.Minute, time.Hour)
+		assert.NoError(t, authService.LogoutAll(ctx, 1))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockTokenRepo := NewMockrefreshTokenRepo(ctrl)
+		mockTokenRepo.EXPECT().RevokeUser(ctx, int64(1)).Return(errors.New("database error"))
+
+		authService := NewAuthService(logger.NewLogger(), nil, nil, mockTokenRepo, nil, nil, nil, time.Minute, time.Hour)
+		assert.ErrorIs(t, authService.LogoutAll(ctx, 1), common.ErrInternalError)
 	})
 }
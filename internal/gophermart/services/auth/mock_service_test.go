@@ -81,6 +81,20 @@ func (mr *MockuserRepoMockRecorder) SaveUser(ctx, user interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockuserRepo)(nil).SaveUser), ctx, user)
 }
 
+// UpdatePassword mocks base method.
+func (m *MockuserRepo) UpdatePassword(ctx context.Context, userID int64, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockuserRepoMockRecorder) UpdatePassword(ctx, userID, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockuserRepo)(nil).UpdatePassword), ctx, userID, password)
+}
+
 // MockjwtService is a mock of jwtService interface.
 type MockjwtService struct {
 	ctrl     *gomock.Controller
@@ -118,3 +132,200 @@ func (mr *MockjwtServiceMockRecorder) GenerateAccessToken(user, ttl interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAccessToken", reflect.TypeOf((*MockjwtService)(nil).GenerateAccessToken), user, ttl)
 }
+
+// MockrefreshTokenRepo is a mock of refreshTokenRepo interface.
+type MockrefreshTokenRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockrefreshTokenRepoMockRecorder
+}
+
+// MockrefreshTokenRepoMockRecorder is the mock recorder for MockrefreshTokenRepo.
+type MockrefreshTokenRepoMockRecorder struct {
+	mock *MockrefreshTokenRepo
+}
+
+// NewMockrefreshTokenRepo creates a new mock instance.
+func NewMockrefreshTokenRepo(ctrl *gomock.Controller) *MockrefreshTokenRepo {
+	mock := &MockrefreshTokenRepo{ctrl: ctrl}
+	mock.recorder = &MockrefreshTokenRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrefreshTokenRepo) EXPECT() *MockrefreshTokenRepoMockRecorder {
+	return m.recorder
+}
+
+// FindByHash mocks base method.
+func (m *MockrefreshTokenRepo) FindByHash(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHash", ctx, hash)
+	ret0, _ := ret[0].(entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHash indicates an expected call of FindByHash.
+func (mr *MockrefreshTokenRepoMockRecorder) FindByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHash", reflect.TypeOf((*MockrefreshTokenRepo)(nil).FindByHash), ctx, hash)
+}
+
+// RevokeFamily mocks base method.
+func (m *MockrefreshTokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockrefreshTokenRepoMockRecorder) RevokeFamily(ctx, familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockrefreshTokenRepo)(nil).RevokeFamily), ctx, familyID)
+}
+
+// RevokeUser mocks base method.
+func (m *MockrefreshTokenRepo) RevokeUser(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser.
+func (mr *MockrefreshTokenRepoMockRecorder) RevokeUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*MockrefreshTokenRepo)(nil).RevokeUser), ctx, userID)
+}
+
+// Rotate mocks base method.
+func (m *MockrefreshTokenRepo) Rotate(ctx context.Context, id int64, next entity.RefreshToken) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, id, next)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockrefreshTokenRepoMockRecorder) Rotate(ctx, id, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockrefreshTokenRepo)(nil).Rotate), ctx, id, next)
+}
+
+// Save mocks base method.
+func (m *MockrefreshTokenRepo) Save(ctx context.Context, token entity.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockrefreshTokenRepoMockRecorder) Save(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockrefreshTokenRepo)(nil).Save), ctx, token)
+}
+
+// MocktokenRevoker is a mock of tokenRevoker interface.
+type MocktokenRevoker struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenRevokerMockRecorder
+}
+
+// MocktokenRevokerMockRecorder is the mock recorder for MocktokenRevoker.
+type MocktokenRevokerMockRecorder struct {
+	mock *MocktokenRevoker
+}
+
+// NewMocktokenRevoker creates a new mock instance.
+func NewMocktokenRevoker(ctrl *gomock.Controller) *MocktokenRevoker {
+	mock := &MocktokenRevoker{ctrl: ctrl}
+	mock.recorder = &MocktokenRevokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenRevoker) EXPECT() *MocktokenRevokerMockRecorder {
+	return m.recorder
+}
+
+// RevokeToken mocks base method.
+func (m *MocktokenRevoker) RevokeToken(ctx context.Context, claims entity.AccessClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MocktokenRevokerMockRecorder) RevokeToken(ctx, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MocktokenRevoker)(nil).RevokeToken), ctx, claims)
+}
+
+// RevokeUser mocks base method.
+func (m *MocktokenRevoker) RevokeUser(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser.
+func (mr *MocktokenRevokerMockRecorder) RevokeUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*MocktokenRevoker)(nil).RevokeUser), ctx, userID)
+}
+
+// MockloginGuard is a mock of loginGuard interface.
+type MockloginGuard struct {
+	ctrl     *gomock.Controller
+	recorder *MockloginGuardMockRecorder
+}
+
+// MockloginGuardMockRecorder is the mock recorder for MockloginGuard.
+type MockloginGuardMockRecorder struct {
+	mock *MockloginGuard
+}
+
+// NewMockloginGuard creates a new mock instance.
+func NewMockloginGuard(ctrl *gomock.Controller) *MockloginGuard {
+	mock := &MockloginGuard{ctrl: ctrl}
+	mock.recorder = &MockloginGuardMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockloginGuard) EXPECT() *MockloginGuardMockRecorder {
+	return m.recorder
+}
+
+// Reserve mocks base method.
+func (m *MockloginGuard) Reserve(ctx context.Context, login, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, login, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockloginGuardMockRecorder) Reserve(ctx, login, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockloginGuard)(nil).Reserve), ctx, login, ip)
+}
+
+// Succeed mocks base method.
+func (m *MockloginGuard) Succeed(ctx context.Context, login, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Succeed", ctx, login, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Succeed indicates an expected call of Succeed.
+func (mr *MockloginGuardMockRecorder) Succeed(ctx, login, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Succeed", reflect.TypeOf((*MockloginGuard)(nil).Succeed), ctx, login, ip)
+}
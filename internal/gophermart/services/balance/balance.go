@@ -3,12 +3,19 @@ package balance
 import (
 	"context"
 
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/MxTrap/gophermart/logger"
 )
 
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
 type balanceRepo interface {
 	Get(ctx context.Context, userID int64) (entity.Balance, error)
+	History(ctx context.Context, userID int64, filter entity.HistoryFilter) ([]entity.BalanceHistoryEntry, error)
 }
 
 type BalanceService struct {
@@ -35,3 +42,31 @@ func (s *BalanceService) Get(ctx context.Context, userID int64) (entity.Balance,
 
 	return balance, nil
 }
+
+// History returns a page of the user's balance history, newest first. A
+// missing limit defaults to defaultHistoryLimit, a larger one than
+// maxHistoryLimit is capped.
+func (s *BalanceService) History(
+	ctx context.Context,
+	userID int64,
+	filter entity.HistoryFilter,
+) ([]entity.BalanceHistoryEntry, error) {
+	log := s.log.With("op", "BalanceService.History")
+
+	if filter.Limit < 0 || filter.Offset < 0 ||
+		(!filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To)) {
+		return nil, common.ErrInvalidHistoryFilter
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultHistoryLimit
+	}
+	filter.Limit = min(filter.Limit, maxHistoryLimit)
+
+	history, err := s.repo.History(ctx, userID, filter)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
 	"github.com/MxTrap/gophermart/logger"
@@ -28,9 +30,9 @@ func TestBalanceService_Get(t *testing.T) {
 			setupMock: func(repo *mocks.MockBalanceRepository) {
 				repo.EXPECT().
 					Get(ctx, userID).
-					Return(entity.Balance{Balance: 100.0, Withdrawn: 20.0}, nil)
+					Return(entity.Balance{Balance: 10000, Withdrawn: 2000}, nil)
 			},
-			expectedBalance: entity.Balance{Balance: 100.0, Withdrawn: 20.0},
+			expectedBalance: entity.Balance{Balance: 10000, Withdrawn: 2000},
 			expectedErr:     nil,
 		},
 		{
@@ -68,3 +70,90 @@ func TestBalanceService_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestBalanceService_History(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+	log := logger.NewLogger()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	history := []entity.BalanceHistoryEntry{
+		{Type: entity.LedgerWithdrawal, Amount: -5000, Balance: 5000, Reference: "2377225624", CreatedAt: from.Add(time.Hour)},
+		{Type: entity.LedgerAccrual, Amount: 10000, Balance: 10000, Reference: "12345678903", CreatedAt: from},
+	}
+
+	tests := []struct {
+		name            string
+		filter          entity.HistoryFilter
+		expectedFilter  *entity.HistoryFilter
+		repoErr         error
+		expectedHistory []entity.BalanceHistoryEntry
+		expectedErr     error
+	}{
+		{
+			name:            "Default limit",
+			filter:          entity.HistoryFilter{},
+			expectedFilter:  &entity.HistoryFilter{Limit: defaultHistoryLimit},
+			expectedHistory: history,
+		},
+		{
+			name:            "Period and page",
+			filter:          entity.HistoryFilter{From: from, To: to, Limit: 10, Offset: 20},
+			expectedFilter:  &entity.HistoryFilter{From: from, To: to, Limit: 10, Offset: 20},
+			expectedHistory: history,
+		},
+		{
+			name:            "Limit is capped",
+			filter:          entity.HistoryFilter{Limit: 1000},
+			expectedFilter:  &entity.HistoryFilter{Limit: maxHistoryLimit},
+			expectedHistory: history,
+		},
+		{
+			name:        "Empty period",
+			filter:      entity.HistoryFilter{From: to, To: from},
+			expectedErr: common.ErrInvalidHistoryFilter,
+		},
+		{
+			name:        "Negative offset",
+			filter:      entity.HistoryFilter{Offset: -1},
+			expectedErr: common.ErrInvalidHistoryFilter,
+		},
+		{
+			name:           "Repository error",
+			filter:         entity.HistoryFilter{Limit: 5},
+			expectedFilter: &entity.HistoryFilter{Limit: 5},
+			repoErr:        errors.New("database error"),
+			expectedErr:    errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockBalanceRepository(ctrl)
+			if tt.expectedFilter != nil {
+				var result []entity.BalanceHistoryEntry
+				if tt.repoErr == nil {
+					result = history
+				}
+				repo.EXPECT().
+					History(ctx, userID, *tt.expectedFilter).
+					Return(result, tt.repoErr)
+			}
+
+			s := NewBalanceService(log, repo)
+
+			result, err := s.History(ctx, userID, tt.filter)
+
+			assert.Equal(t, tt.expectedHistory, result)
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
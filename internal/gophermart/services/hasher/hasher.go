@@ -0,0 +1,123 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+const (
+	saltLength = 16
+	keyLength  = 32
+)
+
+// Argon2Params are the Argon2id cost parameters, memory is in KiB.
+type Argon2Params struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+// PasswordHasher hashes new passwords with Argon2id and verifies Argon2id
+// and legacy bcrypt hashes. Hashes are stored in the PHC string format, so
+// that each of them carries the parameters it was computed with:
+//
+//	$argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>
+type PasswordHasher struct {
+	params Argon2Params
+}
+
+func NewPasswordHasher(params Argon2Params) (*PasswordHasher, error) {
+	if params.Time == 0 || params.Threads == 0 || params.Memory < 8*uint32(params.Threads) {
+		return nil, fmt.Errorf("invalid argon2 parameters: m=%d,t=%d,p=%d", params.Memory, params.Time, params.Threads)
+	}
+	return &PasswordHasher{params: params}, nil
+}
+
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Time, h.params.Memory, h.params.Threads, keyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory,
+		h.params.Time,
+		h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks password against an encoded hash. If the password matches,
+// rehash reports whether the hash was computed with another algorithm or
+// other parameters than the current ones and should be replaced.
+func (h *PasswordHasher) Verify(password string, encoded string) (ok bool, rehash bool, err error) {
+	if isBcrypt(encoded) {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+		return true, true, nil
+	}
+
+	params, salt, key, err := decodeArgon2(encoded)
+	if err != nil {
+		return false, false, err
+	}
+	actual := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return false, false, nil
+	}
+
+	return true, params != h.params || len(key) != keyLength, nil
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func decodeArgon2(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("%w: unsupported argon2 version %q", ErrUnknownHashFormat, parts[2])
+	}
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads)
+	if err != nil || params.Memory == 0 || params.Time == 0 || params.Threads == 0 {
+		return params, nil, nil, fmt.Errorf("%w: invalid argon2 parameters %q", ErrUnknownHashFormat, parts[3])
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("%w: invalid salt", ErrUnknownHashFormat)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("%w: invalid key", ErrUnknownHashFormat)
+	}
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,93 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testParams = Argon2Params{Memory: 64, Time: 1, Threads: 1}
+
+func TestNewPasswordHasher(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  Argon2Params
+		wantErr bool
+	}{
+		{"valid", testParams, false},
+		{"zero time", Argon2Params{Memory: 64, Time: 0, Threads: 1}, true},
+		{"zero threads", Argon2Params{Memory: 64, Time: 1, Threads: 0}, true},
+		{"too little memory", Argon2Params{Memory: 8, Time: 1, Threads: 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewPasswordHasher(tt.params)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, h)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPasswordHasher_Hash(t *testing.T) {
+	h, err := NewPasswordHasher(testParams)
+	assert.NoError(t, err)
+
+	first, err := h.Hash("password123")
+	assert.NoError(t, err)
+	second, err := h.Hash("password123")
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "$argon2id$v=19$m=64,t=1,p=1$"), first)
+	// Соль случайная, хеши одного пароля различаются
+	assert.NotEqual(t, first, second)
+}
+
+func TestPasswordHasher_Verify(t *testing.T) {
+	h, err := NewPasswordHasher(testParams)
+	assert.NoError(t, err)
+	stronger, err := NewPasswordHasher(Argon2Params{Memory: 128, Time: 2, Threads: 1})
+	assert.NoError(t, err)
+
+	current, err := h.Hash("password123")
+	assert.NoError(t, err)
+	outdated, err := stronger.Hash("password123")
+	assert.NoError(t, err)
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		password   string
+		encoded    string
+		wantOK     bool
+		wantRehash bool
+		wantErr    error
+	}{
+		{"current argon2id", "password123", current, true, false, nil},
+		{"wrong password", "wrongpassword", current, false, false, nil},
+		{"other parameters", "password123", outdated, true, true, nil},
+		{"legacy bcrypt", "password123", string(legacy), true, true, nil},
+		{"legacy bcrypt wrong password", "wrongpassword", string(legacy), false, false, nil},
+		{"unknown format", "password123", "plaintext", false, false, ErrUnknownHashFormat},
+		{"other argon2 variant", "password123", strings.Replace(current, "argon2id", "argon2i", 1), false, false, ErrUnknownHashFormat},
+		{"unsupported version", "password123", strings.Replace(current, "v=19", "v=16", 1), false, false, ErrUnknownHashFormat},
+		{"invalid parameters", "password123", strings.Replace(current, "t=1", "t=0", 1), false, false, ErrUnknownHashFormat},
+		{"invalid salt", "password123", "$argon2id$v=19$m=64,t=1,p=1$!!!$c2FsdA", false, false, ErrUnknownHashFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := h.Verify(tt.password, tt.encoded)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantRehash, rehash)
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
@@ -11,24 +16,55 @@ import (
 )
 
 type JwtService struct {
-	secretKey string
+	signing Key
+	keys    map[string]Key
 }
 
-func NewJWTService(secretKey string) *JwtService {
-	return &JwtService{
-		secretKey: secretKey,
+// NewJWTService returns a service signing tokens with the signing key. Tokens
+// signed with any of the verification keys are accepted as well, so that the
+// signing key can be rotated without invalidating the tokens issued before.
+func NewJWTService(signing Key, verification ...Key) (*JwtService, error) {
+	if !signing.CanSign() {
+		return nil, fmt.Errorf("%w: signing key %q has no private part", ErrInvalidKey, signing.ID)
+	}
+
+	keys := map[string]Key{signing.ID: signing}
+	for _, key := range verification {
+		if _, ok := keys[key.ID]; ok {
+			return nil, fmt.Errorf("%w: duplicate key id %q", ErrInvalidKey, key.ID)
+		}
+		keys[key.ID] = key
 	}
+
+	return &JwtService{
+		signing: signing,
+		keys:    keys,
+	}, nil
 }
 
 func (s JwtService) GenerateAccessToken(user entity.User, ttl time.Duration) (entity.Token, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
+
+	token := jwt.New(s.signing.Method)
+	token.Header["kid"] = s.signing.ID
 
 	claims := token.Claims.(jwt.MapClaims)
+	claims["jti"] = hex.EncodeToString(jti)
 	claims["uid"] = user.ID
 	claims["login"] = user.Login
-	claims["exp"] = time.Now().Add(ttl).Unix()
+	if user.Role != "" {
+		claims["role"] = string(user.Role)
+	}
+	// iat carries microseconds, so that a token issued right after the
+	// tokens of its user have been revoked is not revoked with them.
+	claims["iat"] = float64(now.UnixMicro()) / 1e6
+	claims["exp"] = now.Add(ttl).Unix()
 
-	signedString, err := token.SignedString([]byte(s.secretKey))
+	signedString, err := token.SignedString(s.signing.private)
 	if err != nil {
 		return "", err
 	}
@@ -36,36 +72,99 @@ func (s JwtService) GenerateAccessToken(user entity.User, ttl time.Duration) (en
 	return entity.Token(signedString), nil
 }
 
-func (s JwtService) Parse(token entity.Token) (int64, error) {
-	parsedToken, err := jwt.Parse(string(token), func(token *jwt.Token) (any, error) {
-		return []byte(s.secretKey), nil
-	})
+// Parse verifies token and returns its claims. Tokens without an id can not
+// be revoked and are not accepted.
+func (s JwtService) Parse(token entity.Token) (entity.AccessClaims, error) {
+	var result entity.AccessClaims
+
+	parsedToken, err := jwt.Parse(string(token), s.verificationKey)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
-			return 0, common.ErrTokenHasExpired
+			return result, common.ErrTokenHasExpired
 		}
-		return 0, err
+		return result, err
 	}
 
 	if !parsedToken.Valid {
-		return 0, common.ErrInvalidToken
+		return result, common.ErrInvalidToken
 	}
 
 	claims, ok := parsedToken.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, common.ErrInvalidToken
+		return result, common.ErrInvalidToken
 	}
 	uid, ok := claims["uid"]
 	if !ok {
-		return 0, common.ErrInvalidToken
+		return result, common.ErrInvalidToken
 	}
 
 	fuid, ok := uid.(float64)
 
 	if !ok {
-		return 0, common.ErrInvalidToken
+		return result, common.ErrInvalidToken
 	}
 
-	return int64(fuid), nil
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return result, common.ErrInvalidToken
+	}
+	// Tokens issued before roles were introduced carry none.
+	role := entity.RoleUser
+	if claim, ok := claims["role"]; ok {
+		name, ok := claim.(string)
+		if !ok {
+			return result, common.ErrInvalidToken
+		}
+		if role, err = entity.ParseRole(name); err != nil {
+			return result, common.ErrInvalidToken
+		}
+	}
+	// GetIssuedAt would round iat down to jwt.TimePrecision, a second.
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return result, common.ErrInvalidToken
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return result, common.ErrInvalidToken
+	}
+
+	return entity.AccessClaims{
+		ID:        jti,
+		UserID:    int64(fuid),
+		Role:      role,
+		IssuedAt:  time.UnixMicro(int64(math.Round(iat * 1e6))),
+		ExpiresAt: exp.Time,
+	}, nil
+}
+
+// verificationKey picks the key by the kid header. The algorithm is fixed by
+// the key, so that a token can not choose how it is verified.
+func (s JwtService) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok || token.Method.Alg() != key.Method.Alg() {
+		return nil, common.ErrInvalidToken
+	}
+	return key.public, nil
+}
+
+// JWKS returns the public keys tokens are verified with, the signing key
+// first. HS256 secrets are not published.
+func (s JwtService) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range s.keys {
+		if key.Method == jwt.SigningMethodHS256 {
+			continue
+		}
+		set.Keys = append(set.Keys, key.jwk())
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		if set.Keys[i].KeyID == s.signing.ID || set.Keys[j].KeyID == s.signing.ID {
+			return set.Keys[i].KeyID == s.signing.ID
+		}
+		return set.Keys[i].KeyID < set.Keys[j].KeyID
+	})
+	return set
 }
@@ -1,6 +1,10 @@
 package jwt
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
 	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/golang-jwt/jwt/v5"
@@ -9,9 +13,17 @@ import (
 	"time"
 )
 
+const testKeyID = "test"
+
+func newHMACService(t *testing.T, secretKey string) *JwtService {
+	svc, err := NewJWTService(NewHMACKey(testKeyID, []byte(secretKey)))
+	assert.NoError(t, err)
+	return svc
+}
+
 func TestJwtService_GenerateAccessToken(t *testing.T) {
 	secretKey := "test-secret-key"
-	svc := NewJWTService(secretKey)
+	svc := newHMACService(t, secretKey)
 	user := entity.User{
 		ID:    1,
 		Login: "testuser",
@@ -29,11 +41,14 @@ func TestJwtService_GenerateAccessToken(t *testing.T) {
 		})
 		assert.NoError(t, err)
 		assert.True(t, parsedToken.Valid)
+		assert.Equal(t, testKeyID, parsedToken.Header["kid"])
 
 		claims, ok := parsedToken.Claims.(jwt.MapClaims)
 		assert.True(t, ok)
 		assert.Equal(t, float64(user.ID), claims["uid"])
 		assert.Equal(t, user.Login, claims["login"])
+		assert.Len(t, claims["jti"], 32)
+		assert.InDelta(t, time.Now().Unix(), claims["iat"], 5)
 		assert.InDelta(t, time.Now().Add(ttl).Unix(), claims["exp"], 5)
 	})
 
@@ -41,7 +56,7 @@ func TestJwtService_GenerateAccessToken(t *testing.T) {
 
 func TestJwtService_Parse(t *testing.T) {
 	secretKey := "test-secret-key"
-	svc := NewJWTService(secretKey)
+	svc := newHMACService(t, secretKey)
 	user := entity.User{
 		ID:    1,
 		Login: "testuser",
@@ -52,9 +67,88 @@ func TestJwtService_Parse(t *testing.T) {
 		token, err := svc.GenerateAccessToken(user, ttl)
 		assert.NoError(t, err)
 
-		uid, err := svc.Parse(token)
+		claims, err := svc.Parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+		assert.NotEmpty(t, claims.ID)
+		assert.WithinDuration(t, time.Now(), claims.IssuedAt, 5*time.Second)
+		assert.WithinDuration(t, time.Now().Add(ttl), claims.ExpiresAt, 5*time.Second)
+
+		other, err := svc.GenerateAccessToken(user, ttl)
+		assert.NoError(t, err)
+		otherClaims, err := svc.Parse(other)
+		assert.NoError(t, err)
+		assert.NotEqual(t, claims.ID, otherClaims.ID, "every token must have its own id")
+	})
+
+	t.Run("role", func(t *testing.T) {
+		admin := user
+		admin.Role = entity.RoleAdmin
+		token, err := svc.GenerateAccessToken(admin, ttl)
+		assert.NoError(t, err)
+
+		claims, err := svc.Parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.RoleAdmin, claims.Role)
+	})
+
+	t.Run("missing role claim", func(t *testing.T) {
+		// Токены без роли выпущены обычным пользователям
+		token, err := svc.GenerateAccessToken(user, ttl)
+		assert.NoError(t, err)
+
+		claims, err := svc.Parse(token)
 		assert.NoError(t, err)
-		assert.Equal(t, user.ID, uid)
+		assert.Equal(t, entity.RoleUser, claims.Role)
+	})
+
+	t.Run("unknown role claim", func(t *testing.T) {
+		token := jwt.New(jwt.SigningMethodHS256)
+		token.Header["kid"] = testKeyID
+		claims := token.Claims.(jwt.MapClaims)
+		claims["jti"] = "jti"
+		claims["uid"] = user.ID
+		claims["role"] = "root"
+		claims["iat"] = time.Now().Unix()
+		claims["exp"] = time.Now().Add(ttl).Unix()
+		signedString, err := token.SignedString([]byte(secretKey))
+		assert.NoError(t, err)
+
+		parsed, err := svc.Parse(entity.Token(signedString))
+		assert.ErrorIs(t, err, common.ErrInvalidToken)
+		assert.Equal(t, entity.AccessClaims{}, parsed)
+	})
+
+	t.Run("issued at", func(t *testing.T) {
+		issuedAt := time.Now().Truncate(time.Second).Add(123456 * time.Microsecond)
+
+		tests := []struct {
+			name string
+			iat  any
+			want time.Time
+		}{
+			{"microseconds", float64(issuedAt.UnixMicro()) / 1e6, issuedAt},
+			// Токены, выпущенные раньше, несут iat в целых секундах
+			{"whole seconds", issuedAt.Unix(), issuedAt.Truncate(time.Second)},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				token := jwt.New(jwt.SigningMethodHS256)
+				token.Header["kid"] = testKeyID
+				claims := token.Claims.(jwt.MapClaims)
+				claims["jti"] = "jti"
+				claims["uid"] = user.ID
+				claims["iat"] = tt.iat
+				claims["exp"] = time.Now().Add(ttl).Unix()
+				signedString, err := token.SignedString([]byte(secretKey))
+				assert.NoError(t, err)
+
+				parsed, err := svc.Parse(entity.Token(signedString))
+				assert.NoError(t, err)
+				assert.True(t, tt.want.Equal(parsed.IssuedAt), "got %s", parsed.IssuedAt)
+			})
+		}
 	})
 
 	t.Run("expired token", func(t *testing.T) {
@@ -62,46 +156,65 @@ func TestJwtService_Parse(t *testing.T) {
 		token, err := svc.GenerateAccessToken(user, expiredTTL)
 		assert.NoError(t, err)
 
-		uid, err := svc.Parse(token)
+		claims, err := svc.Parse(token)
 		assert.ErrorIs(t, err, common.ErrTokenHasExpired)
-		assert.Equal(t, int64(0), uid)
+		assert.Equal(t, entity.AccessClaims{}, claims)
 	})
 
 	t.Run("invalid token", func(t *testing.T) {
 		invalidToken := entity.Token("invalid.token.string")
-		uid, err := svc.Parse(invalidToken)
+		claims, err := svc.Parse(invalidToken)
 		assert.Error(t, err)
 		assert.NotEqual(t, common.ErrTokenHasExpired, err) // Ошибка не связана с истечением
-		assert.Equal(t, int64(0), uid)
+		assert.Equal(t, entity.AccessClaims{}, claims)
 	})
 
 	t.Run("wrong secret key", func(t *testing.T) {
 		token, err := svc.GenerateAccessToken(user, ttl)
 		assert.NoError(t, err)
 
-		wrongSvc := NewJWTService("wrong-secret-key")
-		uid, err := wrongSvc.Parse(token)
+		wrongSvc := newHMACService(t, "wrong-secret-key")
+		claims, err := wrongSvc.Parse(token)
 		assert.Error(t, err)
-		assert.Equal(t, int64(0), uid)
+		assert.Equal(t, entity.AccessClaims{}, claims)
 	})
 
 	t.Run("missing uid claim", func(t *testing.T) {
 		// Создаем токен без uid
 		token := jwt.New(jwt.SigningMethodHS256)
+		token.Header["kid"] = testKeyID
+		claims := token.Claims.(jwt.MapClaims)
+		claims["login"] = user.Login
+		claims["exp"] = time.Now().Add(ttl).Unix()
+		signedString, err := token.SignedString([]byte(secretKey))
+		assert.NoError(t, err)
+
+		parsed, err := svc.Parse(entity.Token(signedString))
+		assert.ErrorIs(t, err, common.ErrInvalidToken)
+		assert.Equal(t, entity.AccessClaims{}, parsed)
+	})
+
+	t.Run("missing jti claim", func(t *testing.T) {
+		// Токены, выпущенные до появления jti, нельзя отозвать
+		token := jwt.New(jwt.SigningMethodHS256)
+		token.Header["kid"] = testKeyID
 		claims := token.Claims.(jwt.MapClaims)
+		claims["uid"] = user.ID
 		claims["login"] = user.Login
+		claims["iat"] = time.Now().Unix()
 		claims["exp"] = time.Now().Add(ttl).Unix()
 		signedString, err := token.SignedString([]byte(secretKey))
 		assert.NoError(t, err)
 
-		uid, err := svc.Parse(entity.Token(signedString))
+		parsed, err := svc.Parse(entity.Token(signedString))
 		assert.ErrorIs(t, err, common.ErrInvalidToken)
-		assert.Equal(t, int64(0), uid)
+		assert.Equal(t, entity.AccessClaims{}, parsed)
 	})
 
 	t.Run("invalid uid type", func(t *testing.T) {
 		// Создаем токен с некорректным типом uid
 		token := jwt.New(jwt.SigningMethodHS256)
+		token.Header["kid"] = testKeyID
 		claims := token.Claims.(jwt.MapClaims)
 		claims["uid"] = "not-a-number"
 		claims["login"] = user.Login
@@ -109,8 +222,131 @@ func TestJwtService_Parse(t *testing.T) {
 		signedString, err := token.SignedString([]byte(secretKey))
 		assert.NoError(t, err)
 
-		uid, err := svc.Parse(entity.Token(signedString))
+		parsed, err := svc.Parse(entity.Token(signedString))
 		assert.ErrorIs(t, err, common.ErrInvalidToken)
-		assert.Equal(t, int64(0), uid)
+		assert.Equal(t, entity.AccessClaims{}, parsed)
 	})
 }
+
+func TestJwtService_asymmetric(t *testing.T) {
+	user := entity.User{ID: 1, Login: "testuser"}
+
+	_, edPrivate, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		private crypto.Signer
+		alg     string
+		kty     string
+	}{
+		{"EdDSA", edPrivate, "EdDSA", "OKP"},
+		{"RS256", rsaPrivate, "RS256", "RSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewPrivateKey("", tt.private)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, key.ID, "key id is derived from the key")
+
+			svc, err := NewJWTService(key)
+			assert.NoError(t, err)
+
+			token, err := svc.GenerateAccessToken(user, time.Hour)
+			assert.NoError(t, err)
+			claims, err := svc.Parse(token)
+			assert.NoError(t, err)
+			assert.Equal(t, user.ID, claims.UserID)
+
+			jwks := svc.JWKS()
+			assert.Len(t, jwks.Keys, 1)
+			assert.Equal(t, key.ID, jwks.Keys[0].KeyID)
+			assert.Equal(t, tt.alg, jwks.Keys[0].Algorithm)
+			assert.Equal(t, tt.kty, jwks.Keys[0].KeyType)
+			assert.Equal(t, "sig", jwks.Keys[0].Use)
+		})
+	}
+}
+
+func TestJwtService_rotation(t *testing.T) {
+	user := entity.User{ID: 1, Login: "testuser"}
+
+	_, oldPrivate, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	_, newPrivate, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	oldKey, err := NewPrivateKey("old", oldPrivate)
+	assert.NoError(t, err)
+	newKey, err := NewPrivateKey("new", newPrivate)
+	assert.NoError(t, err)
+	retiredKey, err := NewPublicKey("old", oldPrivate.Public())
+	assert.NoError(t, err)
+
+	oldSvc, err := NewJWTService(oldKey)
+	assert.NoError(t, err)
+	oldToken, err := oldSvc.GenerateAccessToken(user, time.Hour)
+	assert.NoError(t, err)
+
+	t.Run("tokens signed with a retired key are accepted", func(t *testing.T) {
+		svc, err := NewJWTService(newKey, retiredKey)
+		assert.NoError(t, err)
+
+		claims, err := svc.Parse(oldToken)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+
+		jwks := svc.JWKS()
+		assert.Len(t, jwks.Keys, 2)
+		assert.Equal(t, "new", jwks.Keys[0].KeyID, "the signing key goes first")
+	})
+
+	t.Run("tokens signed with a removed key are rejected", func(t *testing.T) {
+		svc, err := NewJWTService(newKey)
+		assert.NoError(t, err)
+
+		_, err = svc.Parse(oldToken)
+		assert.ErrorIs(t, err, common.ErrInvalidToken)
+	})
+
+	t.Run("retired key can not sign", func(t *testing.T) {
+		_, err := NewJWTService(retiredKey)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("duplicate key id", func(t *testing.T) {
+		_, err := NewJWTService(oldKey, retiredKey)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+}
+
+func TestJwtService_Parse_algorithmConfusion(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	key, err := NewPrivateKey("ed", private)
+	assert.NoError(t, err)
+	svc, err := NewJWTService(key)
+	assert.NoError(t, err)
+
+	// Токен, подписанный HS256 публичным ключом, не должен приниматься
+	token := jwt.New(jwt.SigningMethodHS256)
+	token.Header["kid"] = "ed"
+	claims := token.Claims.(jwt.MapClaims)
+	claims["jti"] = "jti"
+	claims["uid"] = 1
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	signedString, err := token.SignedString([]byte(private.Public().(ed25519.PublicKey)))
+	assert.NoError(t, err)
+
+	_, err = svc.Parse(entity.Token(signedString))
+	assert.ErrorIs(t, err, common.ErrInvalidToken)
+}
+
+func TestJwtService_JWKS_hmac(t *testing.T) {
+	svc := newHMACService(t, "test-secret-key")
+	assert.Empty(t, svc.JWKS().Keys, "HS256 secrets must not be published")
+}
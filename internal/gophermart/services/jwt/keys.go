@@ -0,0 +1,213 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minSecretSize = 32
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidKey           = errors.New("invalid key")
+)
+
+// Key is a key tokens are signed or verified with. Retired keys have no
+// private part: they only verify the tokens issued before the rotation.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private any
+	public  any
+}
+
+// NewHMACKey returns a HS256 key. The secret both signs and verifies tokens,
+// so it is never published in the JWKS. Unless given, the key id is derived
+// from a truncated hash of the secret.
+func NewHMACKey(id string, secret []byte) Key {
+	if id == "" {
+		sum := sha256.Sum256(secret)
+		id = hex.EncodeToString(sum[:8])
+	}
+	return Key{ID: id, Method: jwt.SigningMethodHS256, private: secret, public: secret}
+}
+
+// NewPrivateKey returns a key signing tokens with an RSA (RS256) or Ed25519
+// (EdDSA) private key.
+func NewPrivateKey(id string, private crypto.Signer) (Key, error) {
+	key, err := NewPublicKey(id, private.Public())
+	if err != nil {
+		return Key{}, err
+	}
+	key.private = private
+	return key, nil
+}
+
+// NewPublicKey returns a key verifying RS256 or EdDSA tokens. Unless
+// given, the key id is the RFC 7638 thumbprint of the key.
+func NewPublicKey(id string, public crypto.PublicKey) (Key, error) {
+	key := Key{ID: id, public: public}
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return Key{}, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, public)
+	}
+	if key.ID == "" {
+		key.ID = thumbprint(key.jwk().fields())
+	}
+	return key, nil
+}
+
+// CanSign reports whether the key has a private part.
+func (k Key) CanSign() bool {
+	return k.private != nil
+}
+
+// LoadSigningKey reads the key to sign tokens with from path: a secret of at
+// least 32 bytes for HS256 or a PEM-encoded PKCS #8 private key for RS256
+// and EdDSA.
+func LoadSigningKey(alg, id, path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch alg {
+	case jwt.SigningMethodHS256.Alg():
+		return newSecretKey(id, data)
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+		private, err := parsePrivateKey(data)
+		if err != nil {
+			return Key{}, err
+		}
+		key, err := NewPrivateKey(id, private)
+		if err != nil {
+			return Key{}, err
+		}
+		if key.Method.Alg() != alg {
+			return Key{}, fmt.Errorf("%w: %s key given for %s", ErrInvalidKey, key.Method.Alg(), alg)
+		}
+		return key, nil
+	default:
+		return Key{}, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+	}
+}
+
+// LoadVerificationKey reads a retired key from path: a PEM-encoded public
+// key or, if the file is not PEM, a HS256 secret.
+func LoadVerificationKey(id, path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return newSecretKey(id, data)
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+	return NewPublicKey(id, public)
+}
+
+func newSecretKey(id string, data []byte) (Key, error) {
+	secret := bytes.TrimSpace(data)
+	if len(secret) < minSecretSize {
+		return Key{}, fmt.Errorf("%w: HS256 secret must be at least %d bytes", ErrInvalidKey, minSecretSize)
+	}
+	return NewHMACKey(id, secret), nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM data found", ErrInvalidKey)
+	}
+
+	var (
+		private any
+		err     error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedAlgorithm, private)
+	}
+	return signer, nil
+}
+
+// JWK is a public key in the JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKSet is the document published at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k Key) jwk() JWK {
+	jwk := JWK{KeyID: k.ID, Algorithm: k.Method.Alg(), Use: "sig"}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// fields returns the required members of the key used for its thumbprint.
+func (jwk JWK) fields() map[string]string {
+	if jwk.KeyType == "RSA" {
+		return map[string]string{"kty": jwk.KeyType, "n": jwk.N, "e": jwk.E}
+	}
+	return map[string]string{"kty": jwk.KeyType, "crv": jwk.Curve, "x": jwk.X}
+}
+
+// thumbprint computes the RFC 7638 thumbprint of a key: json.Marshal sorts
+// the members and adds no whitespace, as the RFC requires.
+func thumbprint(fields map[string]string) string {
+	data, _ := json.Marshal(fields)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
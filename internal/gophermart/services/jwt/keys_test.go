@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	return writeFile(t, name, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	_, edPrivate, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	assert.NoError(t, err)
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	rsaDER, err := x509.MarshalPKCS8PrivateKey(rsaPrivate)
+	assert.NoError(t, err)
+
+	secret := strings.Repeat("s", minSecretSize)
+	edPath := writePEM(t, "ed.pem", "PRIVATE KEY", edDER)
+	rsaPath := writePEM(t, "rsa.pem", "PRIVATE KEY", rsaDER)
+	pkcs1Path := writePEM(t, "rsa1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaPrivate))
+
+	tests := []struct {
+		name        string
+		alg         string
+		path        string
+		expectedAlg string
+		expectedErr error
+	}{
+		{name: "HS256 secret", alg: "HS256", path: writeFile(t, "secret", []byte(secret+"\n")), expectedAlg: "HS256"},
+		{name: "short HS256 secret", alg: "HS256", path: writeFile(t, "short", []byte("very secret")), expectedErr: ErrInvalidKey},
+		{name: "EdDSA key", alg: "EdDSA", path: edPath, expectedAlg: "EdDSA"},
+		{name: "RS256 PKCS #8 key", alg: "RS256", path: rsaPath, expectedAlg: "RS256"},
+		{name: "RS256 PKCS #1 key", alg: "RS256", path: pkcs1Path, expectedAlg: "RS256"},
+		{name: "key of another algorithm", alg: "RS256", path: edPath, expectedErr: ErrInvalidKey},
+		{name: "not a PEM key", alg: "EdDSA", path: writeFile(t, "garbage", []byte(secret)), expectedErr: ErrInvalidKey},
+		{name: "unsupported algorithm", alg: "none", path: edPath, expectedErr: ErrUnsupportedAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadSigningKey(tt.alg, "", tt.path)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAlg, key.Method.Alg())
+			assert.True(t, key.CanSign())
+			assert.NotEmpty(t, key.ID)
+		})
+	}
+
+	t.Run("secret is trimmed", func(t *testing.T) {
+		key, err := LoadSigningKey("HS256", "kid", writeFile(t, "secret", []byte(secret+"\n")))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(secret), key.private)
+		assert.Equal(t, "kid", key.ID)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadSigningKey("HS256", "", filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestLoadVerificationKey(t *testing.T) {
+	edPublic, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(edPublic)
+	assert.NoError(t, err)
+
+	t.Run("public key", func(t *testing.T) {
+		key, err := LoadVerificationKey("old", writePEM(t, "ed.pub", "PUBLIC KEY", der))
+		assert.NoError(t, err)
+		assert.Equal(t, "old", key.ID)
+		assert.Equal(t, "EdDSA", key.Method.Alg())
+		assert.False(t, key.CanSign())
+	})
+
+	t.Run("HS256 secret", func(t *testing.T) {
+		key, err := LoadVerificationKey("old", writeFile(t, "secret", []byte(strings.Repeat("s", minSecretSize))))
+		assert.NoError(t, err)
+		assert.Equal(t, "HS256", key.Method.Alg())
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		_, err := LoadVerificationKey("old", writePEM(t, "bad.pub", "PUBLIC KEY", []byte("garbage")))
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+}
+
+func TestThumbprint(t *testing.T) {
+	// Пример из RFC 7638, раздел 3.1
+	jwk := JWK{
+		KeyType: "RSA",
+		E:       "AQAB",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMs" +
+			"tn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5h" +
+			"ajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	}
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint(jwk.fields()))
+}
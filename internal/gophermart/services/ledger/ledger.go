@@ -0,0 +1,43 @@
+package ledger
+
+import (
+	"context"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+type ledgerRepo interface {
+	Mismatches(ctx context.Context) ([]entity.BalanceMismatch, error)
+}
+
+type LedgerService struct {
+	log  *logger.Logger
+	repo ledgerRepo
+}
+
+func NewLedgerService(log *logger.Logger, repo ledgerRepo) *LedgerService {
+	return &LedgerService{
+		log:  log,
+		repo: repo,
+	}
+}
+
+// Check confirms that the balance snapshot of every user matches the sum of
+// the user's ledger entries and returns the users for which it does not.
+func (s *LedgerService) Check(ctx context.Context) ([]entity.BalanceMismatch, error) {
+	log := s.log.With("op", "LedgerService.Check")
+	mismatches, err := s.repo.Mismatches(ctx)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for _, m := range mismatches {
+		log.Warnw("balance does not match ledger",
+			"user_id", m.UserID,
+			"balance", m.Balance, "ledger_balance", m.LedgerBalance,
+			"withdrawn", m.Withdrawn, "ledger_withdrawn", m.LedgerWithdrawn,
+		)
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,59 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerService_Check(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		mismatches  []entity.BalanceMismatch
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "Consistent"},
+		{
+			name: "Mismatch",
+			mismatches: []entity.BalanceMismatch{
+				{UserID: 1, Balance: 10000, LedgerBalance: 9000},
+			},
+		},
+		{
+			name:        "Repository error",
+			repoErr:     errors.New("db error"),
+			expectedErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockLedgerRepository(ctrl)
+			repo.EXPECT().
+				Mismatches(ctx).
+				Return(tt.mismatches, tt.repoErr)
+
+			s := NewLedgerService(logger.NewLogger(), repo)
+			result, err := s.Check(ctx)
+
+			assert.Equal(t, tt.mismatches, result)
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
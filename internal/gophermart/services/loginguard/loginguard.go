@@ -0,0 +1,183 @@
+package loginguard
+
+import (
+	"context"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+const (
+	// failureWindow is how long attempts are remembered: the counter starts
+	// over after this much time without an attempt.
+	failureWindow = time.Hour
+	// loginFreeAttempts and ipFreeAttempts are the attempts allowed before
+	// the login or the IP is locked. An IP is allowed more, since many
+	// users may share it.
+	loginFreeAttempts = 3
+	ipFreeAttempts    = 20
+	// resetLoginFreeRequests and resetIPFreeRequests are the password reset
+	// requests allowed before the login or the IP is locked. Each request
+	// may send a message to the owner of the login, so only a few are free.
+	resetLoginFreeRequests = 3
+	resetIPFreeRequests    = 10
+	baseLockout            = time.Second
+	maxLockout             = 15 * time.Minute
+	cleanupInterval        = time.Hour
+)
+
+type attemptRepo interface {
+	LockedUntil(ctx context.Context, keys []entity.LoginAttemptKey) (time.Time, error)
+	Reserve(ctx context.Context, keys []entity.LoginAttemptKey, now time.Time, windowStart time.Time) (bool, error)
+	Release(ctx context.Context, key entity.LoginAttemptKey) error
+	Reset(ctx context.Context, scope entity.LoginAttemptScope, key string) error
+	DeleteStale(ctx context.Context, before time.Time, now time.Time) (int64, error)
+}
+
+// LoginGuard throttles password guessing. Attempts are counted per login and
+// per client IP before the password is checked and taken back when it turns
+// out to be right; once the free attempts are used up, every further attempt
+// locks the login or the IP for twice as long as the previous one.
+type LoginGuard struct {
+	log  *logger.Logger
+	repo attemptRepo
+	now  func() time.Time
+}
+
+func NewLoginGuard(log *logger.Logger, repo attemptRepo) *LoginGuard {
+	return &LoginGuard{
+		log:  log,
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Reserve counts an attempt for the login and the IP before the password is
+// checked, or returns a *common.TooManyRequestsError if either is locked.
+// Counting comes first, so parallel guesses cannot all get past the lockout
+// before any of them has failed. An empty ip means the client address is
+// unknown and only the login is counted: all clients would share the address
+// of the proxy and lock each other out.
+func (g *LoginGuard) Reserve(ctx context.Context, login string, ip string) error {
+	keys := []entity.LoginAttemptKey{g.loginKey(login)}
+	if ip != "" {
+		keys = append(keys, g.ipKey(ip))
+	}
+	return g.reserve(ctx, keys)
+}
+
+// ReserveReset counts a password reset request for the login and the IP, or
+// returns a *common.TooManyRequestsError if either is locked. The requests
+// are counted apart from login attempts: requesting resets for a login must
+// not lock its owner out. An empty ip is handled as by Reserve.
+func (g *LoginGuard) ReserveReset(ctx context.Context, login string, ip string) error {
+	keys := []entity.LoginAttemptKey{{
+		Scope:        entity.PasswordResetByLogin,
+		Key:          login,
+		FreeAttempts: resetLoginFreeRequests,
+		BaseLockout:  baseLockout,
+		MaxLockout:   maxLockout,
+	}}
+	if ip != "" {
+		keys = append(keys, entity.LoginAttemptKey{
+			Scope:        entity.PasswordResetByIP,
+			Key:          ip,
+			FreeAttempts: resetIPFreeRequests,
+			BaseLockout:  baseLockout,
+			MaxLockout:   maxLockout,
+		})
+	}
+	return g.reserve(ctx, keys)
+}
+
+func (g *LoginGuard) reserve(ctx context.Context, keys []entity.LoginAttemptKey) error {
+	now := g.now()
+	allowed, err := g.repo.Reserve(ctx, keys, now, now.Add(-failureWindow))
+	if err != nil {
+		return err
+	}
+	if allowed {
+		return nil
+	}
+
+	lockedUntil, err := g.repo.LockedUntil(ctx, keys)
+	if err != nil {
+		return err
+	}
+	wait := lockedUntil.Sub(now)
+	if wait <= 0 {
+		// The lockout has just ended.
+		wait = baseLockout
+	}
+	g.log.Warnw("attempt locked", "keys", keys, "retryAfter", wait)
+	return &common.TooManyRequestsError{RetryAfter: wait}
+}
+
+// Succeed takes back the attempt reserved by Reserve once the password turns
+// out to be right. The attempts of the login are forgotten; only this attempt
+// of the IP is taken back: logging in to one's own account must not allow
+// guessing passwords of others.
+func (g *LoginGuard) Succeed(ctx context.Context, login string, ip string) error {
+	if err := g.repo.Reset(ctx, entity.LoginAttemptByLogin, login); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return g.repo.Release(ctx, g.ipKey(ip))
+}
+
+func (*LoginGuard) loginKey(login string) entity.LoginAttemptKey {
+	return entity.LoginAttemptKey{
+		Scope:        entity.LoginAttemptByLogin,
+		Key:          login,
+		FreeAttempts: loginFreeAttempts,
+		BaseLockout:  baseLockout,
+		MaxLockout:   maxLockout,
+	}
+}
+
+func (*LoginGuard) ipKey(ip string) entity.LoginAttemptKey {
+	return entity.LoginAttemptKey{
+		Scope:        entity.LoginAttemptByIP,
+		Key:          ip,
+		FreeAttempts: ipFreeAttempts,
+		BaseLockout:  baseLockout,
+		MaxLockout:   maxLockout,
+	}
+}
+
+// Unlock lifts the lockout of the login.
+func (g *LoginGuard) Unlock(ctx context.Context, login string) error {
+	log := g.log.With("op", "LoginGuard.Unlock", "login", login)
+	if err := g.repo.Reset(ctx, entity.LoginAttemptByLogin, login); err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Info("login unlocked")
+	return nil
+}
+
+// Run periodically removes the counters which have been forgotten anyway
+// until ctx is done.
+func (g *LoginGuard) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := g.now()
+			deleted, err := g.repo.DeleteStale(ctx, now.Add(-failureWindow), now)
+			if err != nil {
+				g.log.Error("failed to delete stale login attempts: ", err)
+				continue
+			}
+			g.log.Infow("stale login attempts deleted", "count", deleted)
+		}
+	}
+}
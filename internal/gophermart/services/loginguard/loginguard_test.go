@@ -0,0 +1,177 @@
+package loginguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGuard(repo attemptRepo, now time.Time) *LoginGuard {
+	g := NewLoginGuard(logger.NewLogger(), repo)
+	g.now = func() time.Time { return now }
+	return g
+}
+
+func TestLoginGuard_Reserve(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	windowStart := now.Add(-failureWindow)
+	keys := []entity.LoginAttemptKey{
+		{
+			Scope:        entity.LoginAttemptByLogin,
+			Key:          "testuser",
+			FreeAttempts: loginFreeAttempts,
+			BaseLockout:  baseLockout,
+			MaxLockout:   maxLockout,
+		},
+		{
+			Scope:        entity.LoginAttemptByIP,
+			Key:          "192.0.2.1",
+			FreeAttempts: ipFreeAttempts,
+			BaseLockout:  baseLockout,
+			MaxLockout:   maxLockout,
+		},
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reserve(ctx, keys, now, windowStart).Return(true, nil)
+
+		assert.NoError(t, newTestGuard(repo, now).Reserve(ctx, "testuser", "192.0.2.1"))
+	})
+
+	t.Run("unknown ip", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Без адреса клиента считаются только попытки логина
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reserve(ctx, keys[:1], now, windowStart).Return(true, nil)
+
+		assert.NoError(t, newTestGuard(repo, now).Reserve(ctx, "testuser", ""))
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reserve(ctx, keys, now, windowStart).Return(false, nil)
+		repo.EXPECT().LockedUntil(ctx, keys).Return(now.Add(30*time.Second), nil)
+
+		err := newTestGuard(repo, now).Reserve(ctx, "testuser", "192.0.2.1")
+
+		var tooManyRequests *common.TooManyRequestsError
+		assert.ErrorAs(t, err, &tooManyRequests)
+		assert.Equal(t, 30*time.Second, tooManyRequests.RetryAfter)
+	})
+
+	t.Run("lockout just ended", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reserve(ctx, keys, now, windowStart).Return(false, nil)
+		repo.EXPECT().LockedUntil(ctx, keys).Return(now, nil)
+
+		err := newTestGuard(repo, now).Reserve(ctx, "testuser", "192.0.2.1")
+
+		var tooManyRequests *common.TooManyRequestsError
+		assert.ErrorAs(t, err, &tooManyRequests)
+		assert.Equal(t, baseLockout, tooManyRequests.RetryAfter)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dbErr := errors.New("db error")
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reserve(ctx, keys, now, windowStart).Return(false, dbErr)
+
+		assert.ErrorIs(t, newTestGuard(repo, now).Reserve(ctx, "testuser", "192.0.2.1"), dbErr)
+	})
+}
+
+func TestLoginGuard_ReserveReset(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	windowStart := now.Add(-failureWindow)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Запросы сброса считаются отдельно от попыток входа
+	repo := mocks.NewMockLoginAttemptRepository(ctrl)
+	repo.EXPECT().Reserve(ctx, []entity.LoginAttemptKey{
+		{
+			Scope:        entity.PasswordResetByLogin,
+			Key:          "testuser",
+			FreeAttempts: resetLoginFreeRequests,
+			BaseLockout:  baseLockout,
+			MaxLockout:   maxLockout,
+		},
+		{
+			Scope:        entity.PasswordResetByIP,
+			Key:          "192.0.2.1",
+			FreeAttempts: resetIPFreeRequests,
+			BaseLockout:  baseLockout,
+			MaxLockout:   maxLockout,
+		},
+	}, now, windowStart).Return(true, nil)
+
+	assert.NoError(t, newTestGuard(repo, now).ReserveReset(ctx, "testuser", "192.0.2.1"))
+}
+
+func TestLoginGuard_Succeed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("resets login and releases ip", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reset(ctx, entity.LoginAttemptByLogin, "testuser").Return(nil)
+		repo.EXPECT().Release(ctx, entity.LoginAttemptKey{
+			Scope:        entity.LoginAttemptByIP,
+			Key:          "192.0.2.1",
+			FreeAttempts: ipFreeAttempts,
+			BaseLockout:  baseLockout,
+			MaxLockout:   maxLockout,
+		}).Return(nil)
+
+		assert.NoError(t, newTestGuard(repo, time.Now()).Succeed(ctx, "testuser", "192.0.2.1"))
+	})
+
+	t.Run("unknown ip", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockLoginAttemptRepository(ctrl)
+		repo.EXPECT().Reset(ctx, entity.LoginAttemptByLogin, "testuser").Return(nil)
+
+		assert.NoError(t, newTestGuard(repo, time.Now()).Succeed(ctx, "testuser", ""))
+	})
+}
+
+func TestLoginGuard_Unlock(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockLoginAttemptRepository(ctrl)
+	repo.EXPECT().Reset(ctx, entity.LoginAttemptByLogin, "testuser").Return(nil)
+
+	assert.NoError(t, newTestGuard(repo, time.Now()).Unlock(ctx, "testuser"))
+}
@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+	"resty.dev/v3"
+)
+
+const requestTimeout = 10 * time.Second
+
+// WebhookNotifier hands notifications over to a delivery service, which
+// knows how to reach a user by login, e.g. by mail. Tokens are only sent to
+// the service and never logged.
+type WebhookNotifier struct {
+	log    *logger.Logger
+	client *resty.Client
+	url    string
+}
+
+func NewWebhookNotifier(log *logger.Logger, url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		log:    log,
+		client: resty.New().SetTimeout(requestTimeout),
+		url:    url,
+	}
+}
+
+// Close releases idle connections of the client.
+func (n *WebhookNotifier) Close() error {
+	return n.client.Close()
+}
+
+type passwordResetDto struct {
+	Type      string    `json:"type"`
+	Login     string    `json:"login"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (n *WebhookNotifier) SendPasswordReset(ctx context.Context, user entity.User, token entity.Token, expiresAt time.Time) error {
+	res, err := n.client.
+		R().
+		SetContext(ctx).
+		SetBody(passwordResetDto{
+			Type:      "password_reset",
+			Login:     user.Login,
+			Token:     string(token),
+			ExpiresAt: expiresAt,
+		}).
+		Post(n.url)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() < http.StatusOK || res.StatusCode() >= http.StatusMultipleChoices {
+		return fmt.Errorf("delivery service responded with %s", res.Status())
+	}
+	n.log.Infow("password reset sent", "op", "WebhookNotifier.SendPasswordReset", "login", user.Login)
+	return nil
+}
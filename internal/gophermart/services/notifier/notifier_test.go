@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotifier_SendPasswordReset(t *testing.T) {
+	user := entity.User{ID: 1, Login: "testuser"}
+	expiresAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("delivered", func(t *testing.T) {
+		var got passwordResetDto
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		n := NewWebhookNotifier(logger.NewLogger(), server.URL)
+		defer n.Close()
+
+		err := n.SendPasswordReset(context.Background(), user, "reset-token", expiresAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, passwordResetDto{
+			Type:      "password_reset",
+			Login:     "testuser",
+			Token:     "reset-token",
+			ExpiresAt: expiresAt,
+		}, got)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		n := NewWebhookNotifier(logger.NewLogger(), server.URL)
+		defer n.Close()
+
+		assert.Error(t, n.SendPasswordReset(context.Background(), user, "reset-token", expiresAt))
+	})
+}
@@ -10,10 +10,6 @@ import (
 	"github.com/MxTrap/gophermart/logger"
 )
 
-type storageService interface {
-	Push(order entity.Order)
-}
-
 type orderRepository interface {
 	Save(ctx context.Context, order entity.Order) error
 	Find(ctx context.Context, number string) (entity.Order, error)
@@ -22,18 +18,15 @@ type orderRepository interface {
 
 type OrderService struct {
 	log       *logger.Logger
-	service   storageService
 	orderRepo orderRepository
 }
 
 func NewOrderService(
 	log *logger.Logger,
-	service storageService,
 	orderRepo orderRepository,
 ) *OrderService {
 	return &OrderService{
 		log:       log,
-		service:   service,
 		orderRepo: orderRepo,
 	}
 }
@@ -60,6 +53,7 @@ func (s *OrderService) SaveOrder(ctx context.Context, order entity.Order) error
 	order.Status = entity.OrderNew
 	order.UploadedAt = time.Now().UTC()
 
+	// Saving the order also queues it for polling of the accrual system.
 	err = s.orderRepo.Save(ctx, order)
 
 	if err != nil {
@@ -67,8 +61,6 @@ func (s *OrderService) SaveOrder(ctx context.Context, order entity.Order) error
 		return common.ErrInternalError
 	}
 
-	s.service.Push(order)
-
 	return nil
 }
 
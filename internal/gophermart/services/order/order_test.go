@@ -31,13 +31,13 @@ func TestOrderService_SaveOrder(t *testing.T) {
 	tests := []struct {
 		name        string
 		order       entity.Order
-		setupMocks  func(repo *mocks.MockOrderRepository, storage *mocks.MockStorageService)
+		setupMocks  func(repo *mocks.MockOrderRepository)
 		expectedErr error
 	}{
 		{
 			name:  "Success",
 			order: order,
-			setupMocks: func(repo *mocks.MockOrderRepository, storage *mocks.MockStorageService) {
+			setupMocks: func(repo *mocks.MockOrderRepository) {
 				repo.EXPECT().
 					Find(ctx, order.Number).
 					Return(entity.Order{}, nil)
@@ -50,21 +50,13 @@ func TestOrderService_SaveOrder(t *testing.T) {
 						assert.WithinDuration(t, validOrder.UploadedAt, o.UploadedAt, time.Second)
 						return nil
 					})
-				storage.EXPECT().
-					Push(gomock.Any()).
-					Do(func(o entity.Order) {
-						assert.Equal(t, validOrder.Number, o.Number)
-						assert.Equal(t, validOrder.UserID, o.UserID)
-						assert.Equal(t, validOrder.Status, o.Status)
-						assert.WithinDuration(t, validOrder.UploadedAt, o.UploadedAt, time.Second)
-					})
 			},
 			expectedErr: nil,
 		},
 		{
 			name:  "Invalid order number",
 			order: entity.Order{Number: "", UserID: 1},
-			setupMocks: func(repo *mocks.MockOrderRepository, storage *mocks.MockStorageService) {
+			setupMocks: func(repo *mocks.MockOrderRepository) {
 				// Никаких вызовов, так как валидация не проходит
 			},
 			expectedErr: common.ErrInvalidOrderNumber,
@@ -72,7 +64,7 @@ func TestOrderService_SaveOrder(t *testing.T) {
 		{
 			name:  "Order already exists",
 			order: order,
-			setupMocks: func(repo *mocks.MockOrderRepository, storage *mocks.MockStorageService) {
+			setupMocks: func(repo *mocks.MockOrderRepository) {
 				repo.EXPECT().
 					Find(ctx, order.Number).
 					Return(entity.Order{Number: order.Number, UserID: order.UserID}, nil)
@@ -82,7 +74,7 @@ func TestOrderService_SaveOrder(t *testing.T) {
 		{
 			name:  "Order registered by another user",
 			order: order,
-			setupMocks: func(repo *mocks.MockOrderRepository, storage *mocks.MockStorageService) {
+			setupMocks: func(repo *mocks.MockOrderRepository) {
 				repo.EXPECT().
 					Find(ctx, order.Number).
 					Return(entity.Order{Number: order.Number, UserID: 2}, nil)
@@ -92,7 +84,7 @@ func TestOrderService_SaveOrder(t *testing.T) {
 		{
 			name:  "Find error",
 			order: order,
-			setupMocks: func(repo *mocks.MockOrderRepository, storage *mocks.MockStorageService) {
+			setupMocks: func(repo *mocks.MockOrderRepository) {
 				repo.EXPECT().
 					Find(ctx, order.Number).
 					Return(entity.Order{}, errors.New("db error"))
@@ -102,7 +94,7 @@ func TestOrderService_SaveOrder(t *testing.T) {
 		{
 			name:  "Save error",
 			order: order,
-			setupMocks: func(repo *mocks.MockOrderRepository, storage *mocks.MockStorageService) {
+			setupMocks: func(repo *mocks.MockOrderRepository) {
 				repo.EXPECT().
 					Find(ctx, order.Number).
 					Return(entity.Order{}, nil)
@@ -121,11 +113,10 @@ func TestOrderService_SaveOrder(t *testing.T) {
 			defer ctrl.Finish()
 
 			repo := mocks.NewMockOrderRepository(ctrl)
-			storage := mocks.NewMockStorageService(ctrl)
 
-			tt.setupMocks(repo, storage)
+			tt.setupMocks(repo)
 
-			s := NewOrderService(log, storage, repo)
+			s := NewOrderService(log, repo)
 
 			err := s.SaveOrder(ctx, tt.order)
 
@@ -194,11 +185,10 @@ func TestOrderService_GetAll(t *testing.T) {
 			defer ctrl.Finish()
 
 			repo := mocks.NewMockOrderRepository(ctrl)
-			storage := mocks.NewMockStorageService(ctrl)
 
 			tt.setupMock(repo)
 
-			s := NewOrderService(log, storage, repo)
+			s := NewOrderService(log, repo)
 
 			orders, err := s.GetAll(ctx, userID)
 
@@ -7,6 +7,7 @@ package orderworker
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/MxTrap/gophermart/internal/gophermart/entity"
 	gomock "github.com/golang/mock/gomock"
@@ -36,18 +37,18 @@ func (m *MockaccrualService) EXPECT() *MockaccrualServiceMockRecorder {
 }
 
 // GetOrderAccrual mocks base method.
-func (m *MockaccrualService) GetOrderAccrual(number string) (entity.Order, error) {
+func (m *MockaccrualService) GetOrderAccrual(ctx context.Context, number string) (entity.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrderAccrual", number)
+	ret := m.ctrl.Call(m, "GetOrderAccrual", ctx, number)
 	ret0, _ := ret[0].(entity.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrderAccrual indicates an expected call of GetOrderAccrual.
-func (mr *MockaccrualServiceMockRecorder) GetOrderAccrual(number interface{}) *gomock.Call {
+func (mr *MockaccrualServiceMockRecorder) GetOrderAccrual(ctx, number interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderAccrual", reflect.TypeOf((*MockaccrualService)(nil).GetOrderAccrual), number)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderAccrual", reflect.TypeOf((*MockaccrualService)(nil).GetOrderAccrual), ctx, number)
 }
 
 // Mockstorage is a mock of storage interface.
@@ -73,30 +74,61 @@ func (m *Mockstorage) EXPECT() *MockstorageMockRecorder {
 	return m.recorder
 }
 
+// Fail mocks base method.
+func (m *Mockstorage) Fail(ctx context.Context, el entity.Order, cause error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, el, cause)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockstorageMockRecorder) Fail(ctx, el, cause interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*Mockstorage)(nil).Fail), ctx, el, cause)
+}
+
 // Get mocks base method.
-func (m *Mockstorage) Get(elemCount int) []entity.Order {
+func (m *Mockstorage) Get(ctx context.Context, elemCount int) ([]entity.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", elemCount)
+	ret := m.ctrl.Call(m, "Get", ctx, elemCount)
 	ret0, _ := ret[0].([]entity.Order)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockstorageMockRecorder) Get(elemCount interface{}) *gomock.Call {
+func (mr *MockstorageMockRecorder) Get(ctx, elemCount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*Mockstorage)(nil).Get), elemCount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*Mockstorage)(nil).Get), ctx, elemCount)
 }
 
 // Push mocks base method.
-func (m *Mockstorage) Push(el entity.Order) {
+func (m *Mockstorage) Push(ctx context.Context, el entity.Order) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Push", el)
+	ret := m.ctrl.Call(m, "Push", ctx, el)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Push indicates an expected call of Push.
-func (mr *MockstorageMockRecorder) Push(el interface{}) *gomock.Call {
+func (mr *MockstorageMockRecorder) Push(ctx, el interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*Mockstorage)(nil).Push), ctx, el)
+}
+
+// Release mocks base method.
+func (m *Mockstorage) Release(ctx context.Context, el entity.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, el)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockstorageMockRecorder) Release(ctx, el interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*Mockstorage)(nil).Push), el)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*Mockstorage)(nil).Release), ctx, el)
 }
 
 // MockorderBalanceRepo is a mock of orderBalanceRepo interface.
@@ -135,3 +167,52 @@ func (mr *MockorderBalanceRepoMockRecorder) UpdateOrderBalance(ctx, order interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderBalance", reflect.TypeOf((*MockorderBalanceRepo)(nil).UpdateOrderBalance), ctx, order)
 }
+
+// MockrateLimiter is a mock of rateLimiter interface.
+type MockrateLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockrateLimiterMockRecorder
+}
+
+// MockrateLimiterMockRecorder is the mock recorder for MockrateLimiter.
+type MockrateLimiterMockRecorder struct {
+	mock *MockrateLimiter
+}
+
+// NewMockrateLimiter creates a new mock instance.
+func NewMockrateLimiter(ctrl *gomock.Controller) *MockrateLimiter {
+	mock := &MockrateLimiter{ctrl: ctrl}
+	mock.recorder = &MockrateLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockrateLimiter) EXPECT() *MockrateLimiterMockRecorder {
+	return m.recorder
+}
+
+// Throttle mocks base method.
+func (m *MockrateLimiter) Throttle(retryAfter time.Duration, limit int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Throttle", retryAfter, limit)
+}
+
+// Throttle indicates an expected call of Throttle.
+func (mr *MockrateLimiterMockRecorder) Throttle(retryAfter, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Throttle", reflect.TypeOf((*MockrateLimiter)(nil).Throttle), retryAfter, limit)
+}
+
+// Wait mocks base method.
+func (m *MockrateLimiter) Wait(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Wait", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Wait indicates an expected call of Wait.
+func (mr *MockrateLimiterMockRecorder) Wait(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wait", reflect.TypeOf((*MockrateLimiter)(nil).Wait), ctx)
+}
@@ -3,31 +3,46 @@ package orderworker
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
 	"github.com/MxTrap/gophermart/logger"
 )
 
 type accrualService interface {
-	GetOrderAccrual(number string) (entity.Order, error)
+	GetOrderAccrual(ctx context.Context, number string) (entity.Order, error)
 }
 
 type storage interface {
-	Push(el entity.Order)
-	Get(elemCount int) []entity.Order
+	Push(ctx context.Context, el entity.Order) error
+	Release(ctx context.Context, el entity.Order) error
+	Fail(ctx context.Context, el entity.Order, cause error) error
+	Get(ctx context.Context, elemCount int) ([]entity.Order, error)
 }
 
 type orderBalanceRepo interface {
 	UpdateOrderBalance(ctx context.Context, order entity.Order) error
 }
 
+type rateLimiter interface {
+	Wait(ctx context.Context) error
+	Throttle(retryAfter time.Duration, limit int)
+}
+
+type circuitBreaker interface {
+	Wait(ctx context.Context) error
+}
+
 type OrderWorkerService struct {
 	log     *logger.Logger
 	svc     accrualService
 	storage storage
 	repo    orderBalanceRepo
+	limiter rateLimiter
+	breaker circuitBreaker
 }
 
 func NewOrderWorkerService(
@@ -35,22 +50,61 @@ func NewOrderWorkerService(
 	svc accrualService,
 	storage storage,
 	repo orderBalanceRepo,
+	limiter rateLimiter,
+	breaker circuitBreaker,
 ) *OrderWorkerService {
 	return &OrderWorkerService{
 		log:     log,
 		svc:     svc,
 		storage: storage,
 		repo:    repo,
+		limiter: limiter,
+		breaker: breaker,
 	}
 }
 
+var errOrderNotChanged = errors.New("order has already been processed")
+
 type result struct {
 	order entity.Order
 	err   error
 }
 
-func (*OrderWorkerService) isTerminalStatus(status string) bool {
-	return status == entity.OrderInvalid || status == entity.OrderProcessed
+func (*OrderWorkerService) isTerminalStatus(status entity.OrderStatus) bool {
+	return status.IsFinal()
+}
+
+// checkTransition rejects a status reported by the accrual system which the
+// order cannot move to from its current status, e.g. a regression from
+// PROCESSING back to NEW.
+func (s *OrderWorkerService) checkTransition(order, accrualOrder entity.Order) error {
+	if accrualOrder.Status == order.Status {
+		return errOrderNotChanged
+	}
+	if !order.Status.CanTransitionTo(accrualOrder.Status) {
+		s.log.Warnw("illegal order status transition rejected",
+			"number", order.Number, "from", order.Status, "to", accrualOrder.Status)
+		return fmt.Errorf("%w: %s -> %s", common.ErrIllegalStatusTransition, order.Status, accrualOrder.Status)
+	}
+	return nil
+}
+
+func (s *OrderWorkerService) requeue(ctx context.Context, order entity.Order) {
+	if err := s.storage.Push(ctx, order); err != nil {
+		s.log.Error("failed to requeue order: ", err)
+	}
+}
+
+func (s *OrderWorkerService) release(ctx context.Context, order entity.Order) {
+	if err := s.storage.Release(ctx, order); err != nil {
+		s.log.Error("failed to release order: ", err)
+	}
+}
+
+func (s *OrderWorkerService) fail(ctx context.Context, order entity.Order, cause error) {
+	if err := s.storage.Fail(ctx, order, cause); err != nil {
+		s.log.Error("failed to requeue order: ", err)
+	}
 }
 
 func (s *OrderWorkerService) save(ctx context.Context, ch chan result) {
@@ -60,12 +114,32 @@ func (s *OrderWorkerService) save(ctx context.Context, ch chan result) {
 			case <-ctx.Done():
 				return
 			default:
+				if errors.Is(res.err, errOrderNotChanged) {
+					s.requeue(ctx, res.order)
+					continue
+				}
+				// An order rejected by the open circuit or throttled by the
+				// accrual system was not polled, so it counts neither as a
+				// failed attempt nor as a successful one: throttling must not
+				// dead-letter the queue, nor keep a failing order from it.
+				if errors.Is(res.err, common.ErrCircuitOpen) ||
+					errors.Is(res.err, common.ErrTooManyRequests) {
+					s.release(ctx, res.order)
+					continue
+				}
 				if res.err != nil {
-					s.log.Error("failed to save ch: %v", res.err)
+					s.log.Error("failed to get order accrual: ", res.err)
+					s.fail(ctx, res.order, res.err)
+					continue
 				}
 				err := s.repo.UpdateOrderBalance(ctx, res.order)
-				if err != nil || !s.isTerminalStatus(res.order.Status) {
-					s.storage.Push(res.order)
+				if err != nil {
+					s.log.Error("failed to update order balance: ", err)
+					s.fail(ctx, res.order, err)
+					continue
+				}
+				if !s.isTerminalStatus(res.order.Status) {
+					s.requeue(ctx, res.order)
 				}
 			}
 		}
@@ -78,13 +152,24 @@ func (s *OrderWorkerService) update(ctx context.Context, inputChan chan entity.O
 	go func() {
 		defer close(resultCh)
 		for order := range inputChan {
-			accrualOrder, err := s.svc.GetOrderAccrual(order.Number)
-			if accrualOrder.Status == order.Status {
-				err = errors.New("order has already been processed")
+			if err := s.limiter.Wait(ctx); err != nil {
+				return
+			}
+
+			accrualOrder, err := s.svc.GetOrderAccrual(ctx, order.Number)
+			var rateErr *common.TooManyRequestsError
+			if errors.As(err, &rateErr) {
+				s.limiter.Throttle(rateErr.RetryAfter, rateErr.Limit)
+			}
+			if err == nil {
+				err = s.checkTransition(order, accrualOrder)
 			}
 			if err == nil {
 				accrualOrder.UserID = order.UserID
 				accrualOrder.Number = order.Number
+				accrualOrder.Attempts = order.Attempts
+			} else {
+				accrualOrder = order
 			}
 
 			select {
@@ -106,6 +191,9 @@ func (*OrderWorkerService) generate(ctx context.Context, input []entity.Order) c
 		defer close(inputCh)
 
 		for _, data := range input {
+			if ctx.Err() != nil {
+				return
+			}
 			select {
 			case <-ctx.Done():
 				return
@@ -171,7 +259,15 @@ func (s *OrderWorkerService) Run(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			default:
-				orders := s.storage.Get(jobNum)
+				// Orders are not polled while the accrual system is known to
+				// be down.
+				if err := s.breaker.Wait(ctx); err != nil {
+					return
+				}
+				orders, err := s.storage.Get(ctx, jobNum)
+				if err != nil {
+					s.log.Error("failed to get orders from queue: ", err)
+				}
 				inputCh := s.generate(ctx, orders)
 				channels := s.fanOut(ctx, inputCh)
 				resultCh := s.fanIn(ctx, channels)
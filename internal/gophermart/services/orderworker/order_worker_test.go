@@ -3,7 +3,10 @@ package orderworker
 import (
 	"context"
 	"errors"
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/accrual"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/ratelimiter"
 	"github.com/MxTrap/gophermart/logger"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -16,7 +19,7 @@ func TestOrderWorkerService_isTerminalStatus(t *testing.T) {
 	svc := &OrderWorkerService{}
 	tests := []struct {
 		name   string
-		status string
+		status entity.OrderStatus
 		want   bool
 	}{
 		{"invalid status", entity.OrderInvalid, true},
@@ -87,13 +90,14 @@ func TestOrderWorkerService_update(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAccrualService := NewMockaccrualService(ctrl)
-	svc := &OrderWorkerService{svc: mockAccrualService}
+	limiter := ratelimiter.NewRateLimiter()
+	svc := &OrderWorkerService{log: logger.NewLogger(), svc: mockAccrualService, limiter: limiter}
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
 	inputCh := make(chan entity.Order, 1)
 	order := entity.Order{Number: "123", UserID: 1, Status: entity.OrderNew}
-	var accrualTestNum float32 = 100.0
+	var accrualTestNum entity.Money = 10000
 	accrualOrder := entity.Order{Number: "123", Status: entity.OrderProcessed, Accrual: &accrualTestNum}
 
 	t.Run("successful update", func(t *testing.T) {
@@ -101,7 +105,7 @@ func TestOrderWorkerService_update(t *testing.T) {
 		close(inputCh)
 
 		mockAccrualService.EXPECT().
-			GetOrderAccrual(order.Number).
+			GetOrderAccrual(gomock.Any(), order.Number).
 			Return(accrualOrder, nil)
 
 		resultCh := svc.update(ctx, inputCh)
@@ -118,7 +122,7 @@ func TestOrderWorkerService_update(t *testing.T) {
 		close(inputCh)
 
 		mockAccrualService.EXPECT().
-			GetOrderAccrual(sameStatusOrder.Number).
+			GetOrderAccrual(gomock.Any(), sameStatusOrder.Number).
 			Return(entity.Order{Status: sameStatusOrder.Status}, nil)
 
 		resultCh := svc.update(ctx, inputCh)
@@ -128,6 +132,23 @@ func TestOrderWorkerService_update(t *testing.T) {
 		assert.Equal(t, "order has already been processed", results[0].err.Error())
 	})
 
+	t.Run("illegal status transition", func(t *testing.T) {
+		inputCh = make(chan entity.Order, 1)
+		processingOrder := entity.Order{Number: "123", UserID: 1, Status: entity.OrderProcessing}
+		inputCh <- processingOrder
+		close(inputCh)
+
+		mockAccrualService.EXPECT().
+			GetOrderAccrual(gomock.Any(), processingOrder.Number).
+			Return(entity.Order{Status: entity.OrderNew}, nil)
+
+		resultCh := svc.update(ctx, inputCh)
+		results := collectResults(resultCh)
+		assert.Len(t, results, 1)
+		assert.ErrorIs(t, results[0].err, common.ErrIllegalStatusTransition)
+		assert.Equal(t, processingOrder, results[0].order)
+	})
+
 	t.Run("accrual service error", func(t *testing.T) {
 		inputCh = make(chan entity.Order, 1)
 		inputCh <- order
@@ -135,13 +156,31 @@ func TestOrderWorkerService_update(t *testing.T) {
 
 		accrualErr := errors.New("accrual error")
 		mockAccrualService.EXPECT().
-			GetOrderAccrual(order.Number).
+			GetOrderAccrual(gomock.Any(), order.Number).
 			Return(entity.Order{}, accrualErr)
 
 		resultCh := svc.update(ctx, inputCh)
 		results := collectResults(resultCh)
 		assert.Len(t, results, 1)
 		assert.ErrorIs(t, results[0].err, accrualErr)
+		assert.Equal(t, order, results[0].order)
+	})
+
+	t.Run("too many requests throttles limiter", func(t *testing.T) {
+		inputCh = make(chan entity.Order, 1)
+		inputCh <- order
+		close(inputCh)
+
+		mockAccrualService.EXPECT().
+			GetOrderAccrual(gomock.Any(), order.Number).
+			Return(entity.Order{}, &common.TooManyRequestsError{RetryAfter: 10 * time.Millisecond, Limit: 600})
+
+		resultCh := svc.update(ctx, inputCh)
+		results := collectResults(resultCh)
+		assert.Len(t, results, 1)
+		assert.ErrorIs(t, results[0].err, common.ErrTooManyRequests)
+		assert.Equal(t, order, results[0].order)
+		assert.Equal(t, 100*time.Millisecond, limiter.Interval())
 	})
 }
 
@@ -151,7 +190,9 @@ func TestOrderWorkerService_fanOut(t *testing.T) {
 
 	mockAccrualService := NewMockaccrualService(ctrl)
 	svc := &OrderWorkerService{
-		svc: mockAccrualService,
+		log:     logger.NewLogger(),
+		svc:     mockAccrualService,
+		limiter: ratelimiter.NewRateLimiter(),
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
@@ -162,11 +203,11 @@ func TestOrderWorkerService_fanOut(t *testing.T) {
 	close(inputCh)
 
 	mockAccrualService.EXPECT().
-		GetOrderAccrual("123").
+		GetOrderAccrual(gomock.Any(), "123").
 		Return(entity.Order{Number: "123", UserID: 1}, nil)
 
 	mockAccrualService.EXPECT().
-		GetOrderAccrual("456").
+		GetOrderAccrual(gomock.Any(), "456").
 		Return(entity.Order{Number: "456", UserID: 2}, nil)
 
 	channels := svc.fanOut(ctx, inputCh)
@@ -234,12 +275,18 @@ func TestOrderWorkerService_save(t *testing.T) {
 	t.Run("save orders", func(t *testing.T) {
 		mockRepo.EXPECT().
 			UpdateOrderBalance(ctx, gomock.Any()).
-			Times(2).
+			Times(1).
 			Return(nil)
 
 		mockStorage.EXPECT().
-			Push(gomock.Any()).
-			Times(1) // Только для не терминального статуса (OrderNew)
+			Push(ctx, gomock.Any()).
+			Times(1). // Только для не терминального статуса (OrderNew)
+			Return(nil)
+
+		mockStorage.EXPECT().
+			Fail(ctx, entity.Order{Number: "456", Status: entity.OrderProcessed}, gomock.Any()).
+			Times(1). // Для ошибки accrual
+			Return(nil)
 
 		svc.save(ctx, resultCh)
 		time.Sleep(100 * time.Millisecond) // Даем время горутине обработать
@@ -256,8 +303,51 @@ func TestOrderWorkerService_save(t *testing.T) {
 			Return(updateErr)
 
 		mockStorage.EXPECT().
-			Push(gomock.Any()).
-			Times(1)
+			Fail(ctx, gomock.Any(), updateErr).
+			Times(1).
+			Return(nil)
+
+		svc.save(ctx, resultCh)
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	t.Run("order rejected by open circuit is released", func(t *testing.T) {
+		resultCh = make(chan result, 1)
+		resultCh <- result{order: entity.Order{Number: "123", Status: entity.OrderNew, Attempts: 1}, err: common.ErrCircuitOpen}
+		close(resultCh)
+
+		mockStorage.EXPECT().
+			Release(ctx, entity.Order{Number: "123", Status: entity.OrderNew, Attempts: 1}).
+			Times(1).
+			Return(nil)
+
+		svc.save(ctx, resultCh)
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	t.Run("throttled order is released", func(t *testing.T) {
+		resultCh = make(chan result, 1)
+		resultCh <- result{order: entity.Order{Number: "123", Status: entity.OrderNew, Attempts: 9}, err: &common.TooManyRequestsError{RetryAfter: time.Minute}}
+		close(resultCh)
+
+		mockStorage.EXPECT().
+			Release(ctx, entity.Order{Number: "123", Status: entity.OrderNew, Attempts: 9}).
+			Times(1).
+			Return(nil)
+
+		svc.save(ctx, resultCh)
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	t.Run("unchanged order is polled again", func(t *testing.T) {
+		resultCh = make(chan result, 1)
+		resultCh <- result{order: entity.Order{Number: "123", Status: entity.OrderProcessing, Attempts: 3}, err: errOrderNotChanged}
+		close(resultCh)
+
+		mockStorage.EXPECT().
+			Push(ctx, entity.Order{Number: "123", Status: entity.OrderProcessing, Attempts: 3}).
+			Times(1).
+			Return(nil)
 
 		svc.save(ctx, resultCh)
 		time.Sleep(100 * time.Millisecond)
@@ -272,7 +362,14 @@ func TestOrderWorkerService_Run(t *testing.T) {
 	mockAccrualService := NewMockaccrualService(ctrl)
 	mockRepo := NewMockorderBalanceRepo(ctrl)
 	log := logger.NewLogger()
-	svc := &OrderWorkerService{log: log, svc: mockAccrualService, storage: mockStorage, repo: mockRepo}
+	svc := &OrderWorkerService{
+		log:     log,
+		svc:     mockAccrualService,
+		storage: mockStorage,
+		repo:    mockRepo,
+		limiter: ratelimiter.NewRateLimiter(),
+		breaker: accrual.NewCircuitBreaker(log, 5, time.Minute),
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 	defer cancel()
@@ -280,16 +377,16 @@ func TestOrderWorkerService_Run(t *testing.T) {
 	orders := []entity.Order{
 		{Number: "123", UserID: 1, Status: entity.OrderNew},
 	}
-	var accrualTestNum float32 = 100
+	var accrualTestNum entity.Money = 10000
 	accrualOrder := entity.Order{Number: "123", Status: entity.OrderProcessed, Accrual: &accrualTestNum}
 
 	mockStorage.EXPECT().
-		Get(5).
-		Return(orders).
+		Get(ctx, 5).
+		Return(orders, nil).
 		Times(1)
 
 	mockAccrualService.EXPECT().
-		GetOrderAccrual(orders[0].Number).
+		GetOrderAccrual(gomock.Any(), orders[0].Number).
 		Return(accrualOrder, nil)
 
 	mockRepo.EXPECT().
@@ -300,6 +397,73 @@ func TestOrderWorkerService_Run(t *testing.T) {
 	time.Sleep(150 * time.Millisecond) // Даем время для одного цикла
 }
 
+func TestOrderWorkerService_Run_OpenCircuit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	log := logger.NewLogger()
+	breaker := accrual.NewCircuitBreaker(log, 1, time.Hour)
+	breaker.Failure()
+
+	// Пока цепь разомкнута, очередь не читается.
+	mockStorage := NewMockstorage(ctrl)
+	svc := &OrderWorkerService{
+		log:     log,
+		svc:     NewMockaccrualService(ctrl),
+		storage: mockStorage,
+		repo:    NewMockorderBalanceRepo(ctrl),
+		limiter: ratelimiter.NewRateLimiter(),
+		breaker: breaker,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	svc.Run(ctx)
+	time.Sleep(150 * time.Millisecond)
+}
+
+func TestOrderWorkerService_Run_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := NewMockstorage(ctrl)
+	mockAccrualService := NewMockaccrualService(ctrl)
+	log := logger.NewLogger()
+	svc := &OrderWorkerService{
+		log:     log,
+		svc:     mockAccrualService,
+		storage: mockStorage,
+		repo:    NewMockorderBalanceRepo(ctrl),
+		limiter: ratelimiter.NewRateLimiter(),
+		breaker: accrual.NewCircuitBreaker(log, 5, time.Minute),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// Заказ на последней попытке не должен уйти в dead letters из-за 429,
+	// но и счётчик попыток не сбрасывается: Fail и Push не ожидаются,
+	// любой их вызов провалит тест.
+	orders := []entity.Order{
+		{Number: "123", UserID: 1, Status: entity.OrderNew, Attempts: 9},
+		{Number: "456", UserID: 2, Status: entity.OrderProcessing, Attempts: 9},
+	}
+	mockStorage.EXPECT().
+		Get(ctx, 5).
+		Return(orders, nil).
+		Times(1)
+	mockAccrualService.EXPECT().
+		GetOrderAccrual(gomock.Any(), gomock.Any()).
+		Return(entity.Order{}, &common.TooManyRequestsError{RetryAfter: time.Millisecond, Limit: 60}).
+		Times(2)
+	mockStorage.EXPECT().Release(ctx, orders[0]).Return(nil)
+	mockStorage.EXPECT().Release(ctx, orders[1]).Return(nil)
+
+	svc.Run(ctx)
+	time.Sleep(150 * time.Millisecond)
+}
+
 // Вспомогательная функция для сбора результатов из канала
 func collectResults(ch chan result) []result {
 	var results []result
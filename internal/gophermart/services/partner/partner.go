@@ -0,0 +1,66 @@
+package partner
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+type userFinder interface {
+	FindUserByUsername(ctx context.Context, username string) (entity.User, error)
+	FindUserByExternalID(ctx context.Context, externalID string) (entity.User, error)
+}
+
+type orderSaver interface {
+	SaveOrder(ctx context.Context, order entity.Order) error
+}
+
+// PartnerService serves partner systems registering orders on behalf of
+// users.
+type PartnerService struct {
+	log    *logger.Logger
+	users  userFinder
+	orders orderSaver
+}
+
+func NewPartnerService(log *logger.Logger, users userFinder, orders orderSaver) *PartnerService {
+	return &PartnerService{
+		log:    log,
+		users:  users,
+		orders: orders,
+	}
+}
+
+// RegisterOrder registers the order number for the customer, the same way
+// as the user would do it.
+func (s *PartnerService) RegisterOrder(ctx context.Context, key entity.APIKey, customer entity.PartnerCustomer, number string) error {
+	log := s.log.With("op", "PartnerService.RegisterOrder", "key_id", key.ID, "number", number)
+
+	var (
+		user entity.User
+		err  error
+	)
+	if customer.Login != "" {
+		user, err = s.users.FindUserByUsername(ctx, customer.Login)
+	} else {
+		user, err = s.users.FindUserByExternalID(ctx, customer.ExternalID)
+	}
+	if err != nil {
+		if errors.Is(err, common.ErrUserNotFound) {
+			return common.ErrUserNotFound
+		}
+		log.Error(err)
+		return common.ErrInternalError
+	}
+
+	err = s.orders.SaveOrder(ctx, entity.Order{UserID: user.ID, Number: number})
+	if err != nil {
+		return err
+	}
+	log.Infow("order registered by partner", "user_id", user.ID)
+
+	return nil
+}
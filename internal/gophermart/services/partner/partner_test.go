@@ -0,0 +1,86 @@
+package partner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartnerService_RegisterOrder(t *testing.T) {
+	ctx := context.Background()
+	key := entity.APIKey{ID: 3, Scopes: []entity.APIKeyScope{entity.ScopeOrdersWrite}}
+	user := entity.User{ID: 1, Login: "testuser"}
+	order := entity.Order{UserID: 1, Number: "12345678903"}
+
+	tests := []struct {
+		name        string
+		customer    entity.PartnerCustomer
+		setupMocks  func(users *MockuserFinder, orders *MockorderSaver)
+		expectedErr error
+	}{
+		{
+			name:     "by login",
+			customer: entity.PartnerCustomer{Login: "testuser"},
+			setupMocks: func(users *MockuserFinder, orders *MockorderSaver) {
+				users.EXPECT().FindUserByUsername(ctx, "testuser").Return(user, nil)
+				orders.EXPECT().SaveOrder(ctx, order).Return(nil)
+			},
+		},
+		{
+			name:     "by external id",
+			customer: entity.PartnerCustomer{ExternalID: "pos-42"},
+			setupMocks: func(users *MockuserFinder, orders *MockorderSaver) {
+				users.EXPECT().FindUserByExternalID(ctx, "pos-42").Return(user, nil)
+				orders.EXPECT().SaveOrder(ctx, order).Return(nil)
+			},
+		},
+		{
+			name:     "unknown user",
+			customer: entity.PartnerCustomer{ExternalID: "pos-43"},
+			setupMocks: func(users *MockuserFinder, orders *MockorderSaver) {
+				users.EXPECT().FindUserByExternalID(ctx, "pos-43").Return(entity.User{}, common.ErrUserNotFound)
+			},
+			expectedErr: common.ErrUserNotFound,
+		},
+		{
+			name:     "user lookup error",
+			customer: entity.PartnerCustomer{Login: "testuser"},
+			setupMocks: func(users *MockuserFinder, orders *MockorderSaver) {
+				users.EXPECT().FindUserByUsername(ctx, "testuser").Return(entity.User{}, errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			// Ошибки регистрации заказа возвращаются как есть
+			name:     "order of another user",
+			customer: entity.PartnerCustomer{Login: "testuser"},
+			setupMocks: func(users *MockuserFinder, orders *MockorderSaver) {
+				users.EXPECT().FindUserByUsername(ctx, "testuser").Return(user, nil)
+				orders.EXPECT().SaveOrder(ctx, order).Return(common.ErrOrderRegisteredByAnother)
+			},
+			expectedErr: common.ErrOrderRegisteredByAnother,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := NewMockuserFinder(ctrl)
+			orders := NewMockorderSaver(ctrl)
+			tt.setupMocks(users, orders)
+
+			s := NewPartnerService(logger.NewLogger(), users, orders)
+			err := s.RegisterOrder(ctx, key, tt.customer, order.Number)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
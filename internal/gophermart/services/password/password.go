@@ -0,0 +1,213 @@
+package password
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+type userRepo interface {
+	FindUserByUsername(ctx context.Context, username string) (entity.User, error)
+	FindUserByID(ctx context.Context, userID int64) (entity.User, error)
+	UpdatePassword(ctx context.Context, userID int64, password string) error
+}
+
+type resetRepo interface {
+	Save(ctx context.Context, reset entity.PasswordReset) error
+	Consume(ctx context.Context, hash string) (int64, error)
+}
+
+type passwordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, encoded string) (ok bool, rehash bool, err error)
+}
+
+type sessionRevoker interface {
+	LogoutAll(ctx context.Context, userID int64) error
+}
+
+type loginGuard interface {
+	Reserve(ctx context.Context, login string, ip string) error
+	ReserveReset(ctx context.Context, login string, ip string) error
+	Succeed(ctx context.Context, login string, ip string) error
+}
+
+// notifier delivers reset tokens to their users, e.g. by mail.
+type notifier interface {
+	SendPasswordReset(ctx context.Context, user entity.User, token entity.Token, expiresAt time.Time) error
+}
+
+type PasswordService struct {
+	log      *logger.Logger
+	users    userRepo
+	hasher   passwordHasher
+	resets   resetRepo
+	sessions sessionRevoker
+	guard    loginGuard
+	notifier notifier
+	resetTTL time.Duration
+}
+
+func NewPasswordService(
+	log *logger.Logger,
+	users userRepo,
+	hasher passwordHasher,
+	resets resetRepo,
+	sessions sessionRevoker,
+	guard loginGuard,
+	notifier notifier,
+	resetTTL time.Duration,
+) *PasswordService {
+	return &PasswordService{
+		log:      log,
+		users:    users,
+		hasher:   hasher,
+		resets:   resets,
+		sessions: sessions,
+		guard:    guard,
+		notifier: notifier,
+		resetTTL: resetTTL,
+	}
+}
+
+// ChangePassword sets a new password after checking the current one. All
+// sessions of the user are revoked, including the one of the request. The
+// check is throttled as login is: a stolen session must not allow guessing
+// the password, so a *common.TooManyRequestsError is returned while the
+// login or ip is locked.
+func (s *PasswordService) ChangePassword(ctx context.Context, userID int64, current string, password string, ip string) error {
+	log := s.log.With("op", "PasswordService.ChangePassword", "uid", userID, "ip", ip)
+
+	user, err := s.users.FindUserByID(ctx, userID)
+	if err != nil {
+		log.Error("failed to find user", err)
+		return common.ErrInternalError
+	}
+
+	if err := s.guard.Reserve(ctx, user.Login, ip); err != nil {
+		if errors.Is(err, common.ErrTooManyRequests) {
+			log.Info("login is locked")
+			return err
+		}
+		log.Error(err)
+		return common.ErrInternalError
+	}
+	ok, _, err := s.hasher.Verify(current, user.Password)
+	if err != nil {
+		log.Error("failed to verify password", err)
+		return common.ErrInternalError
+	}
+	if !ok {
+		log.Info("invalid current password")
+		return common.ErrInvalidCredentials
+	}
+	if err := s.guard.Succeed(ctx, user.Login, ip); err != nil {
+		log.Error("failed to release login attempt", err)
+	}
+
+	return s.setPassword(ctx, userID, password)
+}
+
+// RequestReset sends a reset token to the user with the given login, the
+// request coming from ip. Requests are throttled per login and per ip with a
+// *common.TooManyRequestsError. Once the login is looked up, the result is
+// nil whether it exists or not and whether the token is delivered or not, so
+// that the response does not tell which logins exist; failures are logged.
+func (s *PasswordService) RequestReset(ctx context.Context, login string, ip string) error {
+	log := s.log.With("op", "PasswordService.RequestReset", "login", login, "ip", ip)
+
+	if err := s.guard.ReserveReset(ctx, login, ip); err != nil {
+		if errors.Is(err, common.ErrTooManyRequests) {
+			log.Info("password reset requests are locked")
+			return err
+		}
+		log.Error(err)
+		return common.ErrInternalError
+	}
+
+	user, err := s.users.FindUserByUsername(ctx, login)
+	if err != nil {
+		if errors.Is(err, common.ErrUserNotFound) {
+			log.Info("password reset requested for unknown login")
+			return nil
+		}
+		log.Error("failed to find user", err)
+		return common.ErrInternalError
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Error("failed to generate reset token", err)
+		return nil
+	}
+	token := entity.Token(base64.RawURLEncoding.EncodeToString(raw))
+	reset := entity.PasswordReset{
+		UserID:    user.ID,
+		Hash:      hashToken(token),
+		ExpiresAt: time.Now().Add(s.resetTTL),
+	}
+
+	if err := s.resets.Save(ctx, reset); err != nil {
+		log.Error("failed to save reset token", err)
+		return nil
+	}
+	if err := s.notifier.SendPasswordReset(ctx, user, token, reset.ExpiresAt); err != nil {
+		log.Error("failed to send reset token", err)
+		return nil
+	}
+	log.Info("reset token sent")
+
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token. The token is
+// accepted once and all sessions of the user are revoked.
+func (s *PasswordService) ResetPassword(ctx context.Context, token entity.Token, password string) error {
+	log := s.log.With("op", "PasswordService.ResetPassword")
+
+	userID, err := s.resets.Consume(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidToken) {
+			return common.ErrInvalidToken
+		}
+		log.Error("failed to consume reset token", err)
+		return common.ErrInternalError
+	}
+
+	return s.setPassword(ctx, userID, password)
+}
+
+func (s *PasswordService) setPassword(ctx context.Context, userID int64, password string) error {
+	log := s.log.With("op", "PasswordService.setPassword", "uid", userID)
+
+	passHash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Error("failed to generate password hash", err)
+		return common.ErrInternalError
+	}
+	if err := s.users.UpdatePassword(ctx, userID, passHash); err != nil {
+		log.Error("failed to update password", err)
+		return common.ErrInternalError
+	}
+	// Sessions opened with the old password must not outlive it.
+	if err := s.sessions.LogoutAll(ctx, userID); err != nil {
+		log.Error("failed to revoke sessions", err)
+		return common.ErrInternalError
+	}
+	log.Info("password changed")
+
+	return nil
+}
+
+func hashToken(token entity.Token) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
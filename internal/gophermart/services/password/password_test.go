@@ -0,0 +1,283 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/services/hasher"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type testDeps struct {
+	users    *MockuserRepo
+	resets   *MockresetRepo
+	sessions *MocksessionRevoker
+	guard    *MockloginGuard
+	notifier *Mocknotifier
+	hasher   *hasher.PasswordHasher
+}
+
+func newTestService(t *testing.T, ctrl *gomock.Controller) (*PasswordService, testDeps) {
+	passwordHasher, err := hasher.NewPasswordHasher(hasher.Argon2Params{Memory: 64, Time: 1, Threads: 1})
+	assert.NoError(t, err)
+	deps := testDeps{
+		users:    NewMockuserRepo(ctrl),
+		resets:   NewMockresetRepo(ctrl),
+		sessions: NewMocksessionRevoker(ctrl),
+		guard:    NewMockloginGuard(ctrl),
+		notifier: NewMocknotifier(ctrl),
+		hasher:   passwordHasher,
+	}
+	svc := NewPasswordService(logger.NewLogger(), deps.users, deps.hasher, deps.resets, deps.sessions, deps.guard, deps.notifier, 30*time.Minute)
+	return svc, deps
+}
+
+// expectPassword checks that the stored hash matches password.
+func expectPassword(t *testing.T, deps testDeps, userID int64, password string) {
+	deps.users.EXPECT().
+		UpdatePassword(gomock.Any(), userID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, hash string) error {
+			ok, _, err := deps.hasher.Verify(password, hash)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			return nil
+		})
+}
+
+func TestPasswordService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	// Текущий пароль может быть захеширован ещё bcrypt
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	user := entity.User{ID: 1, Login: "testuser", Password: string(hash)}
+	ip := "192.0.2.1"
+
+	tests := []struct {
+		name        string
+		current     string
+		setupMocks  func(t *testing.T, deps testDeps)
+		expectedErr error
+	}{
+		{
+			name:    "success",
+			current: "password123",
+			setupMocks: func(t *testing.T, deps testDeps) {
+				deps.users.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil)
+				deps.guard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
+				deps.guard.EXPECT().Succeed(ctx, user.Login, ip).Return(nil)
+				expectPassword(t, deps, user.ID, "newpassword")
+				deps.sessions.EXPECT().LogoutAll(ctx, user.ID).Return(nil)
+			},
+		},
+		{
+			name:    "invalid current password",
+			current: "wrongpassword",
+			setupMocks: func(t *testing.T, deps testDeps) {
+				deps.users.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil)
+				deps.guard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
+			},
+			expectedErr: common.ErrInvalidCredentials,
+		},
+		{
+			// Пароль не проверяется, пока логин заблокирован
+			name:    "locked",
+			current: "password123",
+			setupMocks: func(t *testing.T, deps testDeps) {
+				deps.users.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil)
+				deps.guard.EXPECT().Reserve(ctx, user.Login, ip).Return(&common.TooManyRequestsError{RetryAfter: time.Minute})
+			},
+			expectedErr: common.ErrTooManyRequests,
+		},
+		{
+			name:    "guard error",
+			current: "password123",
+			setupMocks: func(t *testing.T, deps testDeps) {
+				deps.users.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil)
+				deps.guard.EXPECT().Reserve(ctx, user.Login, ip).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			name:    "find user error",
+			current: "password123",
+			setupMocks: func(t *testing.T, deps testDeps) {
+				deps.users.EXPECT().FindUserByID(ctx, user.ID).Return(entity.User{}, errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			name:    "revoke sessions error",
+			current: "password123",
+			setupMocks: func(t *testing.T, deps testDeps) {
+				deps.users.EXPECT().FindUserByID(ctx, user.ID).Return(user, nil)
+				deps.guard.EXPECT().Reserve(ctx, user.Login, ip).Return(nil)
+				deps.guard.EXPECT().Succeed(ctx, user.Login, ip).Return(nil)
+				expectPassword(t, deps, user.ID, "newpassword")
+				deps.sessions.EXPECT().LogoutAll(ctx, user.ID).Return(common.ErrInternalError)
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, deps := newTestService(t, ctrl)
+			tt.setupMocks(t, deps)
+
+			err := svc.ChangePassword(ctx, user.ID, tt.current, "newpassword", ip)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestPasswordService_Reset(t *testing.T) {
+	ctx := context.Background()
+	user := entity.User{ID: 1, Login: "testuser"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestService(t, ctrl)
+
+	// Токен перехватывается нотификатором, в базе хранится только его хеш
+	var token entity.Token
+	var saved entity.PasswordReset
+	deps.guard.EXPECT().ReserveReset(ctx, user.Login, "192.0.2.1").Return(nil)
+	deps.users.EXPECT().FindUserByUsername(ctx, user.Login).Return(user, nil)
+	deps.resets.EXPECT().
+		Save(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, reset entity.PasswordReset) error {
+			saved = reset
+			return nil
+		})
+	deps.notifier.EXPECT().
+		SendPasswordReset(ctx, user, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ entity.User, sent entity.Token, expiresAt time.Time) error {
+			token = sent
+			assert.Equal(t, saved.ExpiresAt, expiresAt)
+			return nil
+		})
+
+	assert.NoError(t, svc.RequestReset(ctx, user.Login, "192.0.2.1"))
+	assert.NotEmpty(t, token)
+	assert.Equal(t, user.ID, saved.UserID)
+	assert.Equal(t, hashToken(token), saved.Hash)
+	assert.NotEqual(t, string(token), saved.Hash)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), saved.ExpiresAt, time.Second)
+
+	deps.resets.EXPECT().Consume(ctx, saved.Hash).Return(user.ID, nil)
+	expectPassword(t, deps, user.ID, "newpassword")
+	deps.sessions.EXPECT().LogoutAll(ctx, user.ID).Return(nil)
+
+	assert.NoError(t, svc.ResetPassword(ctx, token, "newpassword"))
+}
+
+func TestPasswordService_RequestReset(t *testing.T) {
+	ctx := context.Background()
+	user := entity.User{ID: 1, Login: "testuser"}
+	ip := "192.0.2.1"
+
+	tests := []struct {
+		name        string
+		setupMocks  func(deps testDeps)
+		expectedErr error
+	}{
+		{
+			name: "unknown login",
+			setupMocks: func(deps testDeps) {
+				deps.guard.EXPECT().ReserveReset(ctx, user.Login, ip).Return(nil)
+				deps.users.EXPECT().FindUserByUsername(ctx, user.Login).Return(entity.User{}, common.ErrUserNotFound)
+			},
+		},
+		{
+			name: "locked",
+			setupMocks: func(deps testDeps) {
+				deps.guard.EXPECT().
+					ReserveReset(ctx, user.Login, ip).
+					Return(&common.TooManyRequestsError{RetryAfter: time.Minute})
+			},
+			expectedErr: common.ErrTooManyRequests,
+		},
+		{
+			name: "guard error",
+			setupMocks: func(deps testDeps) {
+				deps.guard.EXPECT().ReserveReset(ctx, user.Login, ip).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			name: "find user error",
+			setupMocks: func(deps testDeps) {
+				deps.guard.EXPECT().ReserveReset(ctx, user.Login, ip).Return(nil)
+				deps.users.EXPECT().FindUserByUsername(ctx, user.Login).Return(entity.User{}, errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			// Ответ не должен выдавать, что логин существует
+			name: "save error",
+			setupMocks: func(deps testDeps) {
+				deps.guard.EXPECT().ReserveReset(ctx, user.Login, ip).Return(nil)
+				deps.users.EXPECT().FindUserByUsername(ctx, user.Login).Return(user, nil)
+				deps.resets.EXPECT().Save(ctx, gomock.Any()).Return(errors.New("db error"))
+			},
+		},
+		{
+			name: "notifier error",
+			setupMocks: func(deps testDeps) {
+				deps.guard.EXPECT().ReserveReset(ctx, user.Login, ip).Return(nil)
+				deps.users.EXPECT().FindUserByUsername(ctx, user.Login).Return(user, nil)
+				deps.resets.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+				deps.notifier.EXPECT().
+					SendPasswordReset(ctx, user, gomock.Any(), gomock.Any()).
+					Return(errors.New("smtp error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, deps := newTestService(t, ctrl)
+			tt.setupMocks(deps)
+
+			assert.ErrorIs(t, svc.RequestReset(ctx, user.Login, ip), tt.expectedErr)
+		})
+	}
+}
+
+func TestPasswordService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		consumeErr  error
+		expectedErr error
+	}{
+		{"invalid token", common.ErrInvalidToken, common.ErrInvalidToken},
+		{"consume error", errors.New("db error"), common.ErrInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc, deps := newTestService(t, ctrl)
+			deps.resets.EXPECT().Consume(ctx, hashToken("token")).Return(int64(0), tt.consumeErr)
+
+			assert.ErrorIs(t, svc.ResetPassword(ctx, "token", "newpassword"), tt.expectedErr)
+		})
+	}
+}
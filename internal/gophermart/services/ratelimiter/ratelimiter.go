@@ -0,0 +1,89 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultRetryAfter = time.Minute
+
+// RateLimiter spaces out requests to an external system. It is shared by all
+// workers: once the remote side answers with 429, every caller of Wait is
+// paused until the advertised window reopens, and the request rate is adapted
+// to the advertised limit.
+type RateLimiter struct {
+	mu          sync.Mutex
+	interval    time.Duration
+	next        time.Time
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		now: time.Now,
+	}
+}
+
+// Wait blocks until the caller is allowed to send the next request or ctx is
+// done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if now.Before(l.next) {
+		return l.next.Sub(now)
+	}
+	l.next = now.Add(l.interval)
+
+	return 0
+}
+
+// Throttle pauses all callers for retryAfter and, when limit is positive,
+// limits the rate to limit requests per minute.
+func (l *RateLimiter) Throttle(retryAfter time.Duration, limit int) {
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit > 0 {
+		l.interval = time.Minute / time.Duration(limit)
+	}
+	until := l.now().Add(retryAfter)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// Interval returns the current minimal delay between two requests.
+func (l *RateLimiter) Interval() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.interval
+}
@@ -0,0 +1,87 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(now *time.Time) *RateLimiter {
+	l := NewRateLimiter()
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestRateLimiter_reserve(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("unlimited by default", func(t *testing.T) {
+		l := newTestLimiter(&now)
+		for i := 0; i < 10; i++ {
+			assert.Zero(t, l.reserve())
+		}
+	})
+
+	t.Run("throttle pauses and adapts rate", func(t *testing.T) {
+		current := now
+		l := newTestLimiter(&current)
+
+		l.Throttle(30*time.Second, 60)
+		assert.Equal(t, time.Second, l.Interval())
+		assert.Equal(t, 30*time.Second, l.reserve())
+
+		current = now.Add(30 * time.Second)
+		assert.Zero(t, l.reserve())
+		assert.Equal(t, time.Second, l.reserve())
+
+		current = current.Add(time.Second)
+		assert.Zero(t, l.reserve())
+	})
+
+	t.Run("default retry after", func(t *testing.T) {
+		l := newTestLimiter(&now)
+		l.Throttle(0, 0)
+		assert.Equal(t, defaultRetryAfter, l.reserve())
+		assert.Zero(t, l.Interval())
+	})
+
+	t.Run("shorter pause does not shorten existing one", func(t *testing.T) {
+		l := newTestLimiter(&now)
+		l.Throttle(time.Minute, 0)
+		l.Throttle(time.Second, 0)
+		assert.Equal(t, time.Minute, l.reserve())
+	})
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Run("all waiters are paused", func(t *testing.T) {
+		l := NewRateLimiter()
+		l.Throttle(100*time.Millisecond, 0)
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, l.Wait(context.Background()))
+				assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		l := NewRateLimiter()
+		l.Throttle(time.Minute, 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := l.Wait(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
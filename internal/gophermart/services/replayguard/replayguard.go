@@ -0,0 +1,64 @@
+package replayguard
+
+import (
+	"context"
+	"time"
+
+	"github.com/MxTrap/gophermart/logger"
+)
+
+const cleanupInterval = time.Hour
+
+type nonceRepo interface {
+	Remember(ctx context.Context, keyID int64, nonce string, now time.Time, expiresAt time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// ReplayGuard rejects signed requests seen before. Nonces are stored in the
+// database, so that a request is accepted once by all replicas, and kept
+// until the timestamps of their requests leave the allowed skew: after that
+// the timestamp check rejects replays anyway.
+type ReplayGuard struct {
+	log  *logger.Logger
+	repo nonceRepo
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewReplayGuard returns a guard keeping nonces for ttl, which should be
+// twice the allowed skew of timestamps.
+func NewReplayGuard(log *logger.Logger, repo nonceRepo, ttl time.Duration) *ReplayGuard {
+	return &ReplayGuard{
+		log:  log,
+		repo: repo,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Remember records the nonce of the key and reports false if it has been
+// recorded before.
+func (g *ReplayGuard) Remember(ctx context.Context, keyID int64, nonce string) (bool, error) {
+	now := g.now()
+	return g.repo.Remember(ctx, keyID, nonce, now, now.Add(g.ttl))
+}
+
+// Run periodically removes the expired nonces until ctx is done.
+func (g *ReplayGuard) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := g.repo.DeleteExpired(ctx, g.now())
+			if err != nil {
+				g.log.Error("failed to delete expired nonces: ", err)
+				continue
+			}
+			g.log.Infow("expired nonces deleted", "count", deleted)
+		}
+	}
+}
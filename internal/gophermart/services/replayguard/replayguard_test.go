@@ -0,0 +1,51 @@
+package replayguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayGuard_Remember(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	ttl := 10 * time.Minute
+
+	tests := []struct {
+		name     string
+		stored   bool
+		repoErr  error
+		expected bool
+	}{
+		{name: "new nonce", stored: true, expected: true},
+		{name: "replayed nonce", stored: false, expected: false},
+		{name: "repository error", repoErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Нонс хранится, пока метка времени запроса может пройти проверку
+			repo := mocks.NewMockNonceRepository(ctrl)
+			repo.EXPECT().
+				Remember(ctx, int64(3), "nonce-000000000001", now, now.Add(ttl)).
+				Return(tt.stored, tt.repoErr)
+
+			g := NewReplayGuard(logger.NewLogger(), repo, ttl)
+			g.now = func() time.Time { return now }
+
+			ok, err := g.Remember(ctx, 3, "nonce-000000000001")
+
+			assert.ErrorIs(t, err, tt.repoErr)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+const (
+	syncInterval    = 5 * time.Second
+	cleanupInterval = time.Hour
+)
+
+type revocationRepo interface {
+	RevokeToken(ctx context.Context, token entity.RevokedToken) error
+	RevokeUser(ctx context.Context, revocation entity.UserRevocation) error
+	Active(ctx context.Context) ([]entity.RevokedToken, []entity.UserRevocation, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// RevocationService keeps track of access tokens revoked before they expire.
+// Revocations are stored in Postgres and cached in memory, so checking a
+// token does not hit the database. Revocations made by other instances are
+// picked up by the periodic sync.
+type RevocationService struct {
+	log       *logger.Logger
+	repo      revocationRepo
+	accessTTL time.Duration
+	now       func() time.Time
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+	users  map[int64]entity.UserRevocation
+}
+
+func NewRevocationService(log *logger.Logger, repo revocationRepo, accessTTL time.Duration) *RevocationService {
+	return &RevocationService{
+		log:       log,
+		repo:      repo,
+		accessTTL: accessTTL,
+		now:       time.Now,
+		tokens:    make(map[string]time.Time),
+		users:     make(map[int64]entity.UserRevocation),
+	}
+}
+
+// RevokeToken revokes a single access token, e.g. on logout.
+func (s *RevocationService) RevokeToken(ctx context.Context, claims entity.AccessClaims) error {
+	token := entity.RevokedToken{
+		ID:        claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt,
+	}
+	if err := s.repo.RevokeToken(ctx, token); err != nil {
+		s.log.Errorw("failed to revoke token", "op", "RevocationService.RevokeToken", "uid", claims.UserID, "error", err)
+		return err
+	}
+
+	s.mu.Lock()
+	s.addToken(token)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// RevokeUser revokes every access token of the user issued so far. Access
+// tokens carry their issue time in microseconds, the precision Postgres keeps,
+// so a token issued within the same microsecond is revoked as well.
+func (s *RevocationService) RevokeUser(ctx context.Context, userID int64) error {
+	now := s.now()
+	revocation := entity.UserRevocation{
+		UserID:        userID,
+		RevokedBefore: now.Truncate(time.Microsecond),
+		ExpiresAt:     now.Add(s.accessTTL),
+	}
+	if err := s.repo.RevokeUser(ctx, revocation); err != nil {
+		s.log.Errorw("failed to revoke user tokens", "op", "RevocationService.RevokeUser", "uid", userID, "error", err)
+		return err
+	}
+
+	s.mu.Lock()
+	s.addUser(revocation)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// IsRevoked reports whether the access token has been revoked.
+func (s *RevocationService) IsRevoked(claims entity.AccessClaims) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.tokens[claims.ID]; ok {
+		return true
+	}
+	user, ok := s.users[claims.UserID]
+
+	return ok && !claims.IssuedAt.After(user.RevokedBefore)
+}
+
+// Sync loads the revocations made by other instances and evicts the expired
+// ones from the cache.
+func (s *RevocationService) Sync(ctx context.Context) error {
+	tokens, users, err := s.repo.Active(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Revocations are never withdrawn, so the cache is merged rather than
+	// replaced: a revocation made while the query runs is not lost.
+	for _, token := range tokens {
+		s.addToken(token)
+	}
+	for _, user := range users {
+		s.addUser(user)
+	}
+
+	now := s.now()
+	for id, expiresAt := range s.tokens {
+		if !now.Before(expiresAt) {
+			delete(s.tokens, id)
+		}
+	}
+	for id, user := range s.users {
+		if !now.Before(user.ExpiresAt) {
+			delete(s.users, id)
+		}
+	}
+
+	return nil
+}
+
+// Run keeps the cache in sync with the database and periodically removes
+// expired revocations until ctx is done.
+func (s *RevocationService) Run(ctx context.Context) {
+	syncTicker := time.NewTicker(syncInterval)
+	defer syncTicker.Stop()
+	cleanupTicker := time.NewTicker(cleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-syncTicker.C:
+			if err := s.Sync(ctx); err != nil {
+				s.log.Error("failed to sync token revocations: ", err)
+			}
+		case <-cleanupTicker.C:
+			deleted, err := s.repo.DeleteExpired(ctx)
+			if err != nil {
+				s.log.Error("failed to delete expired token revocations: ", err)
+				continue
+			}
+			s.log.Infow("expired token revocations deleted", "count", deleted)
+		}
+	}
+}
+
+func (s *RevocationService) addToken(token entity.RevokedToken) {
+	s.tokens[token.ID] = token.ExpiresAt
+}
+
+func (s *RevocationService) addUser(revocation entity.UserRevocation) {
+	current, ok := s.users[revocation.UserID]
+	if !ok {
+		s.users[revocation.UserID] = revocation
+		return
+	}
+	if revocation.RevokedBefore.After(current.RevokedBefore) {
+		current.RevokedBefore = revocation.RevokedBefore
+	}
+	if revocation.ExpiresAt.After(current.ExpiresAt) {
+		current.ExpiresAt = revocation.ExpiresAt
+	}
+	s.users[revocation.UserID] = current
+}
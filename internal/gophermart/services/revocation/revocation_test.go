@@ -0,0 +1,133 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(repo revocationRepo, now *time.Time) *RevocationService {
+	s := NewRevocationService(logger.NewLogger(), repo, time.Hour)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestRevocationService_RevokeToken(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	claims := entity.AccessClaims{ID: "jti", UserID: 1, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	other := entity.AccessClaims{ID: "other", UserID: 1, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("token revoked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRevocationRepository(ctrl)
+		repo.EXPECT().
+			RevokeToken(ctx, entity.RevokedToken{ID: "jti", UserID: 1, ExpiresAt: claims.ExpiresAt}).
+			Return(nil)
+
+		s := newTestService(repo, &now)
+		assert.NoError(t, s.RevokeToken(ctx, claims))
+		assert.True(t, s.IsRevoked(claims))
+		assert.False(t, s.IsRevoked(other))
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockRevocationRepository(ctrl)
+		repo.EXPECT().
+			RevokeToken(ctx, gomock.Any()).
+			Return(errors.New("db error"))
+
+		s := newTestService(repo, &now)
+		assert.Error(t, s.RevokeToken(ctx, claims))
+		assert.False(t, s.IsRevoked(claims))
+	})
+}
+
+func TestRevocationService_RevokeUser(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 700_000_500, time.UTC)
+	revokedBefore := now.Truncate(time.Microsecond)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRevocationRepository(ctrl)
+	repo.EXPECT().
+		RevokeUser(ctx, entity.UserRevocation{UserID: 1, RevokedBefore: revokedBefore, ExpiresAt: now.Add(time.Hour)}).
+		Return(nil)
+
+	s := newTestService(repo, &now)
+	assert.NoError(t, s.RevokeUser(ctx, 1))
+
+	tests := []struct {
+		name    string
+		claims  entity.AccessClaims
+		revoked bool
+	}{
+		{"issued before", entity.AccessClaims{ID: "a", UserID: 1, IssuedAt: now.Add(-time.Minute)}, true},
+		{"issued earlier in the same second", entity.AccessClaims{ID: "e", UserID: 1, IssuedAt: now.Add(-400 * time.Millisecond)}, true},
+		// Токены, выпущенные до перехода на микросекунды, несут iat в целых секундах.
+		{"whole second iat", entity.AccessClaims{ID: "f", UserID: 1, IssuedAt: now.Truncate(time.Second)}, true},
+		{"issued in the same microsecond", entity.AccessClaims{ID: "b", UserID: 1, IssuedAt: revokedBefore}, true},
+		{"issued right after", entity.AccessClaims{ID: "g", UserID: 1, IssuedAt: revokedBefore.Add(time.Microsecond)}, false},
+		{"issued after", entity.AccessClaims{ID: "c", UserID: 1, IssuedAt: now.Add(time.Second)}, false},
+		{"another user", entity.AccessClaims{ID: "d", UserID: 2, IssuedAt: now.Add(-time.Minute)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.revoked, s.IsRevoked(tt.claims))
+		})
+	}
+}
+
+func TestRevocationService_Sync(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRevocationRepository(ctrl)
+	s := newTestService(repo, &now)
+
+	// Отзывы, сделанные другими экземплярами сервиса
+	repo.EXPECT().
+		Active(ctx).
+		Return(
+			[]entity.RevokedToken{{ID: "remote", UserID: 1, ExpiresAt: now.Add(time.Minute)}},
+			[]entity.UserRevocation{{UserID: 2, RevokedBefore: now.Add(500 * time.Millisecond), ExpiresAt: now.Add(time.Hour)}},
+			nil,
+		)
+	assert.NoError(t, s.Sync(ctx))
+	assert.True(t, s.IsRevoked(entity.AccessClaims{ID: "remote", UserID: 1}))
+	assert.True(t, s.IsRevoked(entity.AccessClaims{ID: "any", UserID: 2, IssuedAt: now}))
+	assert.True(t, s.IsRevoked(entity.AccessClaims{ID: "any", UserID: 2, IssuedAt: now.Add(500 * time.Millisecond)}))
+	assert.False(t, s.IsRevoked(entity.AccessClaims{ID: "any", UserID: 2, IssuedAt: now.Add(501 * time.Millisecond)}))
+
+	// Истекшие записи удаляются из кеша
+	now = now.Add(2 * time.Minute)
+	repo.EXPECT().
+		Active(ctx).
+		Return(nil, nil, nil)
+	assert.NoError(t, s.Sync(ctx))
+	assert.False(t, s.IsRevoked(entity.AccessClaims{ID: "remote", UserID: 1}))
+	assert.True(t, s.IsRevoked(entity.AccessClaims{ID: "any", UserID: 2, IssuedAt: now.Add(-time.Hour)}))
+
+	repo.EXPECT().
+		Active(ctx).
+		Return(nil, nil, errors.New("db error"))
+	assert.Error(t, s.Sync(ctx))
+}
@@ -0,0 +1,69 @@
+package role
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+type userRepo interface {
+	FindUserByUsername(ctx context.Context, username string) (entity.User, error)
+	SaveUser(ctx context.Context, user entity.User) (uid int64, err error)
+	UpdateRole(ctx context.Context, userID int64, role entity.Role) error
+}
+
+type passwordHasher interface {
+	Hash(password string) (string, error)
+}
+
+type RoleService struct {
+	log    *logger.Logger
+	users  userRepo
+	hasher passwordHasher
+}
+
+func NewRoleService(log *logger.Logger, users userRepo, hasher passwordHasher) *RoleService {
+	return &RoleService{
+		log:    log,
+		users:  users,
+		hasher: hasher,
+	}
+}
+
+// BootstrapAdmin gives the admin role to the user with the given login. If
+// there is no such user, it is created with password. It reports whether
+// the user has been created.
+func (s *RoleService) BootstrapAdmin(ctx context.Context, login string, password string) (bool, error) {
+	log := s.log.With("op", "RoleService.BootstrapAdmin", "login", login)
+
+	user, err := s.users.FindUserByUsername(ctx, login)
+	if err == nil {
+		if err := s.users.UpdateRole(ctx, user.ID, entity.RoleAdmin); err != nil {
+			log.Error("failed to update role", err)
+			return false, common.ErrInternalError
+		}
+		log.Infow("user promoted to admin", "uid", user.ID, "previous_role", user.Role)
+		return false, nil
+	}
+	if !errors.Is(err, common.ErrUserNotFound) {
+		log.Error("failed to find user", err)
+		return false, common.ErrInternalError
+	}
+
+	passHash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Error("failed to generate password hash", err)
+		return false, common.ErrInternalError
+	}
+	id, err := s.users.SaveUser(ctx, entity.User{Login: login, Password: passHash, Role: entity.RoleAdmin})
+	if err != nil {
+		log.Error("failed to save user", err)
+		return false, common.ErrInternalError
+	}
+	log.Infow("admin created", "uid", id)
+
+	return true, nil
+}
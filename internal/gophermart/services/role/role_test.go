@@ -0,0 +1,84 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MxTrap/gophermart/internal/gophermart/common"
+	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleService_BootstrapAdmin(t *testing.T) {
+	ctx := context.Background()
+	existing := entity.User{ID: 1, Login: "admin", Role: entity.RoleUser}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(users *MockuserRepo, hasher *MockpasswordHasher)
+		created     bool
+		expectedErr error
+	}{
+		{
+			name: "create admin",
+			setupMocks: func(users *MockuserRepo, hasher *MockpasswordHasher) {
+				users.EXPECT().FindUserByUsername(ctx, "admin").Return(entity.User{}, common.ErrUserNotFound)
+				hasher.EXPECT().Hash("password123").Return("hash", nil)
+				users.EXPECT().
+					SaveUser(ctx, entity.User{Login: "admin", Password: "hash", Role: entity.RoleAdmin}).
+					Return(int64(1), nil)
+			},
+			created: true,
+		},
+		{
+			name: "promote existing user",
+			setupMocks: func(users *MockuserRepo, hasher *MockpasswordHasher) {
+				// Пароль существующего пользователя не меняется
+				users.EXPECT().FindUserByUsername(ctx, "admin").Return(existing, nil)
+				users.EXPECT().UpdateRole(ctx, existing.ID, entity.RoleAdmin).Return(nil)
+			},
+		},
+		{
+			name: "find user error",
+			setupMocks: func(users *MockuserRepo, hasher *MockpasswordHasher) {
+				users.EXPECT().FindUserByUsername(ctx, "admin").Return(entity.User{}, errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			name: "update role error",
+			setupMocks: func(users *MockuserRepo, hasher *MockpasswordHasher) {
+				users.EXPECT().FindUserByUsername(ctx, "admin").Return(existing, nil)
+				users.EXPECT().UpdateRole(ctx, existing.ID, entity.RoleAdmin).Return(errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+		{
+			name: "save user error",
+			setupMocks: func(users *MockuserRepo, hasher *MockpasswordHasher) {
+				users.EXPECT().FindUserByUsername(ctx, "admin").Return(entity.User{}, common.ErrUserNotFound)
+				hasher.EXPECT().Hash("password123").Return("hash", nil)
+				users.EXPECT().SaveUser(ctx, gomock.Any()).Return(int64(0), errors.New("db error"))
+			},
+			expectedErr: common.ErrInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := NewMockuserRepo(ctrl)
+			hasher := NewMockpasswordHasher(ctrl)
+			tt.setupMocks(users, hasher)
+
+			created, err := NewRoleService(logger.NewLogger(), users, hasher).BootstrapAdmin(ctx, "admin", "password123")
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.created, created)
+		})
+	}
+}
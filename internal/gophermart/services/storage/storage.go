@@ -1,35 +1,143 @@
 package storage
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand/v2"
+	"os"
+	"time"
+
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/logger"
+)
+
+// DefaultPollDelay is the delay between polls of an order which is still
+// being processed by the accrual system.
+const DefaultPollDelay = 5 * time.Second
+
+const (
+	baseRetryDelay = 5 * time.Second
+	maxRetryDelay  = time.Hour
+	leaseDuration  = time.Minute
 )
 
+type queueRepo interface {
+	Pull(ctx context.Context, owner string, limit int, lease time.Duration) ([]entity.Order, error)
+	Reschedule(ctx context.Context, owner string, number string, delay time.Duration) error
+	Release(ctx context.Context, owner string, number string, delay time.Duration) error
+	Retry(ctx context.Context, owner string, number string, reason string, delay time.Duration) error
+	DeadLetter(ctx context.Context, owner string, number string, reason string) error
+	DeadLetters(ctx context.Context) ([]entity.DeadLetter, error)
+	Requeue(ctx context.Context, number string) error
+	Postpone(ctx context.Context, number string, delay time.Duration) error
+}
+
+// Storage is the order processing queue. It is persisted in Postgres and
+// shared by all instances of the service: every taken order is leased to
+// this instance, so an order is polled by one worker at a time.
 type Storage struct {
-	queue *[]entity.Order
+	log         *logger.Logger
+	repo        queueRepo
+	owner       string
+	maxAttempts int
+	pollDelay   time.Duration
 }
 
-func NewStorageService() *Storage {
+func NewStorageService(log *logger.Logger, repo queueRepo, maxAttempts int, pollDelay time.Duration) *Storage {
 	return &Storage{
-		queue: &[]entity.Order{},
+		log:         log,
+		repo:        repo,
+		owner:       leaseOwner(),
+		maxAttempts: maxAttempts,
+		pollDelay:   pollDelay,
 	}
 }
 
-func (s *Storage) Push(el entity.Order) {
-	*s.queue = append(*s.queue, el)
+// leaseOwner identifies this instance among the replicas sharing the queue.
+func leaseOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Push releases the lease on an order which is still being processed by the
+// accrual system and returns it to the queue to be polled again after the
+// poll delay. A later poll, set by a callback during the lease, is kept.
+func (s *Storage) Push(ctx context.Context, el entity.Order) error {
+	return s.repo.Reschedule(ctx, s.owner, el.Number, s.pollDelay)
+}
+
+// Release returns an order which has not been polled, e.g. because the
+// accrual system throttles requests, to the queue after the poll delay. It
+// does not count as an attempt, neither failed nor successful.
+func (s *Storage) Release(ctx context.Context, el entity.Order) error {
+	return s.repo.Release(ctx, s.owner, el.Number, s.pollDelay)
 }
 
-func (s *Storage) Get(elemCount int) []entity.Order {
-	self := *s.queue
-	var el []entity.Order
-	if len(self) == 0 {
-		return el
+// Fail returns an order to the queue after a failed attempt. The next attempt
+// is delayed exponentially; after maxAttempts failures in a row the order is
+// dead-lettered and is not polled until it is requeued.
+func (s *Storage) Fail(ctx context.Context, el entity.Order, cause error) error {
+	if el.Attempts+1 >= s.maxAttempts {
+		s.log.Warnw("order dead-lettered", "number", el.Number, "attempts", el.Attempts+1, "error", cause)
+		return s.repo.DeadLetter(ctx, s.owner, el.Number, cause.Error())
 	}
-	if len(self) > elemCount {
-		el, *s.queue = self[:elemCount], self[elemCount:]
-		return el
+	return s.repo.Retry(ctx, s.owner, el.Number, cause.Error(), s.backoff(el.Attempts))
+}
+
+// backoff returns the delay before the next attempt: the exponentially grown
+// delay with "equal jitter", i.e. its random upper half.
+func (*Storage) backoff(attempts int) time.Duration {
+	delay := maxRetryDelay
+	if attempts < 32 {
+		if d := baseRetryDelay << attempts; d > 0 && d < maxRetryDelay {
+			delay = d
+		}
 	}
+	half := delay / 2
+
+	return half + mathrand.N(half+1)
+}
 
-	el, *s.queue = *s.queue, []entity.Order{}
+// Get leases up to elemCount due orders from the queue. Leased orders that
+// are not pushed back or completed become due again once the lease expires,
+// e.g. when the instance crashes.
+func (s *Storage) Get(ctx context.Context, elemCount int) ([]entity.Order, error) {
+	return s.repo.Pull(ctx, s.owner, elemCount, leaseDuration)
+}
 
-	return el
+func (s *Storage) DeadLetters(ctx context.Context) ([]entity.DeadLetter, error) {
+	log := s.log.With("op", "Storage.DeadLetters")
+	deadLetters, err := s.repo.DeadLetters(ctx)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return deadLetters, nil
+}
+
+// Postpone delays the next poll of a pending order, e.g. after its status
+// has been reported by a callback of the accrual system.
+func (s *Storage) Postpone(ctx context.Context, number string, delay time.Duration) error {
+	return s.repo.Postpone(ctx, number, delay)
+}
+
+// Requeue makes a pending order, e.g. a dead-lettered one, due right away
+// with a fresh attempt counter, so that the accrual system is polled again.
+func (s *Storage) Requeue(ctx context.Context, number string) error {
+	log := s.log.With("op", "Storage.Requeue", "number", number)
+	err := s.repo.Requeue(ctx, number)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	log.Info("order requeued")
+	return nil
 }
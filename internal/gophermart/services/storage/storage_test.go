@@ -1,118 +1,295 @@
 package storage
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/MxTrap/gophermart/internal/gophermart/entity"
+	"github.com/MxTrap/gophermart/internal/gophermart/mocks"
+	"github.com/MxTrap/gophermart/logger"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestStorage_Push(t *testing.T) {
+	ctx := context.Background()
+
 	tests := []struct {
-		name          string
-		orders        []entity.Order
-		expectedQueue []entity.Order
+		name        string
+		order       entity.Order
+		repoErr     error
+		expectedErr error
 	}{
 		{
-			name:          "Push single order",
-			orders:        []entity.Order{{UserID: 1, Number: "123"}},
-			expectedQueue: []entity.Order{{UserID: 1, Number: "123"}},
+			name:  "Push order",
+			order: entity.Order{UserID: 1, Number: "123"},
 		},
 		{
-			name: "Push multiple orders",
-			orders: []entity.Order{
-				{UserID: 1, Number: "123"},
-				{UserID: 2, Number: "456"},
-			},
-			expectedQueue: []entity.Order{
-				{UserID: 1, Number: "123"},
-				{UserID: 2, Number: "456"},
-			},
+			name:        "Repository error",
+			order:       entity.Order{UserID: 2, Number: "456"},
+			repoErr:     errors.New("db error"),
+			expectedErr: errors.New("db error"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Создаем новый Storage
-			s := NewStorageService()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 
-			// Добавляем заказы
-			for _, order := range tt.orders {
-				s.Push(order)
-			}
+			repo := mocks.NewMockQueueRepository(ctrl)
+			s := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+			repo.EXPECT().
+				Reschedule(ctx, s.owner, tt.order.Number, DefaultPollDelay).
+				Return(tt.repoErr)
+
+			err := s.Push(ctx, tt.order)
 
-			// Проверяем содержимое очереди
-			assert.Equal(t, tt.expectedQueue, *s.queue, "queue content mismatch")
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
 		})
 	}
 }
 
+func TestStorage_Push_callbackDelay(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// With accrual callbacks a polled order waits for its callback as long
+	// as a new one, instead of being polled again in DefaultPollDelay.
+	repo := mocks.NewMockQueueRepository(ctrl)
+	s := NewStorageService(logger.NewLogger(), repo, 3, 10*time.Minute)
+	repo.EXPECT().Reschedule(ctx, s.owner, "123", 10*time.Minute).Return(nil)
+
+	assert.NoError(t, s.Push(ctx, entity.Order{Number: "123"}))
+}
+
+func TestStorage_Release(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockQueueRepository(ctrl)
+	s := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+
+	repo.EXPECT().Release(ctx, s.owner, "123", DefaultPollDelay).Return(nil)
+	assert.NoError(t, s.Release(ctx, entity.Order{Number: "123", Attempts: 2}))
+
+	dbErr := errors.New("db error")
+	repo.EXPECT().Release(ctx, s.owner, "456", DefaultPollDelay).Return(dbErr)
+	assert.ErrorIs(t, s.Release(ctx, entity.Order{Number: "456"}), dbErr)
+}
+
+func TestStorage_Postpone(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockQueueRepository(ctrl)
+	s := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+
+	repo.EXPECT().Postpone(ctx, "123", time.Minute).Return(nil)
+	assert.NoError(t, s.Postpone(ctx, "123", time.Minute))
+
+	dbErr := errors.New("db error")
+	repo.EXPECT().Postpone(ctx, "456", time.Minute).Return(dbErr)
+	assert.ErrorIs(t, s.Postpone(ctx, "456", time.Minute), dbErr)
+}
+
 func TestStorage_Get(t *testing.T) {
+	ctx := context.Background()
+
 	tests := []struct {
 		name           string
-		initialQueue   []entity.Order
 		elemCount      int
+		repoOrders     []entity.Order
+		repoErr        error
 		expectedResult []entity.Order
-		expectedQueue  []entity.Order
+		expectedErr    error
 	}{
 		{
-			name:           "Test empty queue",
-			initialQueue:   []entity.Order{},
+			name:           "Empty queue",
 			elemCount:      1,
 			expectedResult: nil,
-			expectedQueue:  []entity.Order{},
 		},
 		{
-			name: "Test get all elements (exact count)",
-			initialQueue: []entity.Order{
+			name:      "Due orders",
+			elemCount: 2,
+			repoOrders: []entity.Order{
 				{UserID: 1, Number: "123"},
 				{UserID: 2, Number: "456"},
 			},
-			elemCount: 2,
 			expectedResult: []entity.Order{
 				{UserID: 1, Number: "123"},
 				{UserID: 2, Number: "456"},
 			},
-			expectedQueue: []entity.Order{},
 		},
 		{
-			name: "Test get all elements (less than requested)",
-			initialQueue: []entity.Order{
-				{UserID: 1, Number: "123"},
-			},
-			elemCount: 2,
-			expectedResult: []entity.Order{
-				{UserID: 1, Number: "123"},
-			},
-			expectedQueue: []entity.Order{},
+			name:        "Repository error",
+			elemCount:   2,
+			repoErr:     errors.New("db error"),
+			expectedErr: errors.New("db error"),
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockQueueRepository(ctrl)
+			s := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+			repo.EXPECT().
+				Pull(ctx, s.owner, tt.elemCount, leaseDuration).
+				Return(tt.repoOrders, tt.repoErr)
+
+			result, err := s.Get(ctx, tt.elemCount)
+
+			assert.Equal(t, tt.expectedResult, result, "result mismatch")
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewStorageService_owner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockQueueRepository(ctrl)
+	first := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+	second := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+
+	assert.NotEmpty(t, first.owner)
+	assert.NotEqual(t, first.owner, second.owner, "every instance must hold its own leases")
+}
+
+func TestStorage_Fail(t *testing.T) {
+	ctx := context.Background()
+	cause := errors.New("accrual error")
+
+	t.Run("retry with backoff", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockQueueRepository(ctrl)
+		s := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+		repo.EXPECT().
+			Retry(ctx, s.owner, "123", cause.Error(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _, _ string, delay time.Duration) error {
+				assert.GreaterOrEqual(t, delay, baseRetryDelay)
+				assert.LessOrEqual(t, delay, 2*baseRetryDelay)
+				return nil
+			})
+
+		err := s.Fail(ctx, entity.Order{Number: "123", Attempts: 1}, cause)
+		assert.NoError(t, err)
+	})
+
+	t.Run("dead letter after max attempts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mocks.NewMockQueueRepository(ctrl)
+		s := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+		repo.EXPECT().
+			DeadLetter(ctx, s.owner, "123", cause.Error()).
+			Return(nil)
+
+		err := s.Fail(ctx, entity.Order{Number: "123", Attempts: 2}, cause)
+		assert.NoError(t, err)
+	})
+}
+
+func TestStorage_backoff(t *testing.T) {
+	s := &Storage{}
+
+	tests := []struct {
+		name     string
+		attempts int
+		min      time.Duration
+		max      time.Duration
+	}{
+		{"first attempt", 0, baseRetryDelay / 2, baseRetryDelay},
+		{"third attempt", 2, 2 * baseRetryDelay, 4 * baseRetryDelay},
+		{"capped", 20, maxRetryDelay / 2, maxRetryDelay},
+		{"overflow", 100, maxRetryDelay / 2, maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				delay := s.backoff(tt.attempts)
+				assert.GreaterOrEqual(t, delay, tt.min)
+				assert.LessOrEqual(t, delay, tt.max)
+			}
+		})
+	}
+}
+
+func TestStorage_DeadLetters(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deadLetters := []entity.DeadLetter{
+		{Order: entity.Order{Number: "123", Attempts: 3}, LastError: "order does not exist"},
+	}
+
+	repo := mocks.NewMockQueueRepository(ctrl)
+	repo.EXPECT().
+		DeadLetters(ctx).
+		Return(deadLetters, nil)
+
+	s := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+	result, err := s.DeadLetters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, deadLetters, result)
+}
+
+func TestStorage_Requeue(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{name: "Requeue order"},
 		{
-			name: "Test get partial elements",
-			initialQueue: []entity.Order{
-				{UserID: 1, Number: "123"},
-				{UserID: 2, Number: "456"},
-				{UserID: 3, Number: "789"},
-			},
-			elemCount: 2,
-			expectedResult: []entity.Order{
-				{UserID: 1, Number: "123"},
-				{UserID: 2, Number: "456"},
-			},
-			expectedQueue: []entity.Order{
-				{UserID: 3, Number: "789"},
-			},
+			name:        "Repository error",
+			repoErr:     errors.New("db error"),
+			expectedErr: errors.New("db error"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := NewStorageService()
-			*s.queue = tt.initialQueue
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 
-			result := s.Get(tt.elemCount)
+			repo := mocks.NewMockQueueRepository(ctrl)
+			repo.EXPECT().
+				Requeue(ctx, "123").
+				Return(tt.repoErr)
 
-			assert.Equal(t, tt.expectedResult, result, "result mismatch")
-			assert.Equal(t, tt.expectedQueue, *s.queue, "queue content mismatch")
+			s := NewStorageService(logger.NewLogger(), repo, 3, DefaultPollDelay)
+			err := s.Requeue(ctx, "123")
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
 		})
 	}
 }
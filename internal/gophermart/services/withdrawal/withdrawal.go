@@ -37,6 +37,9 @@ func (s *WithdrawalService) Withdraw(ctx context.Context, userID int64, withdraw
 	if !utils.IsOrderNumberValid(withdrawal.Order) {
 		return common.ErrInvalidOrderNumber
 	}
+	if withdrawal.Sum <= 0 {
+		return common.ErrInvalidAmount
+	}
 
 	withdrawal.ProcessedAt = time.Now().UTC()
 	err := s.withdrawer.Withdraw(ctx, userID, withdrawal)
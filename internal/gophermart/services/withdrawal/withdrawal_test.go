@@ -20,11 +20,11 @@ func TestWithdrawalService_Withdraw(t *testing.T) {
 	userID := int64(1)
 	withdrawal := entity.Withdrawal{
 		Order: "12345674",
-		Sum:   100.0,
+		Sum:   10000,
 	}
 	validWithdrawal := entity.Withdrawal{
 		Order:       "12345674",
-		Sum:         100.0,
+		Sum:         10000,
 		ProcessedAt: time.Now().UTC(),
 	}
 
@@ -54,12 +54,21 @@ func TestWithdrawalService_Withdraw(t *testing.T) {
 		{
 			name:       "Invalid order number",
 			userID:     userID,
-			withdrawal: entity.Withdrawal{Order: "", Sum: 100.0},
+			withdrawal: entity.Withdrawal{Order: "", Sum: 10000},
 			setupMock: func(withdrawer *mocks.MockBalanceWithdrawalRepository) {
 
 			},
 			expectedErr: common.ErrInvalidOrderNumber,
 		},
+		{
+			name:       "Non-positive sum",
+			userID:     userID,
+			withdrawal: entity.Withdrawal{Order: "12345674", Sum: -10000},
+			setupMock: func(withdrawer *mocks.MockBalanceWithdrawalRepository) {
+
+			},
+			expectedErr: common.ErrInvalidAmount,
+		},
 		{
 			name:       "Withdraw error",
 			userID:     userID,
@@ -114,13 +123,13 @@ func TestWithdrawalService_GetAll(t *testing.T) {
 				getter.EXPECT().
 					GetAll(ctx, userID).
 					Return([]entity.Withdrawal{
-						{Order: "12345", Sum: 100.0, ProcessedAt: time.Now().UTC()},
-						{Order: "67890", Sum: 50.0, ProcessedAt: time.Now().UTC()},
+						{Order: "12345", Sum: 10000, ProcessedAt: time.Now().UTC()},
+						{Order: "67890", Sum: 5000, ProcessedAt: time.Now().UTC()},
 					}, nil)
 			},
 			expectedWithdrawals: []entity.Withdrawal{
-				{Order: "12345", Sum: 100.0, ProcessedAt: time.Now().UTC()},
-				{Order: "67890", Sum: 50.0, ProcessedAt: time.Now().UTC()},
+				{Order: "12345", Sum: 10000, ProcessedAt: time.Now().UTC()},
+				{Order: "67890", Sum: 5000, ProcessedAt: time.Now().UTC()},
 			},
 			expectedErr: nil,
 		},
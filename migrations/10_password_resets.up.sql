@@ -0,0 +1,17 @@
+BEGIN TRANSACTION;
+
+-- Password reset tokens are sent to the user and only their SHA-256 hash is
+-- stored. A token is used once and expires after a short time.
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMPTZ,
+    CONSTRAINT fk_password_reset_tokens_users FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user ON password_reset_tokens (user_id);
+
+COMMIT TRANSACTION;
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'user'
+    CONSTRAINT chk_users_role CHECK (role IN ('user', 'support', 'admin'));
+
+COMMIT TRANSACTION;
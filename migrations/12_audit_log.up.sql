@@ -0,0 +1,18 @@
+BEGIN TRANSACTION;
+
+-- Operations of support staff on users, orders and balances: who did what to
+-- which target and why. Entries are never updated or deleted.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    target TEXT NOT NULL,
+    amount NUMERIC(20, 2),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_audit_log_users FOREIGN KEY (actor_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+
+COMMIT TRANSACTION;
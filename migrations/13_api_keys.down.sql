@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE users DROP COLUMN IF EXISTS external_id;
+
+DROP TABLE IF EXISTS api_keys;
+
+COMMIT TRANSACTION;
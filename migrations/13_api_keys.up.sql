@@ -0,0 +1,23 @@
+BEGIN TRANSACTION;
+
+-- API keys of partner systems, e.g. points of sale registering orders on
+-- behalf of users. Only the SHA-256 hash of a key is stored, the prefix tells
+-- the keys apart in listings. Revoked keys are kept for the audit log.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    created_by INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ,
+    CONSTRAINT fk_api_keys_users FOREIGN KEY (created_by) REFERENCES users (id)
+);
+
+-- Partners may identify a user by the id in their own system.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS external_id VARCHAR(64)
+    CONSTRAINT uq_users_external_id UNIQUE;
+
+COMMIT TRANSACTION;
@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+-- Secret for HMAC request signing. Unlike the key it is stored as is: the
+-- server needs it to verify signatures. Keys created before have none.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS signing_secret VARCHAR(64);
+
+COMMIT TRANSACTION;
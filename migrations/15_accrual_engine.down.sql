@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+DROP TABLE IF EXISTS accrual_orders;
+
+DROP TABLE IF EXISTS accrual_rules;
+
+COMMIT TRANSACTION;
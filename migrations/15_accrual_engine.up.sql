@@ -0,0 +1,21 @@
+BEGIN TRANSACTION;
+
+-- Reward rules and orders of the built-in accrual engine, used instead of
+-- the external accrual system when it is enabled. The accrual of an order is
+-- calculated once, when the order is registered.
+CREATE TABLE IF NOT EXISTS accrual_rules (
+    id BIGSERIAL PRIMARY KEY,
+    match VARCHAR(255) NOT NULL UNIQUE,
+    reward NUMERIC(20, 2) NOT NULL CHECK (reward > 0),
+    reward_type VARCHAR(2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS accrual_orders (
+    number VARCHAR(255) PRIMARY KEY,
+    goods JSONB NOT NULL,
+    accrual NUMERIC(20, 2) NOT NULL,
+    registered_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT TRANSACTION;
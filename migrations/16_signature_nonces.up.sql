@@ -0,0 +1,15 @@
+BEGIN TRANSACTION;
+
+-- Nonces of accepted signed requests, shared by all replicas, so that a
+-- signed request is accepted once. A nonce is kept until the timestamp of its
+-- request leaves the allowed skew.
+CREATE TABLE IF NOT EXISTS signature_nonces (
+    key_id BIGINT NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+    nonce VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key_id, nonce)
+);
+
+CREATE INDEX IF NOT EXISTS idx_signature_nonces_expires_at ON signature_nonces (expires_at);
+
+COMMIT TRANSACTION;
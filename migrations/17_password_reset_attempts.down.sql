@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+DELETE FROM login_attempts WHERE scope IN ('RESET_LOGIN', 'RESET_IP');
+ALTER TABLE login_attempts DROP CONSTRAINT IF EXISTS chk_login_attempts_scope;
+ALTER TABLE login_attempts ADD CONSTRAINT chk_login_attempts_scope CHECK (scope IN ('LOGIN', 'IP'));
+
+COMMIT TRANSACTION;
@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+-- Password reset requests are throttled per login and per client IP apart
+-- from login attempts, so that requesting resets for a login does not lock
+-- its owner out.
+ALTER TABLE login_attempts DROP CONSTRAINT IF EXISTS chk_login_attempts_scope;
+ALTER TABLE login_attempts ADD CONSTRAINT chk_login_attempts_scope
+    CHECK (scope IN ('LOGIN', 'IP', 'RESET_LOGIN', 'RESET_IP'));
+
+COMMIT TRANSACTION;
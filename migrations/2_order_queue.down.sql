@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_next_attempt_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS next_attempt_at, DROP COLUMN IF EXISTS attempts;
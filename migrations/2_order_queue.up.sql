@@ -0,0 +1,14 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+
+UPDATE orders
+SET next_attempt_at = NOW()
+WHERE status_id IN (SELECT id FROM order_statuses WHERE status IN ('NEW', 'PROCESSING'));
+
+CREATE INDEX IF NOT EXISTS idx_orders_next_attempt_at ON orders (next_attempt_at)
+WHERE next_attempt_at IS NOT NULL;
+
+COMMIT TRANSACTION;
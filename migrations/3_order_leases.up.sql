@@ -0,0 +1,7 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS locked_by TEXT,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+COMMIT TRANSACTION;
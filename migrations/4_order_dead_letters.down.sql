@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_dead_lettered_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS last_error, DROP COLUMN IF EXISTS dead_lettered_at;
@@ -0,0 +1,10 @@
+BEGIN TRANSACTION;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS last_error TEXT,
+    ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_orders_dead_lettered_at ON orders (dead_lettered_at)
+WHERE dead_lettered_at IS NOT NULL;
+
+COMMIT TRANSACTION;
@@ -0,0 +1 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS fk_orders_order_statuses;
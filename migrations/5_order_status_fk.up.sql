@@ -0,0 +1,9 @@
+BEGIN TRANSACTION;
+
+-- The initial migration referenced order_statuses from orders.user_id by
+-- mistake, the status itself was not constrained.
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS fk_orders_order_statuses;
+ALTER TABLE orders
+    ADD CONSTRAINT fk_orders_order_statuses FOREIGN KEY (status_id) REFERENCES order_statuses (id);
+
+COMMIT TRANSACTION;
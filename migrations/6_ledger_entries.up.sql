@@ -0,0 +1,49 @@
+BEGIN TRANSACTION;
+
+-- Every change of a user's balance is recorded as an entry: a positive amount
+-- is a credit, a negative one is a debit. users.balance and users.withdrawn
+-- are a snapshot of the entries maintained in the same transaction.
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(15) NOT NULL,
+    amount NUMERIC(20, 2) NOT NULL,
+    order_id BIGINT,
+    withdrawal_id INT,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_ledger_entries_users FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_ledger_entries_orders FOREIGN KEY (order_id) REFERENCES orders (id),
+    CONSTRAINT fk_ledger_entries_withdrawals FOREIGN KEY (withdrawal_id) REFERENCES withdrawals (id),
+    CONSTRAINT chk_ledger_entries_type CHECK (
+        (type = 'ACCRUAL' AND order_id IS NOT NULL AND amount >= 0)
+        OR (type = 'WITHDRAWAL' AND withdrawal_id IS NOT NULL AND amount <= 0)
+        OR (type = 'ADJUSTMENT' AND reason IS NOT NULL)
+    )
+);
+
+-- An order is credited and a withdrawal is debited at most once.
+CREATE UNIQUE INDEX IF NOT EXISTS ux_ledger_entries_order
+    ON ledger_entries (order_id) WHERE type = 'ACCRUAL';
+CREATE UNIQUE INDEX IF NOT EXISTS ux_ledger_entries_withdrawal
+    ON ledger_entries (withdrawal_id) WHERE type = 'WITHDRAWAL';
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user ON ledger_entries (user_id, created_at);
+
+-- Opening entries: accruals of processed orders and withdrawals made so far,
+INSERT INTO ledger_entries (user_id, type, amount, order_id, created_at)
+SELECT o.user_id, 'ACCRUAL', o.accrual, o.id, COALESCE(o.uploaded_at, NOW())
+FROM orders AS o JOIN order_statuses AS s ON s.id = o.status_id
+WHERE s.status = 'PROCESSED' AND o.accrual > 0;
+
+INSERT INTO ledger_entries (user_id, type, amount, withdrawal_id, created_at)
+SELECT user_id, 'WITHDRAWAL', -sum, id, COALESCE(processed_at, NOW())
+FROM withdrawals;
+
+-- and an adjustment for the part of the stored balance they do not explain.
+INSERT INTO ledger_entries (user_id, type, amount, reason)
+SELECT u.id, 'ADJUSTMENT', u.balance - COALESCE(SUM(l.amount), 0), 'opening balance'
+FROM users AS u LEFT JOIN ledger_entries AS l ON l.user_id = u.id
+GROUP BY u.id, u.balance
+HAVING u.balance <> COALESCE(SUM(l.amount), 0);
+
+COMMIT TRANSACTION;
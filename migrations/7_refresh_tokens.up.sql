@@ -0,0 +1,20 @@
+BEGIN TRANSACTION;
+
+-- Refresh tokens are opaque, only their SHA-256 hash is stored. A token is
+-- used once: refreshing marks it rotated and issues the next token of the
+-- same family. Presenting a rotated token again revokes the whole family.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    family_id VARCHAR(32) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    rotated_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    CONSTRAINT fk_refresh_tokens_users FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens (family_id);
+
+COMMIT TRANSACTION;
@@ -0,0 +1,23 @@
+BEGIN TRANSACTION;
+
+-- Access tokens revoked on logout. An entry is needed only until the token
+-- expires and is removed afterwards.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(32) PRIMARY KEY,
+    user_id INT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_revoked_tokens_users FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+-- "Log out everywhere": every access token of the user issued before
+-- revoked_before is rejected.
+CREATE TABLE IF NOT EXISTS user_token_revocations (
+    user_id INT PRIMARY KEY,
+    revoked_before TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_user_token_revocations_users FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+COMMIT TRANSACTION;
@@ -0,0 +1,17 @@
+BEGIN TRANSACTION;
+
+-- Failed login attempts, counted per login and per client IP, so that all
+-- replicas throttle the same attacker.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    scope VARCHAR(10) NOT NULL,
+    key TEXT NOT NULL,
+    failures INT NOT NULL,
+    last_failure_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ,
+    PRIMARY KEY (scope, key),
+    CONSTRAINT chk_login_attempts_scope CHECK (scope IN ('LOGIN', 'IP'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failure_at ON login_attempts (last_failure_at);
+
+COMMIT TRANSACTION;
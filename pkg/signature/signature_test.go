@@ -0,0 +1,158 @@
+package signature
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type vector struct {
+	Name       string `json:"name"`
+	Secret     string `json:"secret"`
+	Method     string `json:"method"`
+	RequestURI string `json:"request_uri"`
+	Body       string `json:"body"`
+	Timestamp  int64  `json:"timestamp"`
+	Nonce      string `json:"nonce"`
+	Canonical  string `json:"canonical"`
+	Signature  string `json:"signature"`
+}
+
+func loadVectors(t *testing.T) []vector {
+	data, err := os.ReadFile("testdata/vectors.json")
+	assert.NoError(t, err)
+	var vectors []vector
+	assert.NoError(t, json.Unmarshal(data, &vectors))
+	assert.NotEmpty(t, vectors)
+	return vectors
+}
+
+func TestSign_vectors(t *testing.T) {
+	for _, v := range loadVectors(t) {
+		t.Run(v.Name, func(t *testing.T) {
+			body := []byte(v.Body)
+
+			assert.Equal(t, v.Canonical, CanonicalString(v.Method, v.RequestURI, body, v.Timestamp, v.Nonce))
+			assert.Equal(t, v.Signature, Sign([]byte(v.Secret), v.Method, v.RequestURI, body, v.Timestamp, v.Nonce))
+
+			h := Headers{Signature: v.Signature, Timestamp: v.Timestamp, Nonce: v.Nonce}
+			assert.NoError(t, Verify([]byte(v.Secret), v.Method, v.RequestURI, body, h))
+		})
+	}
+}
+
+func TestVerify_tampered(t *testing.T) {
+	v := loadVectors(t)[0]
+	secret, body := []byte(v.Secret), []byte(v.Body)
+	h := Headers{Signature: v.Signature, Timestamp: v.Timestamp, Nonce: v.Nonce}
+
+	tests := []struct {
+		name   string
+		verify func() error
+	}{
+		{"secret", func() error { return Verify([]byte("wrong"), v.Method, v.RequestURI, body, h) }},
+		{"method", func() error { return Verify(secret, http.MethodPut, v.RequestURI, body, h) }},
+		{"path", func() error { return Verify(secret, v.Method, v.RequestURI+"?x=1", body, h) }},
+		{"body", func() error { return Verify(secret, v.Method, v.RequestURI, append(body, ' '), h) }},
+		{"timestamp", func() error {
+			h := h
+			h.Timestamp++
+			return Verify(secret, v.Method, v.RequestURI, body, h)
+		}},
+		{"nonce", func() error {
+			h := h
+			h.Nonce = "n0nce-0123456789abcdeF"
+			return Verify(secret, v.Method, v.RequestURI, body, h)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.verify(), ErrMismatch)
+		})
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	v := loadVectors(t)[0]
+	req := httptest.NewRequest(v.Method, v.RequestURI, strings.NewReader(v.Body))
+
+	err := SignRequest(req, []byte(v.Secret), v.Nonce, time.Unix(v.Timestamp, 0))
+
+	assert.NoError(t, err)
+	assert.Equal(t, v.Signature, req.Header.Get(HeaderSignature))
+	assert.Equal(t, strconv.FormatInt(v.Timestamp, 10), req.Header.Get(HeaderTimestamp))
+	assert.Equal(t, v.Nonce, req.Header.Get(HeaderNonce))
+	// Тело запроса можно прочитать ещё раз
+	body, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, v.Body, string(body))
+}
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			headers: map[string]string{
+				HeaderSignature: "v1=00", HeaderTimestamp: "1735787045", HeaderNonce: "n0nce-0123456789abcdef",
+			},
+		},
+		{name: "not signed", headers: map[string]string{}, expectedErr: ErrMissing},
+		{
+			name: "malformed timestamp",
+			headers: map[string]string{
+				HeaderSignature: "v1=00", HeaderTimestamp: "2025-01-02", HeaderNonce: "n0nce-0123456789abcdef",
+			},
+			expectedErr: ErrMalformed,
+		},
+		{
+			name: "short nonce",
+			headers: map[string]string{
+				HeaderSignature: "v1=00", HeaderTimestamp: "1735787045", HeaderNonce: "n0nce",
+			},
+			expectedErr: ErrMalformed,
+		},
+		{
+			name: "unknown version",
+			headers: map[string]string{
+				HeaderSignature: "v2=00", HeaderTimestamp: "1735787045", HeaderNonce: "n0nce-0123456789abcdef",
+			},
+			expectedErr: ErrMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.headers {
+				header.Set(k, v)
+			}
+
+			_, err := ParseHeaders(header)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestCheckTimestamp(t *testing.T) {
+	now := time.Unix(1735787045, 0)
+
+	assert.NoError(t, CheckTimestamp(now.Unix(), now, 5*time.Minute))
+	assert.NoError(t, CheckTimestamp(now.Add(-5*time.Minute).Unix(), now, 5*time.Minute))
+	assert.NoError(t, CheckTimestamp(now.Add(time.Minute).Unix(), now, 5*time.Minute))
+	assert.ErrorIs(t, CheckTimestamp(now.Add(-6*time.Minute).Unix(), now, 5*time.Minute), ErrStale)
+	assert.ErrorIs(t, CheckTimestamp(now.Add(6*time.Minute).Unix(), now, 5*time.Minute), ErrStale)
+}